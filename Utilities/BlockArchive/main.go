@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+	"github.com/FactomProject/factomd/database/securedb"
+	"github.com/FactomProject/factomd/database/snapshot"
+	"github.com/FactomProject/factomd/state"
+)
+
+func main() {
+	var (
+		from    = flag.Uint("from", 1, "First height written to the archive")
+		to      = flag.Int("to", -1, "Last height written to the archive, the one below the head by default")
+		keyFile = flag.String("keyfile", "", "File holding the passphrase of an encrypted database, $"+securedb.PassphraseEnv+" is used if not set")
+	)
+	flag.Parse()
+
+	fmt.Fprintln(os.Stderr, "Usage:")
+	fmt.Fprintln(os.Stderr, "BlockArchive [-from 1] [-to height] [-keyfile passphrase.txt] LDB|Bolt|Badger DatabaseLocation ArchiveFile")
+	fmt.Fprintln(os.Stderr, "Writes the blocks, entries and signatures of each height to a block archive,")
+	fmt.Fprintln(os.Stderr, "that factomd -importarchive ArchiveFile syncs from.")
+
+	if len(flag.Args()) < 3 {
+		fmt.Fprintln(os.Stderr, "\nNot enough arguments passed")
+		os.Exit(1)
+	}
+	if len(flag.Args()) > 3 {
+		fmt.Fprintln(os.Stderr, "\nToo many arguments passed")
+		os.Exit(1)
+	}
+
+	db, err := snapshot.OpenDatabaseWithKey(flag.Args()[0], flag.Args()[1], *keyFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "\n%v\n", err)
+		os.Exit(1)
+	}
+	dbo := databaseOverlay.NewOverlay(db)
+	defer dbo.Close()
+
+	last := uint32(*to)
+	if *to < 0 {
+		// The signatures of a height are in the admin block of the next one
+		head, err := dbo.FetchDBlockHead()
+		if err != nil || head == nil || head.GetDatabaseHeight() == 0 {
+			fmt.Fprintf(os.Stderr, "\nNo signed heights in the database %v\n", err)
+			dbo.Close()
+			os.Exit(1)
+		}
+		last = head.GetDatabaseHeight() - 1
+	}
+
+	if err := export(dbo, flag.Args()[2], uint32(*from), last); err != nil {
+		fmt.Fprintf(os.Stderr, "\nExport failed - %v\n", err)
+		dbo.Close()
+		os.Exit(1)
+	}
+	fmt.Printf("Exported heights %d to %d to %v\n", *from, last, flag.Args()[2])
+}
+
+// export writes the archive next to the file and moves it in place once it is complete
+func export(dbo *databaseOverlay.Overlay, filename string, from uint32, to uint32) error {
+	f, err := os.Create(filename + ".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(filename + ".tmp")
+	defer f.Close()
+
+	w := bufio.NewWriterSize(f, 1<<20)
+	var shown time.Time
+	err = state.ExportArchive(dbo, w, from, to, func(h uint32) {
+		if h < to && time.Since(shown) < 5*time.Second {
+			return
+		}
+		shown = time.Now()
+		fmt.Fprintf(os.Stderr, "Exported height %d of %d\n", h, to)
+	})
+	if err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(filename+".tmp", filename)
+}
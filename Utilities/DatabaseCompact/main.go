@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/FactomProject/factomd/database/compact"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+	"github.com/FactomProject/factomd/database/securedb"
+	"github.com/FactomProject/factomd/database/snapshot"
+	"github.com/FactomProject/factomd/util"
+)
+
+func main() {
+	var (
+		rebuild = flag.String("rebuild", "", "Comma separated buckets to drop and rebuild from the blocks: "+strings.Join(compact.RebuildableBuckets(), ", "))
+		asJSON  = flag.Bool("json", false, "Print the report as JSON")
+		config  = flag.String("config", "", "factomd.conf to read the anchor record keys from, for rebuilding DirBlockInfo")
+		keyFile = flag.String("keyfile", "", "File holding the passphrase of an encrypted database, $"+securedb.PassphraseEnv+" is used if not set")
+	)
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage:")
+		fmt.Fprintln(os.Stderr, "DatabaseCompact [-rebuild IncludedIn,PaidFor,DirBlockInfo] [-keyfile passphrase.txt] [-json] LDB|Bolt|Badger DatabaseLocation")
+		fmt.Fprintln(os.Stderr, "The node must be stopped. A running node using LDB or Badger is compacted, without rebuilding, with the compact-database debug API call")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if len(flag.Args()) < 2 {
+		fmt.Fprintln(os.Stderr, "Not enough arguments passed")
+		flag.Usage()
+		os.Exit(1)
+	}
+	if len(flag.Args()) > 2 {
+		fmt.Fprintln(os.Stderr, "Too many arguments passed")
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	var buckets []string
+	if *rebuild != "" {
+		buckets = strings.Split(*rebuild, ",")
+	}
+
+	// The blocks of an encrypted database are read, and the rebuilt buckets written, through the
+	// encryption
+	db, err := snapshot.OpenDatabaseWithKey(flag.Args()[0], flag.Args()[1], *keyFile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	dbo := databaseOverlay.NewOverlay(db)
+	defer dbo.Close()
+
+	for _, bucket := range buckets {
+		if bucket != "DirBlockInfo" {
+			continue
+		}
+		cfg := util.ReadConfig(*config)
+		if err := dbo.SetBitcoinAnchorRecordPublicKeysFromHex(cfg.App.BitcoinAnchorRecordPublicKeys); err != nil {
+			panic(err)
+		}
+		if err := dbo.SetEthereumAnchorRecordPublicKeysFromHex(cfg.App.EthereumAnchorRecordPublicKeys); err != nil {
+			panic(err)
+		}
+	}
+
+	r, err := compact.Run(dbo, buckets, func(msg string) {
+		fmt.Fprintln(os.Stderr, msg)
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Compaction failed - %v\n", err)
+		os.Exit(1)
+	}
+
+	if *asJSON {
+		b, err := json.MarshalIndent(r, "", "\t")
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(string(b))
+		return
+	}
+	fmt.Printf("%-32s %16s %16s\n", "Bucket", "Before", "After")
+	for _, b := range r.Buckets {
+		fmt.Printf("%-32s %16d %16d\n", b.Bucket, b.Before, b.After)
+	}
+	fmt.Printf("%-32s %16d %16d\n", "Total", r.Before, r.After)
+	fmt.Printf("Finished in %v\n", r.Duration)
+}
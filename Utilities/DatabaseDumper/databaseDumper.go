@@ -1,10 +1,9 @@
 package main
 
 import (
-	"bytes"
+	"bufio"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"os"
 
 	"github.com/FactomProject/factomd/common/interfaces"
@@ -55,6 +54,8 @@ func main() {
 	}
 }
 
+// ExportDatabaseJSON writes every record of the database into db.txt as json, grouped by bucket.
+// The records are streamed into the file one at a time, so the export runs in constant memory.
 func ExportDatabaseJSON(db interfaces.IDatabase, convertNames bool) error {
 	fmt.Printf("Exporting the database\n")
 	if db == nil {
@@ -64,53 +65,71 @@ func ExportDatabaseJSON(db interfaces.IDatabase, convertNames bool) error {
 	if err != nil {
 		return err
 	}
-	answer := map[string]interface{}{}
-	for _, bucket := range buckets {
-		m := map[string]interface{}{}
-		data, keys, err := db.GetAll(bucket, new(primitives.ByteSlice))
-		if err != nil {
-			return err
+
+	dir := "db.txt"
+	f, err := os.OpenFile(dir, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0777)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	out := bufio.NewWriter(f)
+
+	out.WriteString("{")
+	for i, bucket := range buckets {
+		name := fmt.Sprintf("%x", bucket)
+		if convertNames == true {
+			name = KeyToName(bucket)
 		}
-		for i, key := range keys {
-			m[fmt.Sprintf("%x", key)] = data[i]
+		if i > 0 {
+			out.WriteString(",")
 		}
-		if convertNames == true {
-			answer[KeyToName(bucket)] = m
-		} else {
-			answer[fmt.Sprintf("%x", bucket)] = m
+		err = exportBucketJSON(out, db, bucket, name)
+		if err != nil {
+			return err
 		}
 	}
+	out.WriteString("\n}\n")
 
-	data, err := primitives.EncodeJSON(answer)
+	return out.Flush()
+}
+
+func exportBucketJSON(out *bufio.Writer, db interfaces.IDatabase, bucket []byte, name string) error {
+	jsonName, err := json.Marshal(name)
 	if err != nil {
 		return err
 	}
+	fmt.Fprintf(out, "\n\t%s: {", jsonName)
 
-	var out bytes.Buffer
-	json.Indent(&out, data, "", "\t")
-	data = out.Next(out.Len())
-	/*
-		dir := be.DataStorePath
-		if dir != "" {
-			if FileNotExists(dir) {
-				err := os.MkdirAll(dir, 0777)
-				if err == nil {
-					fmt.Println("Created directory " + dir)
-				} else {
-					return err
-				}
-			}
-		}
-		if dir != "" {
-			dir = dir + "/db.txt"
-		} else {
-			dir = "db.txt"
-		}*/
-	dir := "db.txt"
-	err = ioutil.WriteFile(dir, data, 0777)
+	it, err := db.Iterate(bucket, interfaces.IterateOptions{})
 	if err != nil {
 		return err
 	}
+	defer it.Release()
+
+	count := 0
+	for it.Next() {
+		data, err := it.Value(new(primitives.ByteSlice))
+		if err != nil {
+			return err
+		}
+		value, err := primitives.EncodeJSON(data)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			out.WriteString(",")
+		}
+		fmt.Fprintf(out, "\n\t\t\"%x\": %s", it.Key(), value)
+		count++
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+
+	if count > 0 {
+		out.WriteString("\n\t")
+	}
+	out.WriteString("}")
 	return nil
 }
 
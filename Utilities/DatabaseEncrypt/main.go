@@ -0,0 +1,78 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/FactomProject/factomd/database/securedb"
+	"github.com/FactomProject/factomd/database/snapshot"
+	"github.com/FactomProject/factomd/state"
+)
+
+func main() {
+	var (
+		decrypt = flag.Bool("decrypt", false, "Decrypt the database instead of encrypting it")
+		keyFile = flag.String("keyfile", "", "File holding the passphrase, $"+securedb.PassphraseEnv+" is used if not set")
+		buckets = flag.String("buckets", "all", "Buckets to encrypt, all or a comma separated list, as in DBEncryption. All of them are decrypted")
+	)
+	flag.Parse()
+
+	fmt.Fprintln(os.Stderr, "Usage:")
+	fmt.Fprintln(os.Stderr, "DatabaseEncrypt [-decrypt] [-keyfile passphrase.txt] [-buckets all] LDB|Bolt|Badger DatabaseLocation")
+	fmt.Fprintln(os.Stderr, "Encrypts, or decrypts, the records of a stopped node's database in place.")
+	fmt.Fprintln(os.Stderr, "Running it again after an interruption finishes the conversion.")
+
+	if len(flag.Args()) < 2 {
+		fmt.Fprintln(os.Stderr, "\nNot enough arguments passed")
+		os.Exit(1)
+	}
+	if len(flag.Args()) > 2 {
+		fmt.Fprintln(os.Stderr, "\nToo many arguments passed")
+		os.Exit(1)
+	}
+	on, names := state.EncryptedBuckets(*buckets)
+	if !on {
+		fmt.Fprintln(os.Stderr, "\nNo buckets to convert")
+		os.Exit(1)
+	}
+	passphrase, err := securedb.LoadPassphrase(*keyFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "\n%v\n", err)
+		os.Exit(1)
+	}
+
+	db, err := snapshot.OpenDatabase(flag.Args()[0], flag.Args()[1])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "\n%v\n", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	progress := func(bucket []byte, records int) {
+		switch {
+		case bucket == nil:
+			fmt.Fprintf(os.Stderr, "Checked %d records\n", records)
+		case len(bucket) == 32:
+			fmt.Fprintf(os.Stderr, "Checked %d records of chain %x\n", records, bucket)
+		default:
+			fmt.Fprintf(os.Stderr, "Checked %d records of %s\n", records, bucket)
+		}
+	}
+
+	if *decrypt {
+		err = securedb.DecryptInPlace(db, passphrase, progress)
+	} else {
+		err = securedb.EncryptInPlace(db, passphrase, names, progress)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "\nConversion failed - %v\n", err)
+		db.Close()
+		os.Exit(1)
+	}
+	if *decrypt {
+		fmt.Printf("Decrypted %v, remove DBEncryption from factomd.conf\n", flag.Args()[1])
+	} else {
+		fmt.Printf("Encrypted %v, set DBEncryption = \"%s\" in factomd.conf\n", flag.Args()[1], *buckets)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+	"github.com/FactomProject/factomd/database/export"
+	"github.com/FactomProject/factomd/database/securedb"
+	"github.com/FactomProject/factomd/database/snapshot"
+)
+
+func main() {
+	var (
+		out       = flag.String("out", "export", "Directory the CSV files and the checkpoint are written to")
+		partition = flag.Uint("partition", export.DefaultPartitionSize, "Number of heights in each file")
+		keyFile   = flag.String("keyfile", "", "File holding the passphrase of an encrypted database, $"+securedb.PassphraseEnv+" is used if not set")
+	)
+	flag.Parse()
+
+	fmt.Fprintln(os.Stderr, "Usage:")
+	fmt.Fprintln(os.Stderr, "DatabaseExport [-out export] [-partition 10000] [-keyfile passphrase.txt] LDB|Bolt|Badger DatabaseLocation")
+	fmt.Fprintln(os.Stderr, "Writes the dblocks, entries, transactions and commits tables as CSV, one file per range of heights.")
+	fmt.Fprintln(os.Stderr, "Running it again continues from the checkpoint in the output directory.")
+
+	if len(flag.Args()) < 2 {
+		fmt.Fprintln(os.Stderr, "\nNot enough arguments passed")
+		os.Exit(1)
+	}
+	if len(flag.Args()) > 2 {
+		fmt.Fprintln(os.Stderr, "\nToo many arguments passed")
+		os.Exit(1)
+	}
+	if *partition == 0 {
+		fmt.Fprintln(os.Stderr, "\nThe partition needs at least one height")
+		os.Exit(1)
+	}
+
+	db, err := snapshot.OpenDatabaseWithKey(flag.Args()[0], flag.Args()[1], *keyFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "\n%v\n", err)
+		os.Exit(1)
+	}
+	dbo := databaseOverlay.NewOverlay(db)
+	defer dbo.Close()
+
+	e := &export.Exporter{
+		DB:            dbo,
+		Dir:           *out,
+		PartitionSize: uint32(*partition),
+		Progress: func(from uint32, to uint32) {
+			fmt.Fprintf(os.Stderr, "Exported heights %d to %d\n", from, to)
+		},
+	}
+	cp, err := e.Run()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "\nExport failed - %v\n", err)
+		dbo.Close()
+		os.Exit(1)
+	}
+	fmt.Printf("Exported up to height %d to %v\n", int64(cp.Next)-1, *out)
+}
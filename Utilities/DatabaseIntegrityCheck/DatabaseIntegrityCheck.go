@@ -146,37 +146,10 @@ func CheckDatabase(dbo interfaces.DBOverlay) {
 
 	fmt.Printf("\tChecking block indexes\n")
 
-	hashes, keys, err := dbo.GetAll(databaseOverlay.DIRECTORYBLOCK_NUMBER, primitives.NewZeroHash())
-	for i, v := range hashes {
-		h := v.(*primitives.Hash)
-		if hashMap[h.String()] != "OK" {
-			fmt.Printf("Invalid DBlock indexed at height 0x%x - %v\n", keys[i], h)
-		}
-	}
-
-	hashes, keys, err = dbo.GetAll(databaseOverlay.FACTOIDBLOCK_NUMBER, primitives.NewZeroHash())
-	for i, v := range hashes {
-		h := v.(*primitives.Hash)
-		if hashMap[h.String()] != "OK" {
-			fmt.Printf("Invalid FBlock indexed at height 0x%x - %v\n", keys[i], h)
-		}
-	}
-
-	hashes, keys, err = dbo.GetAll(databaseOverlay.ADMINBLOCK_NUMBER, primitives.NewZeroHash())
-	for i, v := range hashes {
-		h := v.(*primitives.Hash)
-		if hashMap[h.String()] != "OK" {
-			fmt.Printf("Invalid ABlock indexed at height 0x%x - %v\n", keys[i], h)
-		}
-	}
-
-	hashes, keys, err = dbo.GetAll(databaseOverlay.ENTRYCREDITBLOCK_NUMBER, primitives.NewZeroHash())
-	for i, v := range hashes {
-		h := v.(*primitives.Hash)
-		if hashMap[h.String()] != "OK" {
-			fmt.Printf("Invalid ECBlock indexed at height 0x%x - %v\n", keys[i], h)
-		}
-	}
+	CheckBlockIndex(dbo, databaseOverlay.DIRECTORYBLOCK_NUMBER, "DBlock", hashMap)
+	CheckBlockIndex(dbo, databaseOverlay.FACTOIDBLOCK_NUMBER, "FBlock", hashMap)
+	CheckBlockIndex(dbo, databaseOverlay.ADMINBLOCK_NUMBER, "ABlock", hashMap)
+	CheckBlockIndex(dbo, databaseOverlay.ENTRYCREDITBLOCK_NUMBER, "ECBlock", hashMap)
 
 	fmt.Printf("\tFinished checking block indexes\n")
 
@@ -383,6 +356,31 @@ type BlockSet struct {
 	//EBlocks
 }
 
+// CheckBlockIndex walks over a height index one record at a time, and reports the heights that
+// point to blocks outside of the chain
+func CheckBlockIndex(dbo interfaces.DBOverlay, bucket []byte, name string, hashMap map[string]string) {
+	it, err := dbo.Iterate(bucket, interfaces.IterateOptions{})
+	if err != nil {
+		panic(err)
+	}
+	defer it.Release()
+
+	for it.Next() {
+		v, err := it.Value(primitives.NewZeroHash())
+		if err != nil {
+			fmt.Printf("Error reading %v index at height 0x%x - %v\n", name, it.Key(), err)
+			continue
+		}
+		h := v.(*primitives.Hash)
+		if hashMap[h.String()] != "OK" {
+			fmt.Printf("Invalid %v indexed at height 0x%x - %v\n", name, it.Key(), h)
+		}
+	}
+	if err := it.Error(); err != nil {
+		panic(err)
+	}
+}
+
 func FetchBlockSet(dbo interfaces.DBOverlay, dBlockHash interfaces.IHash) *BlockSet {
 	bs := new(BlockSet)
 
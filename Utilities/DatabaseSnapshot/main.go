@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/securedb"
+	"github.com/FactomProject/factomd/database/snapshot"
+)
+
+func main() {
+	var (
+		server   = flag.String("s", "localhost:8088", "Address of the debug API of the node")
+		user     = flag.String("rpcuser", "", "Username of the API")
+		password = flag.String("rpcpassword", "", "Password of the API")
+		tarFile  = flag.String("tar", "", "Pack the snapshot into this tar file, - for stdout")
+		verify   = flag.Bool("verify", false, "Only verify an existing snapshot")
+		keyFile  = flag.String("keyfile", "", "File holding the passphrase of an encrypted snapshot, $"+securedb.PassphraseEnv+" is used if not set")
+	)
+	flag.Parse()
+
+	// stdout may carry the tar stream, everything else goes to stderr
+	log := os.Stderr
+	fmt.Fprintln(log, "Usage:")
+	fmt.Fprintln(log, "DatabaseSnapshot [-s host:port] [-tar file|-] SnapshotDirectory")
+	fmt.Fprintln(log, "DatabaseSnapshot -verify [-keyfile file] SnapshotDirectory")
+	fmt.Fprintln(log, "The running node writes a verified snapshot of its database into the directory, which is on the node's machine, below its home directory")
+
+	if len(flag.Args()) < 1 {
+		fmt.Fprintln(log, "\nNot enough arguments passed")
+		os.Exit(1)
+	}
+	if len(flag.Args()) > 1 {
+		fmt.Fprintln(log, "\nToo many arguments passed")
+		os.Exit(1)
+	}
+	dir := flag.Args()[0]
+
+	var m *snapshot.Manifest
+	var err error
+	if *verify {
+		m, err = snapshot.Verify(dir, snapshot.UnlockWithKey(*keyFile))
+	} else {
+		// the node resolves relative paths from its own working directory
+		dir, err = filepath.Abs(dir)
+		if err == nil {
+			m, err = requestSnapshot(*server, *user, *password, dir)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(log, "\nSnapshot failed - %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(log, "Snapshot of %v records at height %v (%v) verified\n", m.Records, m.DBHeight, m.KeyMR)
+	if m.FastBoot != "" {
+		fmt.Fprintf(log, "Fastboot file %v at height %v\n", m.FastBoot, m.FastBootHeight)
+	}
+
+	if *tarFile == "" {
+		return
+	}
+	out := os.Stdout
+	if *tarFile != "-" {
+		out, err = os.OpenFile(*tarFile, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0644)
+		if err != nil {
+			fmt.Fprintf(log, "\n%v\n", err)
+			os.Exit(1)
+		}
+		defer out.Close()
+	}
+	err = snapshot.WriteTar(dir, out)
+	if err != nil {
+		fmt.Fprintf(log, "\nPacking the snapshot failed - %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func requestSnapshot(server, user, password, dir string) (*snapshot.Manifest, error) {
+	params := map[string]string{"path": dir}
+	j := primitives.NewJSON2Request("snapshot-database", 0, params)
+	body, err := json.Marshal(j)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", "http://"+server+"/debug", bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if user != "" {
+		req.SetBasicAuth(user, password)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	raw, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	r := new(primitives.JSON2Response)
+	r.Result = new(snapshot.Manifest)
+	err = json.Unmarshal(raw, r)
+	if err != nil {
+		return nil, fmt.Errorf("%v - %s", resp.Status, raw)
+	}
+	if r.Error != nil {
+		return nil, fmt.Errorf("%v %v", r.Error.Message, r.Error.Data)
+	}
+	return r.Result.(*snapshot.Manifest), nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+	"github.com/FactomProject/factomd/database/securedb"
+	"github.com/FactomProject/factomd/database/snapshot"
+	"github.com/FactomProject/factomd/database/verify"
+	"github.com/FactomProject/factomd/state"
+)
+
+func main() {
+	var (
+		workers  = flag.Int("workers", 0, "Heights verified in parallel, the number of CPUs by default")
+		fastboot = flag.String("fastboot", "", "Fastboot file whose balances are compared with the ones replayed from the blocks")
+		keyFile  = flag.String("keyfile", "", "File holding the passphrase of an encrypted database, $"+securedb.PassphraseEnv+" is used if not set")
+	)
+	flag.Parse()
+
+	fmt.Fprintln(os.Stderr, "Usage:")
+	fmt.Fprintln(os.Stderr, "DatabaseVerify [-workers N] [-fastboot FastBoot_MAIN_v12.db] [-keyfile passphrase.txt] LDB|Bolt|Badger DatabaseLocation")
+	fmt.Fprintln(os.Stderr, "Prints a JSON report of the discrepancies found, and exits with 2 if there are any")
+
+	if len(flag.Args()) < 2 {
+		fmt.Fprintln(os.Stderr, "\nNot enough arguments passed")
+		os.Exit(1)
+	}
+	if len(flag.Args()) > 2 {
+		fmt.Fprintln(os.Stderr, "\nToo many arguments passed")
+		os.Exit(1)
+	}
+
+	opts := verify.Options{Workers: *workers}
+	if *fastboot != "" {
+		ss, err := state.ReadSaveState(*fastboot)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "\nReading %v failed - %v\n", *fastboot, err)
+			os.Exit(1)
+		}
+		opts.SaveState = &verify.Balances{Height: ss.DBHeight, Factoid: ss.FactoidBalancesP, EntryCredit: ss.ECBalancesP}
+	}
+
+	var last time.Time
+	opts.Progress = func(done uint32, total uint32) {
+		if done < total && time.Since(last) < 5*time.Second {
+			return
+		}
+		last = time.Now()
+		fmt.Fprintf(os.Stderr, "Verified %d of %d heights (%.1f%%)\n", done, total, 100*float64(done)/float64(total))
+	}
+
+	db, err := snapshot.OpenDatabaseWithKey(flag.Args()[0], flag.Args()[1], *keyFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "\n%v\n", err)
+		os.Exit(1)
+	}
+	dbo := databaseOverlay.NewOverlay(db)
+	defer dbo.Close()
+
+	r, err := verify.Run(dbo, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "\nVerification failed - %v\n", err)
+		os.Exit(1)
+	}
+
+	b, err := json.MarshalIndent(r, "", "\t")
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(string(b))
+	fmt.Fprintf(os.Stderr, "Found %d discrepancies in %v\n", len(r.Discrepancies), r.Duration)
+	if !r.OK {
+		dbo.Close()
+		os.Exit(2)
+	}
+}
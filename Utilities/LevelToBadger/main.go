@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/FactomProject/factomd/database/badgerdb"
+)
+
+func main() {
+	fmt.Println("Usage:")
+	fmt.Println("LevelToBadger LevelDBLocation BadgerDBLocation")
+	fmt.Println("Every record of the LevelDB database will be copied into a new Badger database")
+	fmt.Println("For a node, the locations are <LdbPath>/<Network>/factoid_level.db and <BadgerDBPath>/<Network>/factoid_badger.db")
+
+	if len(os.Args) < 3 {
+		fmt.Println("\nNot enough arguments passed")
+		os.Exit(1)
+	}
+	if len(os.Args) > 3 {
+		fmt.Println("\nToo many arguments passed")
+		os.Exit(1)
+	}
+
+	levelPath := os.Args[1]
+	badgerPath := os.Args[2]
+
+	if _, err := os.Stat(levelPath); err != nil {
+		fmt.Printf("\n%v\n", err)
+		os.Exit(1)
+	}
+	if _, err := os.Stat(badgerPath); err == nil {
+		fmt.Printf("\n%v already exists, the copy has to go into a new database\n", badgerPath)
+		os.Exit(1)
+	}
+
+	dbase, err := badgerdb.NewBadgerDB(badgerPath, true)
+	if err != nil {
+		panic(err)
+	}
+
+	start := time.Now()
+	count, err := dbase.ImportLevelDB(levelPath, func(records uint64) {
+		fmt.Printf("\tCopied %v records in %v\n", records, time.Since(start).Round(time.Second))
+	})
+	if err != nil {
+		dbase.Close()
+		fmt.Printf("\nCopy failed after %v records - %v\n", count, err)
+		os.Exit(1)
+	}
+
+	if err := dbase.Close(); err != nil {
+		panic(err)
+	}
+	fmt.Printf("Finished copying %v records\n", count)
+}
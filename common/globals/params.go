@@ -27,6 +27,7 @@ type FactomParams struct {
 	NetworkName              string
 	NetworkPortOverride      int
 	ControlPanelPortOverride int
+	DebugApiPortOverride     int
 	LogPort                  string
 	BlkTime                  int
 	FaultTimeout             int
@@ -48,6 +49,7 @@ type FactomParams struct {
 	RpcPassword              string
 	FactomdTLS               bool
 	FactomdLocations         string
+	ApiReadOnly              bool
 	MemProfileRate           int
 	Fast                     bool
 	FastLocation             string
@@ -58,6 +60,7 @@ type FactomParams struct {
 	PluginPath               string
 	TorManage                bool
 	TorUpload                bool
+	ImportArchive            string
 	Sim_Stdin                bool
 	ExposeProfiling          bool
 	UseLogstash              bool
@@ -86,8 +89,13 @@ type FactomParams struct {
 	EventSenderPort          int
 	EventFormat              string
 	EventSendStateChange     bool
+	EventSendBalanceChanges  bool
 	EventBroadcastContent    string
 	EventReplayDuringStartup bool
+	EventReplayReceiver      string
+	EventReplayStart         int
+	EventReplayEnd           int
+	EventReplayRate          int
 	PersistentReconnect      bool
 }
 
@@ -4,6 +4,10 @@
 
 package interfaces
 
+import (
+	"bytes"
+)
+
 type IDatabase interface {
 	Close() error
 	Put(bucket, key []byte, data BinaryMarshallable) error
@@ -16,6 +20,102 @@ type IDatabase interface {
 	ListAllBuckets() ([][]byte, error)
 	Trim()
 	DoesKeyExist(bucket, key []byte) (bool, error)
+	Iterate(bucket []byte, options IterateOptions) (IIterator, error)
+}
+
+// IIterator walks over the records of a bucket in key order, without loading the bucket into
+// memory. Next has to be called before the first record, and Release once done.
+type IIterator interface {
+	Next() bool
+	Key() []byte
+	// Value unmarshals the data of the current record into the destination
+	Value(destination BinaryMarshallable) (BinaryMarshallable, error)
+	Error() error
+	Release()
+}
+
+// IterateOptions selects the records of a bucket an iterator visits. Prefix and the range
+// [Start, End) restrict the keys, nil leaves them unrestricted. Reverse visits the keys from the
+// last to the first, and Limit stops after that many records, 0 visits all of them.
+type IterateOptions struct {
+	Prefix  []byte
+	Start   []byte
+	End     []byte
+	Reverse bool
+	Limit   int
+}
+
+// Lower returns the smallest key in range, nil if there is no lower bound
+func (o IterateOptions) Lower() []byte {
+	if bytes.Compare(o.Start, o.Prefix) > 0 {
+		return o.Start
+	}
+	if len(o.Prefix) == 0 {
+		return nil
+	}
+	return o.Prefix
+}
+
+// Upper returns the first key past the range, nil if there is no upper bound
+func (o IterateOptions) Upper() []byte {
+	upper := PrefixEnd(o.Prefix)
+	if len(o.End) > 0 && (upper == nil || bytes.Compare(o.End, upper) < 0) {
+		return o.End
+	}
+	return upper
+}
+
+// InRange checks if the iterator visits the key
+func (o IterateOptions) InRange(key []byte) bool {
+	if !bytes.HasPrefix(key, o.Prefix) {
+		return false
+	}
+	if len(o.Start) > 0 && bytes.Compare(key, o.Start) < 0 {
+		return false
+	}
+	if len(o.End) > 0 && bytes.Compare(key, o.End) >= 0 {
+		return false
+	}
+	return true
+}
+
+// PrefixEnd returns the first key past all the keys with the prefix, nil if there is none
+func PrefixEnd(prefix []byte) []byte {
+	for i := len(prefix) - 1; i >= 0; i-- {
+		if prefix[i] < 0xff {
+			end := make([]byte, i+1)
+			copy(end, prefix)
+			end[i]++
+			return end
+		}
+	}
+	return nil
+}
+
+// ISnapshotDatabase is implemented by the databases that can take a consistent point-in-time view
+// of all their records while they are in use
+type ISnapshotDatabase interface {
+	Snapshot() (IDatabaseSnapshot, error)
+}
+
+// IDatabaseSnapshot is a point-in-time view of a database. Release has to be called once done,
+// as the database keeps the data of the view around until then.
+type IDatabaseSnapshot interface {
+	// CopyTo writes the records into a new database of the same type at the path, and returns
+	// the number of records written
+	CopyTo(path string) (uint64, error)
+	Release()
+}
+
+// ICompactDatabase is implemented by the databases that can reclaim the space of deleted and
+// overwritten records
+type ICompactDatabase interface {
+	// Compact rewrites the storage of the database, it blocks until done
+	Compact() error
+	// BucketSize returns the approximate space used by the records of the bucket, in bytes
+	BucketSize(bucket []byte) (int64, error)
+	// Size returns the space used by the database on disk, in bytes
+	Size() (int64, error)
 }
 
 type Record struct {
@@ -21,6 +21,7 @@ type DBOverlaySimple interface {
 	FetchDBlockHead() (IDirectoryBlock, error)
 	FetchEBlock(IHash) (IEntryBlock, error)
 	FetchEBlockHead(chainID IHash) (IEntryBlock, error)
+	FetchEBlockKeyMRFromHeight(chainID IHash, height uint32, reverse bool) (IHash, error)
 	FetchECBlock(IHash) (IEntryCreditBlock, error)
 	FetchECBlockByHeight(blockHeight uint32) (IEntryCreditBlock, error)
 	FetchECTransaction(hash IHash) (IECBlockEntry, error)
@@ -50,6 +51,21 @@ type DBOverlaySimple interface {
 	FetchKeyValueStore(key []byte, dst BinaryMarshallable) (BinaryMarshallable, error)
 	SaveDatabaseEntryHeight(height uint32) error
 	FetchDatabaseEntryHeight() (uint32, error)
+	SaveAddressTransactions(height uint32, fblock IFBlock, ecblock IEntryCreditBlock) error
+	ProcessAddressTransactionsMultiBatch(height uint32, fblock IFBlock, ecblock IEntryCreditBlock) error
+	FetchAddressIndexHeight() (uint32, error)
+	FetchFactoidAddressTransactions(address IHash) ([]AddressTransaction, error)
+	FetchECAddressTransactions(address IHash) ([]AddressTransaction, error)
+	FetchFactoidAddressTransactionsPage(address IHash, offset int, limit int) ([]AddressTransaction, int, error)
+	FetchECAddressTransactionsPage(address IHash, offset int, limit int) ([]AddressTransaction, int, error)
+	SaveEntryExtIDs(entry IEBEntry) error
+	SaveExtIDIndexHeight(height uint32) error
+	FetchExtIDIndexHeight() (uint32, error)
+	FetchEntryHashesByExtID(chainID IHash, extID []byte, start IHash, limit int) ([]IHash, IHash, error)
+	PruneEntry(hash IHash) error
+	IsEntryPruned(hash IHash) (bool, error)
+	SaveEntryPruneHeight(height uint32) error
+	FetchEntryPruneHeight() (uint32, error)
 }
 
 // Db defines a generic interface that is used to request and insert data into db
@@ -106,6 +122,10 @@ type DBOverlay interface {
 
 	FetchEBlockHead(chainID IHash) (IEntryBlock, error)
 
+	// FetchEBlockKeyMRFromHeight returns the KeyMR of the first entry block of the chain at or
+	// above the height, or at or below it in reverse
+	FetchEBlockKeyMRFromHeight(chainID IHash, height uint32, reverse bool) (IHash, error)
+
 	FetchAllEBlockChainIDs() ([]IHash, error)
 
 	//**********************************DBlock**********************************//
@@ -266,6 +286,27 @@ type DBOverlay interface {
 	FetchKeyValueStore(key []byte, dst BinaryMarshallable) (BinaryMarshallable, error)
 	SaveDatabaseEntryHeight(height uint32) error
 	FetchDatabaseEntryHeight() (uint32, error)
+
+	//******************************AddressTransactions****************************//
+	SaveAddressTransactions(height uint32, fblock IFBlock, ecblock IEntryCreditBlock) error
+	ProcessAddressTransactionsMultiBatch(height uint32, fblock IFBlock, ecblock IEntryCreditBlock) error
+	FetchAddressIndexHeight() (uint32, error)
+	FetchFactoidAddressTransactions(address IHash) ([]AddressTransaction, error)
+	FetchECAddressTransactions(address IHash) ([]AddressTransaction, error)
+	FetchFactoidAddressTransactionsPage(address IHash, offset int, limit int) ([]AddressTransaction, int, error)
+	FetchECAddressTransactionsPage(address IHash, offset int, limit int) ([]AddressTransaction, int, error)
+
+	//******************************EntryExtIDs****************************//
+	SaveEntryExtIDs(entry IEBEntry) error
+	SaveExtIDIndexHeight(height uint32) error
+	FetchExtIDIndexHeight() (uint32, error)
+	FetchEntryHashesByExtID(chainID IHash, extID []byte, start IHash, limit int) ([]IHash, IHash, error)
+
+	//******************************EntryPruning****************************//
+	PruneEntry(hash IHash) error
+	IsEntryPruned(hash IHash) (bool, error)
+	SaveEntryPruneHeight(height uint32) error
+	FetchEntryPruneHeight() (uint32, error)
 }
 
 type ISCDatabaseOverlay interface {
@@ -286,3 +327,9 @@ type ISCDatabaseOverlay interface {
 	SaveAddressByPublicKey(key []byte, we IWalletEntry) error
 	SaveAddressByName(key []byte, we IWalletEntry) error
 }
+
+// AddressTransaction is an entry of the optional address transaction index
+type AddressTransaction struct {
+	Height uint32
+	TxID   IHash
+}
@@ -5,13 +5,45 @@
 package interfaces
 
 import (
+	"fmt"
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/FactomProject/factomd/activations"
 	"github.com/FactomProject/factomd/common/constants/runstate"
 )
 
+// BalanceHistoryError is returned for the balance at a height the node can not replay from a
+// save state, or from the address index. It tells the caller which heights the node can answer
+type BalanceHistoryError struct {
+	Height uint32
+	// AddressIndex is set if the address index is enabled but still backfilling, it covers the
+	// heights below IndexHeight
+	AddressIndex bool
+	IndexHeight  uint32
+	// SaveState is set if there is a fastboot save state, the heights from SaveStateHeight on are
+	// replayed from it
+	SaveState       bool
+	SaveStateHeight uint32
+}
+
+func (e *BalanceHistoryError) Error() string {
+	var covered []string
+	if e.AddressIndex && e.IndexHeight == 0 {
+		covered = append(covered, "the address index is backfilling and covers no height yet")
+	} else if e.AddressIndex {
+		covered = append(covered, fmt.Sprintf("the address index is backfilling and covers the heights below %d", e.IndexHeight))
+	}
+	if e.SaveState {
+		covered = append(covered, fmt.Sprintf("the fastboot save state covers the heights from %d", e.SaveStateHeight))
+	}
+	if len(covered) == 0 {
+		return fmt.Sprintf("no balance history at height %d, the node has neither an address index nor a fastboot save state", e.Height)
+	}
+	return fmt.Sprintf("no balance history at height %d, %s", e.Height, strings.Join(covered, " and "))
+}
+
 type DBStateSent struct {
 	DBHeight uint32
 	Sent     Timestamp
@@ -86,6 +118,25 @@ type IState interface {
 	GetTlsInfo() (bool, string, string)
 	GetFactomdLocations() string
 	GetCorsDomains() []string
+	GetApiBatchSettings() (int, int)
+	GetApiRateLimits() (int, int, int, int)
+	GetApiRateLimitKey() string
+	IsApiReadOnly() bool
+	GetDebugApiPort() int
+	IsAddressIndexEnabled() bool
+	IsExtIDIndexEnabled() bool
+	GetFactoidBalanceAtHeight(address [32]byte, height uint32) (int64, error)
+	GetECBalanceAtHeight(address [32]byte, height uint32) (int64, error)
+	// SnapshotPath resolves a path for a snapshot, it has to be below the home directory of the node
+	SnapshotPath(path string) (string, error)
+	// SnapshotDatabase writes a verified copy of the database into a directory below the home
+	// directory of the node, and returns its manifest
+	SnapshotDatabase(dir string) (interface{}, error)
+	// CompactDatabase compacts the database, and returns the space of each bucket before and after
+	CompactDatabase() (interface{}, error)
+	// StartLiveFeedReplay starts sending the directory blocks of the height range out of the
+	// database to the live feed receiver, at no more than blocksPerSecond
+	StartLiveFeedReplay(receiver string, start uint32, end uint32, blocksPerSecond int) error
 
 	// Routine for handling the syncroniztion of the leader and follower processes
 	// and how they process messages.
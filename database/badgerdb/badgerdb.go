@@ -0,0 +1,344 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package badgerdb
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/dgraph-io/badger"
+)
+
+// GCInterval is how often the value log of an open database is garbage collected
+var GCInterval = 10 * time.Minute
+
+// BadgerDB stores the records in a Badger key-value store. The keys are laid out the same way
+// as in LevelDB, the bucket followed by ';' and the key, so a LevelDB database can be copied
+// over record by record.
+type BadgerDB struct {
+	bDB     *badger.DB
+	dir     string
+	quit    chan struct{}
+	gc      sync.WaitGroup
+	closed  sync.Once
+	journal sync.Mutex // held while a batch is written through the journal
+}
+
+var _ interfaces.IDatabase = (*BadgerDB)(nil)
+
+func NewBadgerDB(dir string, create bool) (*BadgerDB, error) {
+	if create == true {
+		err := os.MkdirAll(dir, 0750)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		_, err := os.Stat(dir)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	opts := badger.DefaultOptions(dir).WithLogger(quietLogger{})
+	bDB, err := badger.Open(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	db := new(BadgerDB)
+	db.bDB = bDB
+	db.dir = dir
+	// a batch interrupted by a crash is finished or dropped before the records are read
+	if err := db.recoverJournal(); err != nil {
+		bDB.Close()
+		return nil, err
+	}
+	db.quit = make(chan struct{})
+	db.gc.Add(1)
+	go db.collectGarbage()
+	return db, nil
+}
+
+// collectGarbage reclaims the space of overwritten and deleted values until the database is closed
+func (db *BadgerDB) collectGarbage() {
+	defer db.gc.Done()
+	ticker := time.NewTicker(GCInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-db.quit:
+			return
+		case <-ticker.C:
+			// each run rewrites at most one log file, keep going while there is something to reclaim
+			for db.bDB.RunValueLogGC(0.5) == nil {
+			}
+		}
+	}
+}
+
+func dbKey(bucket []byte, key []byte) []byte {
+	k := make([]byte, 0, len(bucket)+1+len(key))
+	k = append(k, bucket...)
+	k = append(k, ';')
+	return append(k, key...)
+}
+
+func (db *BadgerDB) Close() error {
+	var err error
+	db.closed.Do(func() {
+		close(db.quit)
+		db.gc.Wait()
+		err = db.bDB.Close()
+	})
+	return err
+}
+
+func (db *BadgerDB) ListAllBuckets() ([][]byte, error) {
+	return nil, fmt.Errorf("Unable to fetch buckets, the bucket of a key is ambiguous in Badger")
+}
+
+// The value log is garbage collected in the background
+func (db *BadgerDB) Trim() {
+}
+
+func (db *BadgerDB) Get(bucket []byte, key []byte, destination interfaces.BinaryMarshallable) (interfaces.BinaryMarshallable, error) {
+	var data []byte
+	err := db.bDB.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(dbKey(bucket, key))
+		if err != nil {
+			return err
+		}
+		data, err = item.ValueCopy(nil)
+		return err
+	})
+	if err == badger.ErrKeyNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = destination.UnmarshalBinaryData(data)
+	if err != nil {
+		return nil, err
+	}
+	return destination, nil
+}
+
+func (db *BadgerDB) Put(bucket []byte, key []byte, data interfaces.BinaryMarshallable) error {
+	hex, err := data.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return db.bDB.Update(func(txn *badger.Txn) error {
+		return txn.Set(dbKey(bucket, key), hex)
+	})
+}
+
+// PutInBatch writes the records in a single transaction. A batch too big for one transaction is
+// written through the journal instead, which spreads it over several transactions and still
+// writes all of it or none of it.
+func (db *BadgerDB) PutInBatch(records []interfaces.Record) error {
+	keys := make([][]byte, len(records))
+	values := make([][]byte, len(records))
+	for i, v := range records {
+		hex, err := v.Data.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		keys[i] = dbKey(v.Bucket, v.Key)
+		values[i] = hex
+	}
+	return db.putInBatch(keys, values)
+}
+
+// PutStoredInBatch writes the records under the keys they are stored under, as returned by
+// IterateStored. The buckets of the records are not used
+func (db *BadgerDB) PutStoredInBatch(records []interfaces.Record) error {
+	keys := make([][]byte, len(records))
+	values := make([][]byte, len(records))
+	for i, v := range records {
+		hex, err := v.Data.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		keys[i] = v.Key
+		values[i] = hex
+	}
+	return db.putInBatch(keys, values)
+}
+
+// putInBatch writes the records in a single transaction. The multi batch of the overlay relies on
+// a block and its indexes being written together, so a batch too big for a transaction goes
+// through the journal rather than being split into writes that a crash could leave half done.
+func (db *BadgerDB) putInBatch(keys [][]byte, values [][]byte) error {
+	err := db.bDB.Update(func(txn *badger.Txn) error {
+		for i := range keys {
+			if err := txn.Set(keys[i], values[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != badger.ErrTxnTooBig {
+		return err
+	}
+	return db.putInJournal(keys, values)
+}
+
+func (db *BadgerDB) Delete(bucket []byte, key []byte) error {
+	return db.bDB.Update(func(txn *badger.Txn) error {
+		return txn.Delete(dbKey(bucket, key))
+	})
+}
+
+func (db *BadgerDB) Clear(bucket []byte) error {
+	keys, err := db.ListAllKeys(bucket)
+	if err != nil {
+		return err
+	}
+
+	wb := db.bDB.NewWriteBatch()
+	defer wb.Cancel()
+	for _, key := range keys {
+		if err := wb.Delete(dbKey(bucket, key)); err != nil {
+			return err
+		}
+	}
+	return wb.Flush()
+}
+
+func (db *BadgerDB) ListAllKeys(bucket []byte) ([][]byte, error) {
+	prefix := dbKey(bucket, nil)
+	var answer [][]byte
+	err := db.bDB.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		opts.Prefix = prefix
+		iter := txn.NewIterator(opts)
+		defer iter.Close()
+
+		for iter.Rewind(); iter.Valid(); iter.Next() {
+			answer = append(answer, iter.Item().KeyCopy(nil)[len(prefix):])
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return answer, nil
+}
+
+func (db *BadgerDB) GetAll(bucket []byte, sample interfaces.BinaryMarshallableAndCopyable) ([]interfaces.BinaryMarshallableAndCopyable, [][]byte, error) {
+	prefix := dbKey(bucket, nil)
+	answer := []interfaces.BinaryMarshallableAndCopyable{}
+	keys := [][]byte{}
+	err := db.bDB.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = prefix
+		iter := txn.NewIterator(opts)
+		defer iter.Close()
+
+		for iter.Rewind(); iter.Valid(); iter.Next() {
+			item := iter.Item()
+			v, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			tmp := sample.New()
+			err = tmp.UnmarshalBinary(v)
+			if err != nil {
+				return err
+			}
+			keys = append(keys, item.KeyCopy(nil)[len(prefix):])
+			answer = append(answer, tmp)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return answer, keys, nil
+}
+
+func (db *BadgerDB) DoesKeyExist(bucket, key []byte) (bool, error) {
+	err := db.bDB.View(func(txn *badger.Txn) error {
+		_, err := txn.Get(dbKey(bucket, key))
+		return err
+	})
+	if err == badger.ErrKeyNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// quietLogger passes on the warnings and errors of Badger, and drops the rest
+type quietLogger struct{}
+
+func (quietLogger) Errorf(format string, args ...interface{}) {
+	fmt.Fprintln(os.Stderr, "badger:", strings.TrimSpace(fmt.Sprintf(format, args...)))
+}
+
+func (quietLogger) Warningf(format string, args ...interface{}) {
+	fmt.Fprintln(os.Stderr, "badger:", strings.TrimSpace(fmt.Sprintf(format, args...)))
+}
+
+func (quietLogger) Infof(string, ...interface{}) {}
+
+func (quietLogger) Debugf(string, ...interface{}) {}
+
+var _ interfaces.ISnapshotDatabase = (*BadgerDB)(nil)
+
+// Snapshot takes a view of the database as it is now, writes made afterwards are not part of it
+func (db *BadgerDB) Snapshot() (interfaces.IDatabaseSnapshot, error) {
+	return &badgerSnapshot{txn: db.bDB.NewTransaction(false)}, nil
+}
+
+type badgerSnapshot struct {
+	txn *badger.Txn
+}
+
+func (s *badgerSnapshot) CopyTo(path string) (uint64, error) {
+	if _, err := os.Stat(path); err == nil {
+		return 0, fmt.Errorf("%v already exists", path)
+	}
+	dst, err := NewBadgerDB(path, true)
+	if err != nil {
+		return 0, err
+	}
+	defer dst.Close()
+
+	opts := badger.DefaultIteratorOptions
+	iter := s.txn.NewIterator(opts)
+	defer iter.Close()
+
+	wb := dst.bDB.NewWriteBatch()
+	defer wb.Cancel()
+
+	var count uint64
+	for iter.Rewind(); iter.Valid(); iter.Next() {
+		item := iter.Item()
+		v, err := item.ValueCopy(nil)
+		if err != nil {
+			return count, err
+		}
+		if err := wb.Set(item.KeyCopy(nil), v); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, wb.Flush()
+}
+
+func (s *badgerSnapshot) Release() {
+	s.txn.Discard()
+}
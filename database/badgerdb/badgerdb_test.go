@@ -0,0 +1,114 @@
+package badgerdb_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/database/badgerdb"
+	"github.com/FactomProject/factomd/database/leveldb"
+)
+
+func TestImportLevelDB(t *testing.T) {
+	levelPath, badgerPath := "importTest.ldb", "importTest.badger"
+	defer os.RemoveAll(levelPath)
+	defer os.RemoveAll(badgerPath)
+
+	l, err := leveldb.NewLevelDB(levelPath, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var records []interfaces.Record
+	for i := 0; i < 1000; i++ {
+		bucket := []byte("bucket")
+		if i%2 == 0 {
+			// a bucket that is a prefix of the other one
+			bucket = []byte("bucke")
+		}
+		records = append(records, interfaces.Record{Bucket: bucket, Key: primitives.Sha([]byte{byte(i), byte(i >> 8)}).Bytes(), Data: primitives.Sha([]byte{byte(i)})})
+	}
+	if err := l.PutInBatch(records); err != nil {
+		t.Fatal(err)
+	}
+	l.Close()
+
+	b, err := NewBadgerDB(badgerPath, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+
+	var reported uint64
+	count, err := b.ImportLevelDB(levelPath, func(records uint64) { reported = records })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1000 || reported != 1000 {
+		t.Errorf("Copied %v records, reported %v, expected 1000", count, reported)
+	}
+
+	for _, r := range records {
+		h, err := b.Get(r.Bucket, r.Key, new(primitives.Hash))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if h == nil || h.(*primitives.Hash).IsSameAs(r.Data.(*primitives.Hash)) == false {
+			t.Errorf("Record %x/%x was not copied", r.Bucket, r.Key)
+		}
+	}
+	keys, err := b.ListAllKeys([]byte("bucke"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 500 {
+		t.Errorf("Found %v keys in the bucket, expected 500", len(keys))
+	}
+
+	if _, err := b.ImportLevelDB("missing.ldb", nil); err == nil {
+		t.Errorf("Imported a missing database")
+	}
+	os.RemoveAll("missing.ldb")
+}
+
+func TestPutInBatchTooBig(t *testing.T) {
+	path := "tooBigTest.badger"
+	defer os.RemoveAll(path)
+
+	b, err := NewBadgerDB(path, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+
+	// more records than fit in one transaction with the default options go through the journal
+	var records []interfaces.Record
+	for i := 0; i < 200000; i++ {
+		records = append(records, interfaces.Record{Bucket: []byte("bucket"), Key: primitives.Sha([]byte{byte(i), byte(i >> 8), byte(i >> 16)}).Bytes(), Data: primitives.Sha([]byte{byte(i)})})
+	}
+	if err := b.PutInBatch(records); err != nil {
+		t.Fatal(err)
+	}
+	keys, err := b.ListAllKeys([]byte("bucket"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != len(records) {
+		t.Errorf("Expected %v records, found %v", len(records), len(keys))
+	}
+	last := records[len(records)-1]
+	data, err := b.Get(last.Bucket, last.Key, new(primitives.Hash))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data == nil || !data.(*primitives.Hash).IsSameAs(last.Data.(*primitives.Hash)) {
+		t.Errorf("Expected %v, got %v", last.Data, data)
+	}
+	journal, err := b.ListAllKeys([]byte("BadgerJournal"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(journal) != 0 {
+		t.Errorf("%v records were left in the journal", len(journal))
+	}
+}
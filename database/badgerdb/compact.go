@@ -0,0 +1,61 @@
+package badgerdb
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/dgraph-io/badger"
+)
+
+var _ interfaces.ICompactDatabase = (*BadgerDB)(nil)
+
+// Compact merges the tables of the database into one level, then rewrites the value log files
+// until there is nothing left to reclaim
+func (db *BadgerDB) Compact() error {
+	if err := db.bDB.Flatten(1); err != nil {
+		return err
+	}
+	for {
+		err := db.bDB.RunValueLogGC(0.5)
+		if err == badger.ErrNoRewrite {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// BucketSize adds up the estimated sizes of the records of the bucket
+func (db *BadgerDB) BucketSize(bucket []byte) (int64, error) {
+	var size int64
+	err := db.bDB.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		opts.Prefix = dbKey(bucket, nil)
+		it := txn.NewIterator(opts)
+		defer it.Close()
+		for it.Rewind(); it.Valid(); it.Next() {
+			size += it.Item().EstimatedSize()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// Size returns the size of the files of the database. Badger's own figures are only refreshed
+// every minute.
+func (db *BadgerDB) Size() (int64, error) {
+	var size int64
+	err := filepath.Walk(db.dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
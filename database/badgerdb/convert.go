@@ -0,0 +1,47 @@
+package badgerdb
+
+import (
+	"github.com/FactomProject/goleveldb/leveldb"
+	"github.com/FactomProject/goleveldb/leveldb/opt"
+)
+
+// ImportLevelDB copies every record of the LevelDB database at the path into the Badger
+// database, and returns the number of records copied. The LevelDB database is opened read-only
+// and left as it is. Progress, if not nil, is called with the running count as the copy goes.
+func (db *BadgerDB) ImportLevelDB(path string, progress func(records uint64)) (uint64, error) {
+	lDB, err := leveldb.OpenFile(path, &opt.Options{ReadOnly: true, ErrorIfMissing: true})
+	if err != nil {
+		return 0, err
+	}
+	defer lDB.Close()
+
+	iter := lDB.NewIterator(nil, nil)
+	defer iter.Release()
+
+	wb := db.bDB.NewWriteBatch()
+	defer wb.Cancel()
+
+	var count uint64
+	for iter.Next() {
+		// the iterator reuses its buffers, the batch keeps the slices until it is flushed
+		k := append([]byte{}, iter.Key()...)
+		v := append([]byte{}, iter.Value()...)
+		if err := wb.Set(k, v); err != nil {
+			return count, err
+		}
+		count++
+		if progress != nil && count%100000 == 0 {
+			progress(count)
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return count, err
+	}
+	if err := wb.Flush(); err != nil {
+		return count, err
+	}
+	if progress != nil {
+		progress(count)
+	}
+	return count, nil
+}
@@ -0,0 +1,143 @@
+package badgerdb
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/dgraph-io/badger"
+)
+
+// Iterate walks over the records of the bucket selected by the options. The iterator reads
+// from its own read-only transaction, it does not block writes.
+func (db *BadgerDB) Iterate(bucket []byte, options interfaces.IterateOptions) (interfaces.IIterator, error) {
+	prefix := dbKey(bucket, nil)
+
+	it := new(badgerIterator)
+	it.bucketLen = len(prefix)
+	it.reverse = options.Reverse
+	it.limit = options.Limit
+	if lower := options.Lower(); lower != nil {
+		it.start = append(append([]byte{}, prefix...), lower...)
+	}
+	if upper := options.Upper(); upper != nil {
+		it.end = append(append([]byte{}, prefix...), upper...)
+	} else if options.Reverse {
+		// a reverse iterator starts from the first key past the bucket
+		it.end = interfaces.PrefixEnd(prefix)
+	}
+
+	opts := badger.DefaultIteratorOptions
+	opts.PrefetchValues = false
+	opts.Reverse = options.Reverse
+	opts.Prefix = append(append([]byte{}, prefix...), options.Prefix...)
+
+	it.txn = db.bDB.NewTransaction(false)
+	it.iter = it.txn.NewIterator(opts)
+	return it, nil
+}
+
+// IterateStored walks over all the records of the database, whatever their bucket, as the
+// bucket of a key is ambiguous in Badger. The keys are the ones the records are stored under.
+// It only walks forward
+func (db *BadgerDB) IterateStored(options interfaces.IterateOptions) (interfaces.IIterator, error) {
+	if options.Reverse {
+		return nil, fmt.Errorf("Stored records can only be walked forward")
+	}
+
+	it := new(badgerIterator)
+	it.limit = options.Limit
+	it.start = options.Lower()
+	it.end = options.Upper()
+
+	opts := badger.DefaultIteratorOptions
+	opts.PrefetchValues = false
+	opts.Prefix = options.Prefix
+
+	it.txn = db.bDB.NewTransaction(false)
+	it.iter = it.txn.NewIterator(opts)
+	return it, nil
+}
+
+type badgerIterator struct {
+	txn       *badger.Txn
+	iter      *badger.Iterator
+	bucketLen int
+	start     []byte
+	end       []byte
+	reverse   bool
+	limit     int
+	count     int
+	started   bool
+	valid     bool
+	err       error
+}
+
+var _ interfaces.IIterator = (*badgerIterator)(nil)
+
+func (it *badgerIterator) Next() bool {
+	it.valid = false
+	if it.limit > 0 && it.count >= it.limit {
+		return false
+	}
+
+	switch {
+	case !it.started && it.reverse:
+		// seeking backwards lands on the last key at or before the end, skip the end itself
+		it.iter.Seek(it.end)
+		for it.iter.Valid() && bytes.Compare(it.iter.Item().Key(), it.end) >= 0 {
+			it.iter.Next()
+		}
+	case !it.started:
+		it.iter.Seek(it.start)
+	default:
+		it.iter.Next()
+	}
+	it.started = true
+
+	if !it.iter.Valid() {
+		return false
+	}
+	key := it.iter.Item().Key()
+	if it.reverse && it.start != nil && bytes.Compare(key, it.start) < 0 {
+		return false
+	}
+	if !it.reverse && it.end != nil && bytes.Compare(key, it.end) >= 0 {
+		return false
+	}
+	it.count++
+	it.valid = true
+	return true
+}
+
+func (it *badgerIterator) Key() []byte {
+	if !it.valid {
+		return nil
+	}
+	return it.iter.Item().KeyCopy(nil)[it.bucketLen:]
+}
+
+func (it *badgerIterator) Value(destination interfaces.BinaryMarshallable) (interfaces.BinaryMarshallable, error) {
+	if !it.valid {
+		return nil, nil
+	}
+	v, err := it.iter.Item().ValueCopy(nil)
+	if err != nil {
+		it.err = err
+		return nil, err
+	}
+	_, err = destination.UnmarshalBinaryData(v)
+	if err != nil {
+		return nil, err
+	}
+	return destination, nil
+}
+
+func (it *badgerIterator) Error() error {
+	return it.err
+}
+
+func (it *badgerIterator) Release() {
+	it.iter.Close()
+	it.txn.Discard()
+}
@@ -0,0 +1,181 @@
+package badgerdb
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/dgraph-io/badger"
+)
+
+// A batch too big for one Badger transaction is written through a journal. The records are staged
+// in the journal bucket over as many transactions as they take, then a commit marker is written in
+// a single transaction, and only then are the records copied to their own keys. Opening the
+// database finishes a committed batch that a crash interrupted and drops the records of one that
+// was not committed, so the batch is written whole or not at all.
+var (
+	journalBucket    = []byte("BadgerJournal")
+	journalCommitKey = dbKey([]byte("BadgerJournalCommit"), nil)
+)
+
+func journalKey(i uint64) []byte {
+	var n [8]byte
+	binary.BigEndian.PutUint64(n[:], i)
+	return dbKey(journalBucket, n[:])
+}
+
+// putInJournal writes the records through the journal
+func (db *BadgerDB) putInJournal(keys [][]byte, values [][]byte) error {
+	db.journal.Lock()
+	defer db.journal.Unlock()
+
+	// a batch that failed earlier in the run is finished or dropped first, so its records are not
+	// mixed in with these
+	if err := db.recoverJournal(); err != nil {
+		return err
+	}
+	if err := db.stageJournal(keys, values); err != nil {
+		return err
+	}
+	if err := db.commitJournal(uint64(len(keys))); err != nil {
+		return err
+	}
+	return db.applyJournal(uint64(len(keys)))
+}
+
+// stageJournal writes the records into the journal bucket
+func (db *BadgerDB) stageJournal(keys [][]byte, values [][]byte) error {
+	wb := db.bDB.NewWriteBatch()
+	defer wb.Cancel()
+	for i := range keys {
+		record := make([]byte, binary.MaxVarintLen64, binary.MaxVarintLen64+len(keys[i])+len(values[i]))
+		record = append(record[:binary.PutUvarint(record, uint64(len(keys[i])))], keys[i]...)
+		record = append(record, values[i]...)
+		if err := wb.Set(journalKey(uint64(i)), record); err != nil {
+			return err
+		}
+	}
+	return wb.Flush()
+}
+
+// commitJournal writes the commit marker, from here on the batch counts as written
+func (db *BadgerDB) commitJournal(count uint64) error {
+	var n [8]byte
+	binary.BigEndian.PutUint64(n[:], count)
+	return db.bDB.Update(func(txn *badger.Txn) error {
+		return txn.Set(journalCommitKey, n[:])
+	})
+}
+
+// recoverJournal finishes a committed batch, and drops the records of one that was not committed
+func (db *BadgerDB) recoverJournal() error {
+	var count uint64
+	err := db.bDB.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(journalCommitKey)
+		if err != nil {
+			return err
+		}
+		return item.Value(func(v []byte) error {
+			if len(v) != 8 {
+				return fmt.Errorf("The journal commit marker is %d bytes long", len(v))
+			}
+			count = binary.BigEndian.Uint64(v)
+			return nil
+		})
+	})
+	if err == badger.ErrKeyNotFound {
+		return db.dropJournal()
+	}
+	if err != nil {
+		return err
+	}
+	return db.applyJournal(count)
+}
+
+// applyJournal copies the records of the committed batch to their keys, then removes the commit
+// marker and the journal. Copying them again after a crash writes the same values.
+func (db *BadgerDB) applyJournal(count uint64) error {
+	prefix := dbKey(journalBucket, nil)
+	// the write batch commits as it fills up, the journal is counted before any record is copied
+	var staged uint64
+	err := db.bDB.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		opts.Prefix = prefix
+		iter := txn.NewIterator(opts)
+		defer iter.Close()
+
+		for iter.Rewind(); iter.Valid(); iter.Next() {
+			staged++
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if staged != count {
+		return fmt.Errorf("The journal holds %d of the %d records of the batch", staged, count)
+	}
+
+	wb := db.bDB.NewWriteBatch()
+	defer wb.Cancel()
+	err = db.bDB.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = prefix
+		iter := txn.NewIterator(opts)
+		defer iter.Close()
+
+		for iter.Rewind(); iter.Valid(); iter.Next() {
+			record, err := iter.Item().ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			n, l := binary.Uvarint(record)
+			if l <= 0 || uint64(len(record)-l) < n {
+				return fmt.Errorf("Journal record %x is malformed", iter.Item().Key()[len(prefix):])
+			}
+			if err := wb.Set(record[l:l+int(n)], record[l+int(n):]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := wb.Flush(); err != nil {
+		return err
+	}
+
+	err = db.bDB.Update(func(txn *badger.Txn) error {
+		return txn.Delete(journalCommitKey)
+	})
+	if err != nil {
+		return err
+	}
+	return db.dropJournal()
+}
+
+// dropJournal deletes the staged records of the journal
+func (db *BadgerDB) dropJournal() error {
+	wb := db.bDB.NewWriteBatch()
+	defer wb.Cancel()
+
+	err := db.bDB.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		opts.Prefix = dbKey(journalBucket, nil)
+		iter := txn.NewIterator(opts)
+		defer iter.Close()
+
+		for iter.Rewind(); iter.Valid(); iter.Next() {
+			if err := wb.Delete(iter.Item().KeyCopy(nil)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return wb.Flush()
+}
@@ -0,0 +1,67 @@
+package badgerdb
+
+import (
+	"os"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+func TestJournalRecovery(t *testing.T) {
+	for _, committed := range []bool{true, false} {
+		path := "journalTest.badger"
+		os.RemoveAll(path)
+
+		db, err := NewBadgerDB(path, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var keys, values [][]byte
+		for i := 0; i < 100; i++ {
+			keys = append(keys, dbKey([]byte("bucket"), primitives.Sha([]byte{byte(i)}).Bytes()))
+			values = append(values, primitives.Sha([]byte{byte(i), 1}).Bytes())
+		}
+
+		// a crash after staging the batch, with or without the commit marker
+		if err := db.stageJournal(keys, values); err != nil {
+			t.Fatal(err)
+		}
+		if committed {
+			if err := db.commitJournal(uint64(len(keys))); err != nil {
+				t.Fatal(err)
+			}
+		}
+		db.Close()
+
+		db, err = NewBadgerDB(path, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		written, err := db.ListAllKeys([]byte("bucket"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if committed && len(written) != len(keys) {
+			t.Errorf("Expected the %v records of the committed batch, found %v", len(keys), len(written))
+		}
+		if !committed && len(written) != 0 {
+			t.Errorf("Expected none of the uncommitted batch, found %v records", len(written))
+		}
+		journal, err := db.ListAllKeys(journalBucket)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(journal) != 0 {
+			t.Errorf("%v records were left in the journal", len(journal))
+		}
+		marked, err := db.DoesKeyExist([]byte("BadgerJournalCommit"), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if marked {
+			t.Error("The commit marker was left")
+		}
+		db.Close()
+		os.RemoveAll(path)
+	}
+}
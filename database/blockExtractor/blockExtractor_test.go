@@ -1,6 +1,8 @@
 package blockExtractor_test
 
 import (
+	"io/ioutil"
+	"os"
 	"testing"
 
 	. "github.com/FactomProject/factomd/database/blockExtractor"
@@ -10,9 +12,16 @@ import (
 func TestTest(t *testing.T) {
 	dbo := testHelper.CreateAndPopulateTestDatabaseOverlay()
 
+	dir, err := ioutil.TempDir("", "blockextractor")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
 	be := new(BlockExtractor)
+	be.DataStorePath = dir + "/"
 
-	err := be.ExportDChain(dbo)
+	err = be.ExportDChain(dbo)
 	if err != nil {
 		t.Error(err)
 	}
@@ -5,6 +5,7 @@
 package boltdb
 
 import (
+	"bytes"
 	"fmt"
 	"sync"
 
@@ -189,7 +190,8 @@ func (db *BoltDB) Clear(bucket []byte) error {
 
 	err := db.db.Update(func(tx *bolt.Tx) error {
 		err := tx.DeleteBucket(bucket)
-		if err != nil {
+		// a bucket that was never written to is already clear
+		if err != nil && err != bolt.ErrBucketNotFound {
 			return fmt.Errorf("No bucket: %s", err)
 		}
 		return nil
@@ -248,6 +250,150 @@ func (db *BoltDB) GetAll(bucket []byte, sample interfaces.BinaryMarshallableAndC
 	return answer, keys, nil
 }
 
+// boltIteratorPageSize is the number of records an iterator reads per read transaction
+var boltIteratorPageSize = 256
+
+// Iterate walks over the records of the bucket selected by the options. The records are read
+// in pages, each in its own read transaction, so no transaction is held open between calls.
+func (db *BoltDB) Iterate(bucket []byte, options interfaces.IterateOptions) (interfaces.IIterator, error) {
+	it := new(boltIterator)
+	it.db = db
+	it.bucket = append([]byte{}, bucket...)
+	it.options = options
+	it.index = -1
+	return it, nil
+}
+
+type boltRecord struct {
+	key   []byte
+	value []byte
+}
+
+type boltIterator struct {
+	db      *BoltDB
+	bucket  []byte
+	options interfaces.IterateOptions
+
+	page  []boltRecord
+	index int
+	count int
+	last  []byte // key of the last record read, nil before the first page
+	done  bool   // no records left after the current page
+	err   error
+}
+
+var _ interfaces.IIterator = (*boltIterator)(nil)
+
+func (it *boltIterator) Next() bool {
+	if it.err != nil || (it.options.Limit > 0 && it.count >= it.options.Limit) {
+		return false
+	}
+	it.index++
+	if it.index >= len(it.page) {
+		if it.done {
+			it.page = nil
+			return false
+		}
+		it.err = it.readPage()
+		it.index = 0
+		if it.err != nil || len(it.page) == 0 {
+			return false
+		}
+	}
+	it.count++
+	return true
+}
+
+// readPage reads the records following the last one read
+func (it *boltIterator) readPage() error {
+	it.db.Sem.RLock()
+	defer it.db.Sem.RUnlock()
+
+	it.page = it.page[:0]
+	return it.db.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(it.bucket)
+		if b == nil {
+			it.done = true
+			return nil
+		}
+		c := b.Cursor()
+		k, v := it.seek(c)
+		for ; k != nil && it.options.InRange(k); k, v = it.step(c) {
+			if len(it.page) == boltIteratorPageSize {
+				return nil
+			}
+			it.page = append(it.page, boltRecord{key: append([]byte{}, k...), value: append([]byte{}, v...)})
+			it.last = it.page[len(it.page)-1].key
+		}
+		it.done = true
+		return nil
+	})
+}
+
+// seek positions the cursor on the first record to read
+func (it *boltIterator) seek(c *bolt.Cursor) ([]byte, []byte) {
+	if !it.options.Reverse {
+		if it.last != nil {
+			k, v := c.Seek(it.last)
+			if k != nil && bytes.Equal(k, it.last) {
+				return c.Next()
+			}
+			return k, v
+		}
+		if lower := it.options.Lower(); lower != nil {
+			return c.Seek(lower)
+		}
+		return c.First()
+	}
+
+	bound := it.options.Upper()
+	if it.last != nil {
+		bound = it.last
+	}
+	if bound == nil {
+		return c.Last()
+	}
+	// the last record before the bound
+	if k, _ := c.Seek(bound); k == nil {
+		return c.Last()
+	}
+	return c.Prev()
+}
+
+func (it *boltIterator) step(c *bolt.Cursor) ([]byte, []byte) {
+	if it.options.Reverse {
+		return c.Prev()
+	}
+	return c.Next()
+}
+
+func (it *boltIterator) Key() []byte {
+	if it.index < 0 || it.index >= len(it.page) {
+		return nil
+	}
+	return it.page[it.index].key
+}
+
+func (it *boltIterator) Value(destination interfaces.BinaryMarshallable) (interfaces.BinaryMarshallable, error) {
+	if it.index < 0 || it.index >= len(it.page) {
+		return nil, nil
+	}
+	_, err := destination.UnmarshalBinaryData(it.page[it.index].value)
+	if err != nil {
+		return nil, err
+	}
+	return destination, nil
+}
+
+func (it *boltIterator) Error() error {
+	return it.err
+}
+
+func (it *boltIterator) Release() {
+	it.page = nil
+	it.done = true
+}
+
 // We have to make accommodation for many Init functions.  But what we really
 // want here is:
 //
@@ -303,3 +449,51 @@ func (db *BoltDB) DoesKeyExist(bucket, key []byte) (bool, error) {
 
 	return true, nil
 }
+
+var _ interfaces.ISnapshotDatabase = (*BoltDB)(nil)
+
+// Snapshot takes a view of the database as it is now, writes made afterwards are not part of it.
+// The view is a read transaction, Bolt can not grow the database file while it is open. A write
+// that needs a larger file waits until the view is released, so on a large database the writes
+// of the node stall for as long as the copy takes.
+func (db *BoltDB) Snapshot() (interfaces.IDatabaseSnapshot, error) {
+	db.Sem.RLock()
+	defer db.Sem.RUnlock()
+
+	tx, err := db.db.Begin(false)
+	if err != nil {
+		return nil, err
+	}
+	return &boltSnapshot{tx: tx}, nil
+}
+
+type boltSnapshot struct {
+	tx *bolt.Tx
+}
+
+// CopyTo copies the whole file within the read transaction of the view, writers that need to
+// grow the file wait for it.
+func (s *boltSnapshot) CopyTo(path string) (uint64, error) {
+	if _, err := os.Stat(path); err == nil {
+		return 0, fmt.Errorf("%v already exists", path)
+	}
+	err := os.MkdirAll(filepath.Dir(path), 0750)
+	if err != nil {
+		return 0, err
+	}
+	err = s.tx.CopyFile(path, 0600)
+	if err != nil {
+		return 0, err
+	}
+
+	var count uint64
+	err = s.tx.ForEach(func(name []byte, b *bolt.Bucket) error {
+		count += uint64(b.Stats().KeyN)
+		return nil
+	})
+	return count, err
+}
+
+func (s *boltSnapshot) Release() {
+	s.tx.Rollback()
+}
@@ -0,0 +1,135 @@
+package boltdb
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/FactomProject/bolt"
+	"github.com/FactomProject/factomd/common/interfaces"
+)
+
+// boltCompactTxSize is the number of records written to the compacted copy per transaction
+const boltCompactTxSize = 10000
+
+var _ interfaces.ICompactDatabase = (*BoltDB)(nil)
+
+// Compact rewrites the database into a new file without the free pages, then puts the new file
+// in place of the old one. Bolt never shrinks its file otherwise. Every other call waits until
+// the compaction is done, which stalls a node using the database for the whole copy, so Bolt
+// databases are compacted with the node stopped. If the new file cannot be opened, the old one
+// is put back.
+func (db *BoltDB) Compact() error {
+	db.Sem.Lock()
+	defer db.Sem.Unlock()
+
+	path := db.db.Path()
+	tmp := path + ".compact"
+	os.Remove(tmp)
+	dst, err := bolt.Open(tmp, 0600, nil)
+	if err != nil {
+		return err
+	}
+	err = db.db.View(func(tx *bolt.Tx) error {
+		return tx.ForEach(func(name []byte, b *bolt.Bucket) error {
+			return copyBucket(dst, name, b)
+		})
+	})
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	// the old file is kept until the new one is open
+	old := path + ".old"
+	os.Remove(old)
+	if err := db.db.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	err = os.Rename(path, old)
+	if err == nil {
+		err = os.Rename(tmp, path)
+		if err == nil {
+			var tdb *bolt.DB
+			tdb, err = bolt.Open(path, 0600, nil)
+			if err == nil {
+				db.db = tdb
+				os.Remove(old)
+				return nil
+			}
+		}
+		if rerr := os.Rename(old, path); rerr != nil {
+			return fmt.Errorf("%v, and the old database could not be put back from %v: %v", err, old, rerr)
+		}
+	}
+	os.Remove(tmp)
+
+	tdb, oerr := bolt.Open(path, 0600, nil)
+	if oerr != nil {
+		return fmt.Errorf("%v, and the old database could not be reopened: %v", err, oerr)
+	}
+	db.db = tdb
+	return err
+}
+
+func copyBucket(dst *bolt.DB, name []byte, src *bolt.Bucket) error {
+	c := src.Cursor()
+	k, v := c.First()
+	for {
+		// every transaction copies a limited number of records
+		err := dst.Update(func(tx *bolt.Tx) error {
+			b, err := tx.CreateBucketIfNotExists(name)
+			if err != nil {
+				return err
+			}
+			// the records are written in order, pages can be filled completely
+			b.FillPercent = 1
+			for n := 0; k != nil && n < boltCompactTxSize; n++ {
+				if v == nil {
+					return fmt.Errorf("Bucket %x has nested buckets", name)
+				}
+				if err := b.Put(k, v); err != nil {
+					return err
+				}
+				k, v = c.Next()
+			}
+			return nil
+		})
+		if err != nil || k == nil {
+			return err
+		}
+	}
+}
+
+// BucketSize returns the space of the pages holding the bucket
+func (db *BoltDB) BucketSize(bucket []byte) (int64, error) {
+	db.Sem.RLock()
+	defer db.Sem.RUnlock()
+
+	var size int64
+	err := db.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucket)
+		if b == nil {
+			return nil
+		}
+		s := b.Stats()
+		size = int64(s.BranchAlloc + s.LeafAlloc)
+		return nil
+	})
+	return size, err
+}
+
+// Size returns the size of the database file, free pages included
+func (db *BoltDB) Size() (int64, error) {
+	db.Sem.RLock()
+	defer db.Sem.RUnlock()
+
+	info, err := os.Stat(db.db.Path())
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
@@ -0,0 +1,258 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+// Package compact reclaims the space of a node's database, and rebuilds the indexes that are
+// derived from the blocks.
+package compact
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+)
+
+// OtherBuckets is the name the report gives to the space outside the named buckets: the content
+// of the entries, the entry block numbers of each chain, and the free space of the database
+const OtherBuckets = "Other"
+
+// Rebuilders drop a bucket derived from the blocks and rebuild it, by bucket name
+var Rebuilders = map[string]func(dbo *databaseOverlay.Overlay) error{
+	"IncludedIn":   RebuildIncludedIn,
+	"PaidFor":      RebuildPaidFor,
+	"DirBlockInfo": RebuildDirBlockInfo,
+}
+
+// BucketSpace is the space a bucket uses before and after the compaction, in bytes
+type BucketSpace struct {
+	Bucket string `json:"bucket"`
+	Before int64  `json:"before"`
+	After  int64  `json:"after"`
+}
+
+type Report struct {
+	Buckets []BucketSpace `json:"buckets"`
+	// Size of the database on disk
+	Before   int64    `json:"before"`
+	After    int64    `json:"after"`
+	Rebuilt  []string `json:"rebuilt,omitempty"`
+	Duration string   `json:"duration"`
+}
+
+// Run rebuilds the derived buckets given by name, then compacts the database and reports the
+// space of each bucket before and after. While a bucket is rebuilt, lookups in it miss, so
+// buckets are only rebuilt on a stopped node.
+func Run(dbo *databaseOverlay.Overlay, rebuild []string, progress func(msg string)) (*Report, error) {
+	if progress == nil {
+		progress = func(string) {}
+	}
+	compacter, ok := dbo.DB.(interfaces.ICompactDatabase)
+	if !ok {
+		return nil, fmt.Errorf("The database does not support compaction")
+	}
+	for _, name := range rebuild {
+		if _, ok := Rebuilders[name]; !ok {
+			return nil, fmt.Errorf("%v can not be rebuilt, only %v", name, strings.Join(RebuildableBuckets(), ", "))
+		}
+	}
+
+	start := time.Now()
+	r := new(Report)
+	before, total, err := measure(compacter)
+	if err != nil {
+		return nil, err
+	}
+	r.Before = total
+
+	for _, name := range rebuild {
+		progress(fmt.Sprintf("Rebuilding %v", name))
+		if err := Rebuilders[name](dbo); err != nil {
+			return nil, fmt.Errorf("Rebuilding %v failed: %v", name, err)
+		}
+		r.Rebuilt = append(r.Rebuilt, name)
+	}
+
+	progress("Compacting")
+	if err := compacter.Compact(); err != nil {
+		return nil, err
+	}
+
+	after, total, err := measure(compacter)
+	if err != nil {
+		return nil, err
+	}
+	r.After = total
+	for name := range before {
+		r.Buckets = append(r.Buckets, BucketSpace{Bucket: name, Before: before[name], After: after[name]})
+	}
+	sort.Slice(r.Buckets, func(i, j int) bool {
+		return r.Buckets[i].Bucket < r.Buckets[j].Bucket
+	})
+	r.Duration = time.Since(start).String()
+	return r, nil
+}
+
+// RebuildableBuckets returns the names of the buckets that can be rebuilt
+func RebuildableBuckets() []string {
+	names := []string{}
+	for name := range Rebuilders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// measure returns the space of the named buckets, and of everything else under OtherBuckets,
+// along with the size of the database on disk
+func measure(compacter interfaces.ICompactDatabase) (map[string]int64, int64, error) {
+	total, err := compacter.Size()
+	if err != nil {
+		return nil, 0, err
+	}
+	sizes := map[string]int64{}
+	other := total
+	for bucket, name := range databaseOverlay.ConstantNamesMap {
+		size, err := compacter.BucketSize([]byte(bucket))
+		if err != nil {
+			return nil, 0, err
+		}
+		sizes[name] = size
+		other -= size
+	}
+	// the bucket sizes are estimates, they can add up to a bit more than the files
+	if other < 0 {
+		other = 0
+	}
+	sizes[OtherBuckets] = other
+	return sizes, total, nil
+}
+
+// eachHeight calls f with every directory block, in order. Blocks saved while it runs are
+// included.
+func eachHeight(dbo *databaseOverlay.Overlay, f func(dblock interfaces.IDirectoryBlock) error) error {
+	for h := uint32(0); ; h++ {
+		dblock, err := dbo.FetchDBlockByHeight(h)
+		if err != nil {
+			return err
+		}
+		if dblock == nil {
+			return nil
+		}
+		if err := f(dblock); err != nil {
+			return err
+		}
+	}
+}
+
+// multiBatch runs f inside a multi batch of the overlay, so it does not interleave with the
+// blocks the node saves
+func multiBatch(dbo *databaseOverlay.Overlay, f func() error) error {
+	dbo.StartMultiBatch()
+	err := f()
+	if xerr := dbo.ExecuteMultiBatch(); err == nil {
+		err = xerr
+	}
+	return err
+}
+
+// RebuildIncludedIn rebuilds the index of the block each entry, transaction and block is
+// included in, the same way the blocks are saved
+func RebuildIncludedIn(dbo *databaseOverlay.Overlay) error {
+	if err := dbo.Clear(databaseOverlay.INCLUDED_IN); err != nil {
+		return err
+	}
+	return eachHeight(dbo, func(dblock interfaces.IDirectoryBlock) error {
+		height := dblock.GetDatabaseHeight()
+		fblock, err := dbo.FetchFBlockByHeight(height)
+		if err != nil {
+			return err
+		}
+		ecblock, err := dbo.FetchECBlockByHeight(height)
+		if err != nil {
+			return err
+		}
+		eblocks := []interfaces.IEntryBlock{}
+		for _, dbEntry := range dblock.GetEBlockDBEntries() {
+			eblock, err := dbo.FetchEBlock(dbEntry.GetKeyMR())
+			if err != nil {
+				return err
+			}
+			if eblock != nil {
+				eblocks = append(eblocks, eblock)
+			}
+		}
+
+		return multiBatch(dbo, func() error {
+			if fblock != nil {
+				if err := dbo.SaveIncludedInMultiFromBlockMultiBatch(fblock, true); err != nil {
+					return err
+				}
+			}
+			if ecblock != nil {
+				if err := dbo.SaveIncludedInMultiFromBlockMultiBatch(ecblock, true); err != nil {
+					return err
+				}
+			}
+			for _, eblock := range eblocks {
+				if err := dbo.SaveIncludedInMultiFromBlockMultiBatch(eblock, true); err != nil {
+					return err
+				}
+			}
+			return dbo.SaveIncludedInMultiFromBlockMultiBatch(dblock, true)
+		})
+	})
+}
+
+// RebuildPaidFor rebuilds the index of the commit that paid for each entry
+func RebuildPaidFor(dbo *databaseOverlay.Overlay) error {
+	if err := dbo.Clear(databaseOverlay.PAID_FOR); err != nil {
+		return err
+	}
+	return eachHeight(dbo, func(dblock interfaces.IDirectoryBlock) error {
+		ecblock, err := dbo.FetchECBlockByHeight(dblock.GetDatabaseHeight())
+		if err != nil || ecblock == nil {
+			return err
+		}
+		return multiBatch(dbo, func() error {
+			return dbo.SavePaidForMultiFromBlockMultiBatch(ecblock, false)
+		})
+	})
+}
+
+// RebuildDirBlockInfo rebuilds the anchor information from the anchor chains, with the anchor
+// record keys set on the overlay
+func RebuildDirBlockInfo(dbo *databaseOverlay.Overlay) error {
+	for _, bucket := range [][]byte{databaseOverlay.DIRBLOCKINFO, databaseOverlay.DIRBLOCKINFO_UNCONFIRMED,
+		databaseOverlay.DIRBLOCKINFO_NUMBER, databaseOverlay.DIRBLOCKINFO_SECONDARYINDEX} {
+		if err := dbo.Clear(bucket); err != nil {
+			return err
+		}
+	}
+	for _, chain := range []string{databaseOverlay.BitcoinAnchorChainID, databaseOverlay.EthereumAnchorChainID} {
+		chainID, err := primitives.NewShaHashFromStr(chain)
+		if err != nil {
+			return err
+		}
+		entries, err := dbo.FetchAllEntriesByChainID(chainID)
+		if err != nil {
+			return err
+		}
+		// an entry updates the info the entries before it saved, so each one is written on its own
+		for _, entry := range entries {
+			err := multiBatch(dbo, func() error {
+				// entries that are not valid anchor records are skipped, as the node does
+				_ = dbo.SaveAnchorInfoFromEntry(entry, true)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
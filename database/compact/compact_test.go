@@ -0,0 +1,120 @@
+package compact_test
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/directoryBlock/dbInfo"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/badgerdb"
+	"github.com/FactomProject/factomd/database/boltdb"
+	. "github.com/FactomProject/factomd/database/compact"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+	"github.com/FactomProject/factomd/database/leveldb"
+	"github.com/FactomProject/factomd/testHelper"
+	"github.com/stretchr/testify/assert"
+)
+
+// dump returns the records of the bucket, hex encoded
+func dump(t *testing.T, db interfaces.IDatabase, bucket []byte) map[string]string {
+	records := map[string]string{}
+	it, err := db.Iterate(bucket, interfaces.IterateOptions{})
+	if !assert.Nil(t, err) {
+		return records
+	}
+	defer it.Release()
+	for it.Next() {
+		v, err := it.Value(new(primitives.ByteSlice))
+		assert.Nil(t, err)
+		records[hex.EncodeToString(it.Key())] = hex.EncodeToString(v.(*primitives.ByteSlice).Bytes)
+	}
+	assert.Nil(t, it.Error())
+	return records
+}
+
+func TestRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "compact")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	open := map[string]func(path string) (interfaces.IDatabase, error){
+		"LDB": func(path string) (interfaces.IDatabase, error) { return leveldb.NewLevelDB(path, true) },
+		"Bolt": func(path string) (interfaces.IDatabase, error) {
+			return boltdb.NewAndCreateBoltDB(nil, path), nil
+		},
+		"Badger": func(path string) (interfaces.IDatabase, error) { return badgerdb.NewBadgerDB(path, true) },
+	}
+	derived := [][]byte{databaseOverlay.INCLUDED_IN, databaseOverlay.PAID_FOR, databaseOverlay.DIRBLOCKINFO, databaseOverlay.DIRBLOCKINFO_UNCONFIRMED}
+	for dbType, f := range open {
+		db, err := f(filepath.Join(dir, dbType, "test.db"))
+		if !assert.Nil(t, err, dbType) {
+			continue
+		}
+		dbo := databaseOverlay.NewOverlay(db)
+		testHelper.PopulateTestDatabaseOverlay(dbo)
+
+		before := map[string]map[string]string{}
+		for _, bucket := range derived {
+			before[string(bucket)] = dump(t, db, bucket)
+		}
+		assert.NotEmpty(t, before[string(databaseOverlay.INCLUDED_IN)], dbType)
+		assert.NotEmpty(t, before[string(databaseOverlay.PAID_FOR)], dbType)
+
+		// anchor info that no anchor record backs is dropped
+		stale := dbInfo.NewDirBlockInfo()
+		stale.DBHash = primitives.Sha([]byte("stale"))
+		stale.DBMerkleRoot = stale.DBHash
+		assert.Nil(t, dbo.ProcessDirBlockInfoBatch(stale), dbType)
+		assert.NotEqual(t, before[string(databaseOverlay.DIRBLOCKINFO_UNCONFIRMED)], dump(t, db, databaseOverlay.DIRBLOCKINFO_UNCONFIRMED), dbType)
+
+		// space to reclaim
+		junk := []byte("junk")
+		batch := []interfaces.Record{}
+		for i := 0; i < 4000; i++ {
+			key := primitives.Sha([]byte{byte(i), byte(i >> 8)})
+			batch = append(batch, interfaces.Record{Bucket: junk, Key: key.Bytes(), Data: &primitives.ByteSlice{Bytes: make([]byte, 1024)}})
+		}
+		assert.Nil(t, db.PutInBatch(batch), dbType)
+		assert.Nil(t, db.Clear(junk), dbType)
+
+		r, err := Run(dbo, RebuildableBuckets(), nil)
+		if !assert.Nil(t, err, dbType) {
+			dbo.Close()
+			continue
+		}
+		assert.Equal(t, RebuildableBuckets(), r.Rebuilt, dbType)
+		assert.NotZero(t, r.Before, dbType)
+		assert.NotZero(t, r.After, dbType)
+		if dbType == "Bolt" {
+			// Bolt only gives the free pages back by rewriting the file
+			assert.True(t, r.After < r.Before, dbType)
+		}
+
+		names := map[string]bool{}
+		for _, b := range r.Buckets {
+			names[b.Bucket] = true
+			assert.True(t, b.Before >= 0 && b.After >= 0, dbType)
+		}
+		assert.True(t, names["IncludedIn"], dbType)
+		assert.True(t, names["DirectoryBlock"], dbType)
+		assert.True(t, names[OtherBuckets], dbType)
+
+		// the rebuilt buckets are the same as the ones written with the blocks
+		for _, bucket := range derived {
+			assert.Equal(t, before[string(bucket)], dump(t, db, bucket), dbType+" "+string(bucket))
+		}
+		head, err := dbo.FetchDBlockHead()
+		assert.Nil(t, err, dbType)
+		assert.Equal(t, uint32(testHelper.BlockCount-1), head.GetDatabaseHeight(), dbType)
+
+		_, err = Run(dbo, []string{"DirectoryBlock"}, nil)
+		assert.NotNil(t, err, dbType)
+		dbo.Close()
+	}
+}
@@ -0,0 +1,162 @@
+package databaseOverlay
+
+import (
+	"encoding/binary"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// The address transaction index is optional. Each address has its own bucket, keyed by
+// height + transaction id so the transactions of an address are ordered by height.
+
+var AddressIndexHeightKey = []byte("AddressIndexHeight")
+
+func addressTransactionKey(height uint32, txid interfaces.IHash) []byte {
+	key := make([]byte, 4, 4+constants.HASH_LENGTH)
+	binary.BigEndian.PutUint32(key, height)
+	return append(key, txid.Bytes()...)
+}
+
+func factoidAddressBucket(address []byte) []byte {
+	return append(append([]byte{}, FACTOID_ADDRESS_TRANSACTIONS...), address...)
+}
+
+func ecAddressBucket(address []byte) []byte {
+	return append(append([]byte{}, EC_ADDRESS_TRANSACTIONS...), address...)
+}
+
+// AddressTransactionRecords returns the index records of every address touched by the
+// transactions of the given blocks
+func AddressTransactionRecords(height uint32, fblock interfaces.IFBlock, ecblock interfaces.IEntryCreditBlock) []interfaces.Record {
+	batch := []interfaces.Record{}
+
+	if fblock != nil {
+		for _, tx := range fblock.GetTransactions() {
+			txid := tx.GetSigHash()
+			key := addressTransactionKey(height, txid)
+			for _, in := range tx.GetInputs() {
+				batch = append(batch, interfaces.Record{Bucket: factoidAddressBucket(in.GetAddress().Bytes()), Key: key, Data: txid})
+			}
+			for _, out := range tx.GetOutputs() {
+				batch = append(batch, interfaces.Record{Bucket: factoidAddressBucket(out.GetAddress().Bytes()), Key: key, Data: txid})
+			}
+			for _, out := range tx.GetECOutputs() {
+				batch = append(batch, interfaces.Record{Bucket: ecAddressBucket(out.GetAddress().Bytes()), Key: key, Data: txid})
+			}
+		}
+	}
+
+	if ecblock != nil {
+		for _, entry := range ecblock.GetEntries() {
+			var pub *primitives.ByteSlice32
+			switch entry.ECID() {
+			case constants.ECIDChainCommit:
+				pub = entry.(*entryCreditBlock.CommitChain).ECPubKey
+			case constants.ECIDEntryCommit:
+				pub = entry.(*entryCreditBlock.CommitEntry).ECPubKey
+			default:
+				continue
+			}
+			txid := entry.GetEntryHash()
+			batch = append(batch, interfaces.Record{Bucket: ecAddressBucket(pub[:]), Key: addressTransactionKey(height, txid), Data: txid})
+		}
+	}
+
+	return batch
+}
+
+// addressTransactionBatch returns the index records of the blocks at the given height, and the
+// record that the index is complete up to that height
+func addressTransactionBatch(height uint32, fblock interfaces.IFBlock, ecblock interfaces.IEntryCreditBlock) []interfaces.Record {
+	batch := AddressTransactionRecords(height, fblock, ecblock)
+
+	buf := primitives.NewBuffer(nil)
+	buf.PushUInt32(height + 1)
+	bs := new(primitives.ByteSlice)
+	bs.Bytes = buf.DeepCopyBytes()
+	return append(batch, interfaces.Record{Bucket: KEY_VALUE_STORE, Key: AddressIndexHeightKey, Data: bs})
+}
+
+// SaveAddressTransactions indexes the transactions of the blocks at the given height, and
+// records that the index is complete up to that height
+func (db *Overlay) SaveAddressTransactions(height uint32, fblock interfaces.IFBlock, ecblock interfaces.IEntryCreditBlock) error {
+	return db.DB.PutInBatch(addressTransactionBatch(height, fblock, ecblock))
+}
+
+// ProcessAddressTransactionsMultiBatch indexes the transactions of the blocks at the given height
+// in the multi batch, so the index is written together with the blocks
+func (db *Overlay) ProcessAddressTransactionsMultiBatch(height uint32, fblock interfaces.IFBlock, ecblock interfaces.IEntryCreditBlock) error {
+	db.PutInMultiBatch(addressTransactionBatch(height, fblock, ecblock))
+	return nil
+}
+
+// FetchAddressIndexHeight returns the next height to be added to the address transaction index
+func (db *Overlay) FetchAddressIndexHeight() (uint32, error) {
+	bs := new(primitives.ByteSlice)
+	loaded, err := db.FetchKeyValueStore(AddressIndexHeightKey, bs)
+	if err != nil {
+		return 0, err
+	}
+	if loaded == nil {
+		return 0, nil
+	}
+	buf := primitives.NewBuffer(bs.Bytes)
+	return buf.PopUInt32()
+}
+
+func (db *Overlay) FetchFactoidAddressTransactions(address interfaces.IHash) ([]interfaces.AddressTransaction, error) {
+	txs, _, err := db.fetchAddressTransactions(factoidAddressBucket(address.Bytes()), 0, 0)
+	return txs, err
+}
+
+func (db *Overlay) FetchECAddressTransactions(address interfaces.IHash) ([]interfaces.AddressTransaction, error) {
+	txs, _, err := db.fetchAddressTransactions(ecAddressBucket(address.Bytes()), 0, 0)
+	return txs, err
+}
+
+// FetchFactoidAddressTransactionsPage returns at most limit transactions of the address, skipping
+// the first offset of them, and the number of transactions of the address
+func (db *Overlay) FetchFactoidAddressTransactionsPage(address interfaces.IHash, offset int, limit int) ([]interfaces.AddressTransaction, int, error) {
+	return db.fetchAddressTransactions(factoidAddressBucket(address.Bytes()), offset, limit)
+}
+
+// FetchECAddressTransactionsPage returns at most limit transactions of the address, skipping the
+// first offset of them, and the number of transactions of the address
+func (db *Overlay) FetchECAddressTransactionsPage(address interfaces.IHash, offset int, limit int) ([]interfaces.AddressTransaction, int, error) {
+	return db.fetchAddressTransactions(ecAddressBucket(address.Bytes()), offset, limit)
+}
+
+// fetchAddressTransactions walks the keys of the bucket, which are ordered by height, and only
+// reads the transactions of the page. A limit of 0 reads all of them past the offset
+func (db *Overlay) fetchAddressTransactions(bucket []byte, offset int, limit int) ([]interfaces.AddressTransaction, int, error) {
+	it, err := db.Iterate(bucket, interfaces.IterateOptions{})
+	if err != nil {
+		return nil, 0, err
+	}
+	defer it.Release()
+
+	answer := []interfaces.AddressTransaction{}
+	total := 0
+	for it.Next() {
+		k := it.Key()
+		if len(k) < 4 {
+			continue
+		}
+		total++
+		if total <= offset || (limit > 0 && len(answer) >= limit) {
+			continue
+		}
+		txid, err := it.Value(new(primitives.Hash))
+		if err != nil {
+			return nil, 0, err
+		}
+		answer = append(answer, interfaces.AddressTransaction{Height: binary.BigEndian.Uint32(k), TxID: txid.(interfaces.IHash)})
+	}
+	if err := it.Error(); err != nil {
+		return nil, 0, err
+	}
+	return answer, total, nil
+}
@@ -0,0 +1,156 @@
+package databaseOverlay_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/testHelper"
+)
+
+func TestAddressTransactions(t *testing.T) {
+	blocks := CreateFullTestBlockSet()
+	dbo := CreateEmptyTestDatabaseOverlay()
+
+	next, err := dbo.FetchAddressIndexHeight()
+	if err != nil {
+		t.Error(err)
+	}
+	if next != 0 {
+		t.Errorf("Expected an empty index to start at 0, got %v", next)
+	}
+
+	for i, block := range blocks {
+		err := dbo.SaveAddressTransactions(uint32(i), block.FBlock, block.ECBlock)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+
+	next, err = dbo.FetchAddressIndexHeight()
+	if err != nil {
+		t.Error(err)
+	}
+	if int(next) != len(blocks) {
+		t.Errorf("Expected the index to continue at %v, got %v", len(blocks), next)
+	}
+
+	for i, block := range blocks {
+		for _, tx := range block.FBlock.GetTransactions() {
+			for _, in := range tx.GetInputs() {
+				txs, err := dbo.FetchFactoidAddressTransactions(in.GetAddress())
+				if err != nil {
+					t.Error(err)
+				}
+				if !containsTransaction(txs, uint32(i), tx.GetSigHash()) {
+					t.Errorf("Transaction %v not indexed for input %v", tx.GetSigHash(), in.GetAddress())
+				}
+			}
+			for _, out := range tx.GetOutputs() {
+				txs, err := dbo.FetchFactoidAddressTransactions(out.GetAddress())
+				if err != nil {
+					t.Error(err)
+				}
+				if !containsTransaction(txs, uint32(i), tx.GetSigHash()) {
+					t.Errorf("Transaction %v not indexed for output %v", tx.GetSigHash(), out.GetAddress())
+				}
+			}
+		}
+
+		for _, entry := range block.ECBlock.GetEntries() {
+			var pub *primitives.ByteSlice32
+			switch entry.ECID() {
+			case constants.ECIDChainCommit:
+				pub = entry.(*entryCreditBlock.CommitChain).ECPubKey
+			case constants.ECIDEntryCommit:
+				pub = entry.(*entryCreditBlock.CommitEntry).ECPubKey
+			default:
+				continue
+			}
+			txs, err := dbo.FetchECAddressTransactions(primitives.NewHash(pub[:]))
+			if err != nil {
+				t.Error(err)
+			}
+			if !containsTransaction(txs, uint32(i), entry.GetEntryHash()) {
+				t.Errorf("Commit of entry %v not indexed", entry.GetEntryHash())
+			}
+		}
+	}
+
+	txs, err := dbo.FetchFactoidAddressTransactions(primitives.NewZeroHash())
+	if err != nil {
+		t.Error(err)
+	}
+	if len(txs) != 0 {
+		t.Errorf("Expected no transactions for an unknown address, got %v", len(txs))
+	}
+}
+
+func TestAddressTransactionsPage(t *testing.T) {
+	blocks := CreateFullTestBlockSet()
+	dbo := CreateEmptyTestDatabaseOverlay()
+
+	// the index of a block is written with the multi batch
+	for i, block := range blocks {
+		dbo.StartMultiBatch()
+		if err := dbo.ProcessAddressTransactionsMultiBatch(uint32(i), block.FBlock, block.ECBlock); err != nil {
+			t.Error(err)
+		}
+		if err := dbo.ExecuteMultiBatch(); err != nil {
+			t.Error(err)
+		}
+	}
+	next, err := dbo.FetchAddressIndexHeight()
+	if err != nil {
+		t.Error(err)
+	}
+	if int(next) != len(blocks) {
+		t.Errorf("Expected the index to continue at %v, got %v", len(blocks), next)
+	}
+
+	address := blocks[len(blocks)-1].FBlock.GetTransactions()[0].GetOutputs()[0].GetAddress()
+	all, err := dbo.FetchFactoidAddressTransactions(address)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(all) < 2 {
+		t.Fatalf("Expected the address to have several transactions, got %v", len(all))
+	}
+
+	for offset := 0; offset <= len(all); offset++ {
+		page, total, err := dbo.FetchFactoidAddressTransactionsPage(address, offset, 2)
+		if err != nil {
+			t.Error(err)
+		}
+		if total != len(all) {
+			t.Errorf("Expected a total of %v, got %v", len(all), total)
+		}
+		expected := all[offset:]
+		if len(expected) > 2 {
+			expected = expected[:2]
+		}
+		if len(page) != len(expected) {
+			t.Errorf("Expected %v transactions at offset %v, got %v", len(expected), offset, len(page))
+			continue
+		}
+		for i := range page {
+			if page[i].Height != expected[i].Height || !page[i].TxID.IsSameAs(expected[i].TxID) {
+				t.Errorf("Transaction %v at offset %v does not match", i, offset)
+			}
+		}
+	}
+}
+
+func containsTransaction(txs []interfaces.AddressTransaction, height uint32, txid interfaces.IHash) bool {
+	for i, tx := range txs {
+		if i > 0 && txs[i-1].Height > tx.Height {
+			return false
+		}
+		if tx.Height == height && tx.TxID.IsSameAs(txid) {
+			return true
+		}
+	}
+	return false
+}
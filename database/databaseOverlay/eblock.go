@@ -1,6 +1,8 @@
 package databaseOverlay
 
 import (
+	"encoding/binary"
+
 	"github.com/FactomProject/factomd/common/entryBlock"
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/primitives"
@@ -112,6 +114,34 @@ func (db *Overlay) FetchAllEBlocksByChain(chainID interfaces.IHash) ([]interface
 	return list, nil
 }
 
+// FetchEBlockKeyMRFromHeight returns the KeyMR of the first entry block of the chain at or above
+// the directory block height, or at or below it in reverse, nil if there is none. A chain has at
+// most one entry block at a height, they are numbered by height so only one key is read
+func (db *Overlay) FetchEBlockKeyMRFromHeight(chainID interfaces.IHash, height uint32, reverse bool) (interfaces.IHash, error) {
+	bucket := append(append([]byte{}, ENTRYBLOCK_CHAIN_NUMBER...), chainID.Bytes()...)
+	key := make([]byte, 4)
+	binary.BigEndian.PutUint32(key, height)
+
+	opts := interfaces.IterateOptions{Start: key, Limit: 1}
+	if reverse {
+		opts = interfaces.IterateOptions{End: interfaces.PrefixEnd(key), Reverse: true, Limit: 1}
+	}
+	it, err := db.Iterate(bucket, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Release()
+
+	if !it.Next() {
+		return nil, it.Error()
+	}
+	keyMR, err := it.Value(new(primitives.Hash))
+	if err != nil {
+		return nil, err
+	}
+	return keyMR.(interfaces.IHash), nil
+}
+
 func (db *Overlay) SaveEBlockHead(block interfaces.DatabaseBlockWithEntries, checkForDuplicateEntries bool) error {
 	return db.ProcessEBlockBatch(block, checkForDuplicateEntries)
 }
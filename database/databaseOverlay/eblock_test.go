@@ -212,3 +212,60 @@ func TestFetchAllEBlockChainIDs(t *testing.T) {
 		t.Errorf("Got wrong number of chains - %v", len(chains))
 	}
 }
+
+func TestFetchEBlockKeyMRFromHeight(t *testing.T) {
+	dbo := NewOverlay(new(mapdb.MapDB))
+	defer dbo.Close()
+
+	// only the blocks at even heights are saved, so the lookups have to skip the gaps
+	var prev *EBlock = nil
+	keyMRs := map[uint32]string{}
+	for i := 0; i < 10; i++ {
+		prev, _ = testHelper.CreateTestEntryBlock(prev)
+		if i%2 == 1 {
+			continue
+		}
+		err := dbo.SaveEBlockHead(prev, false)
+		if err != nil {
+			t.Error(err)
+		}
+		keyMR, _ := prev.KeyMR()
+		keyMRs[prev.GetDatabaseHeight()] = keyMR.String()
+	}
+	chain := prev.GetChainID()
+
+	tests := []struct {
+		height  uint32
+		reverse bool
+		want    string
+	}{
+		{0, false, keyMRs[0]},
+		{3, false, keyMRs[4]},
+		{4, false, keyMRs[4]},
+		{9, false, ""},
+		{3, true, keyMRs[2]},
+		{4, true, keyMRs[4]},
+		{100, true, keyMRs[8]},
+	}
+	for _, test := range tests {
+		keyMR, err := dbo.FetchEBlockKeyMRFromHeight(chain, test.height, test.reverse)
+		if err != nil {
+			t.Error(err)
+		}
+		got := ""
+		if keyMR != nil {
+			got = keyMR.String()
+		}
+		if got != test.want {
+			t.Errorf("Wrong block at height %v, reverse %v - %v vs %v", test.height, test.reverse, got, test.want)
+		}
+	}
+
+	keyMR, err := dbo.FetchEBlockKeyMRFromHeight(primitives.NewZeroHash(), 0, false)
+	if err != nil {
+		t.Error(err)
+	}
+	if keyMR != nil {
+		t.Errorf("Fetched a block of an unknown chain - %v", keyMR)
+	}
+}
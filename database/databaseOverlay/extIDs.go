@@ -0,0 +1,97 @@
+package databaseOverlay
+
+import (
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// The ExtID index is optional. Each (chain, hash of an ExtID) pair has its own bucket, keyed by
+// the hashes of the entries of the chain that carry that ExtID.
+
+var ExtIDIndexHeightKey = []byte("ExtIDIndexHeight")
+
+func extIDBucket(chainID interfaces.IHash, extID []byte) []byte {
+	bucket := append(append([]byte{}, ENTRY_EXTIDS...), chainID.Bytes()...)
+	return append(bucket, primitives.Sha(extID).Bytes()...)
+}
+
+// ExtIDRecords returns the index records of every ExtID of the entry
+func ExtIDRecords(entry interfaces.IEBEntry) []interfaces.Record {
+	batch := []interfaces.Record{}
+	hash := entry.GetHash()
+	for _, extID := range entry.ExternalIDs() {
+		batch = append(batch, interfaces.Record{Bucket: extIDBucket(entry.GetChainID(), extID), Key: hash.Bytes(), Data: hash})
+	}
+	return batch
+}
+
+// SaveEntryExtIDs adds the ExtIDs of the entry to the index
+func (db *Overlay) SaveEntryExtIDs(entry interfaces.IEBEntry) error {
+	if entry == nil {
+		return nil
+	}
+	batch := ExtIDRecords(entry)
+	if len(batch) == 0 {
+		return nil
+	}
+	return db.DB.PutInBatch(batch)
+}
+
+// SaveExtIDIndexHeight records that the entries of every height below the given one are in
+// the ExtID index
+func (db *Overlay) SaveExtIDIndexHeight(height uint32) error {
+	buf := primitives.NewBuffer(nil)
+	buf.PushUInt32(height)
+	bs := new(primitives.ByteSlice)
+	bs.Bytes = buf.DeepCopyBytes()
+	return db.SaveKeyValueStore(bs, ExtIDIndexHeightKey)
+}
+
+// FetchExtIDIndexHeight returns the next height to be added to the ExtID index
+func (db *Overlay) FetchExtIDIndexHeight() (uint32, error) {
+	bs := new(primitives.ByteSlice)
+	loaded, err := db.FetchKeyValueStore(ExtIDIndexHeightKey, bs)
+	if err != nil {
+		return 0, err
+	}
+	if loaded == nil {
+		return 0, nil
+	}
+	buf := primitives.NewBuffer(bs.Bytes)
+	return buf.PopUInt32()
+}
+
+// FetchEntryHashesByExtID returns the hashes of the entries of the chain that have the ExtID,
+// ordered by hash, starting at the given hash if there is one. Only the keys of the page are
+// read, at most limit of them, a limit of 0 reads all of them. The hash of the entry after the
+// page is returned as the start of the next one, nil if there are no more entries
+func (db *Overlay) FetchEntryHashesByExtID(chainID interfaces.IHash, extID []byte, start interfaces.IHash, limit int) ([]interfaces.IHash, interfaces.IHash, error) {
+	opts := interfaces.IterateOptions{}
+	if start != nil {
+		opts.Start = start.Bytes()
+	}
+	if limit > 0 {
+		opts.Limit = limit + 1
+	}
+	it, err := db.Iterate(extIDBucket(chainID, extID), opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer it.Release()
+
+	answer := []interfaces.IHash{}
+	for it.Next() {
+		if limit > 0 && len(answer) == limit {
+			return answer, primitives.NewHash(it.Key()), nil
+		}
+		hash, err := it.Value(new(primitives.Hash))
+		if err != nil {
+			return nil, nil, err
+		}
+		answer = append(answer, hash.(interfaces.IHash))
+	}
+	if err := it.Error(); err != nil {
+		return nil, nil, err
+	}
+	return answer, nil, nil
+}
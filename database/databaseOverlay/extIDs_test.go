@@ -0,0 +1,116 @@
+package databaseOverlay_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/entryBlock"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	. "github.com/FactomProject/factomd/testHelper"
+)
+
+func TestEntryExtIDs(t *testing.T) {
+	blocks := CreateFullTestBlockSet()
+	dbo := CreateEmptyTestDatabaseOverlay()
+
+	next, err := dbo.FetchExtIDIndexHeight()
+	if err != nil {
+		t.Error(err)
+	}
+	if next != 0 {
+		t.Errorf("Expected an empty index to start at 0, got %v", next)
+	}
+
+	for _, block := range blocks {
+		for _, entry := range block.Entries {
+			if err := dbo.SaveEntryExtIDs(entry); err != nil {
+				t.Error(err)
+			}
+		}
+	}
+	if err := dbo.SaveExtIDIndexHeight(uint32(len(blocks))); err != nil {
+		t.Error(err)
+	}
+
+	next, err = dbo.FetchExtIDIndexHeight()
+	if err != nil {
+		t.Error(err)
+	}
+	if int(next) != len(blocks) {
+		t.Errorf("Expected the index to continue at %v, got %v", len(blocks), next)
+	}
+
+	for _, block := range blocks {
+		for _, entry := range block.Entries {
+			for _, extID := range entry.ExternalIDs() {
+				hashes, _, err := dbo.FetchEntryHashesByExtID(entry.GetChainID(), extID, nil, 0)
+				if err != nil {
+					t.Error(err)
+				}
+				found := false
+				for _, h := range hashes {
+					found = found || h.IsSameAs(entry.GetHash())
+				}
+				if !found {
+					t.Errorf("Entry %v not indexed for ExtID %x", entry.GetHash(), extID)
+				}
+			}
+		}
+	}
+
+	// entries sharing an ExtID are all returned, ordered by hash
+	chainID := primitives.NewZeroHash()
+	for i := 0; i < 5; i++ {
+		entry := entryBlock.NewEntry()
+		entry.ChainID = chainID
+		entry.ExtIDs = []primitives.ByteSlice{{Bytes: []byte("shared")}, {Bytes: []byte{byte(i)}}}
+		if err := dbo.SaveEntryExtIDs(entry); err != nil {
+			t.Error(err)
+		}
+	}
+	hashes, nextHash, err := dbo.FetchEntryHashesByExtID(chainID, []byte("shared"), nil, 0)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(hashes) != 5 || nextHash != nil {
+		t.Errorf("Expected 5 entries for the shared ExtID, got %v and next %v", len(hashes), nextHash)
+	}
+	for i := 1; i < len(hashes); i++ {
+		if bytes.Compare(hashes[i-1].Bytes(), hashes[i].Bytes()) >= 0 {
+			t.Errorf("Entries are not ordered by hash")
+		}
+	}
+
+	// pages of 2 start at the hash returned with the previous page
+	var paged []interfaces.IHash
+	var start interfaces.IHash
+	for pages := 0; pages < 3; pages++ {
+		page, nextHash, err := dbo.FetchEntryHashesByExtID(chainID, []byte("shared"), start, 2)
+		if err != nil {
+			t.Error(err)
+		}
+		paged = append(paged, page...)
+		if nextHash == nil {
+			break
+		}
+		start = nextHash
+	}
+	if len(paged) != len(hashes) {
+		t.Errorf("Expected %v entries in pages, got %v", len(hashes), len(paged))
+	}
+	for i := range paged {
+		if i < len(hashes) && !paged[i].IsSameAs(hashes[i]) {
+			t.Errorf("Entry %v of the pages is %v, expected %v", i, paged[i], hashes[i])
+		}
+	}
+
+	// the index is per chain
+	hashes, _, err = dbo.FetchEntryHashesByExtID(primitives.Sha([]byte("another chain")), []byte("shared"), nil, 0)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(hashes) != 0 {
+		t.Errorf("Expected no entries for another chain, got %v", len(hashes))
+	}
+}
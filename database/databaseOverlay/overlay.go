@@ -71,6 +71,13 @@ var (
 	PAID_FOR = []byte("PaidFor")
 
 	KEY_VALUE_STORE = []byte("KeyValueStore")
+
+	//Optional index of the transactions touching an address
+	FACTOID_ADDRESS_TRANSACTIONS = []byte("FactoidAddressTransactions")
+	EC_ADDRESS_TRANSACTIONS      = []byte("ECAddressTransactions")
+
+	//Optional index of the entries of a chain by their ExtIDs
+	ENTRY_EXTIDS = []byte("EntryExtIDs")
 )
 
 var ConstantNamesMap map[string]string
@@ -110,6 +117,9 @@ func init() {
 
 	ConstantNamesMap[string(PAID_FOR)] = "PaidFor"
 	ConstantNamesMap[string(KEY_VALUE_STORE)] = "KeyValueStore"
+	ConstantNamesMap[string(FACTOID_ADDRESS_TRANSACTIONS)] = "FactoidAddressTransactions"
+	ConstantNamesMap[string(EC_ADDRESS_TRANSACTIONS)] = "ECAddressTransactions"
+	ConstantNamesMap[string(ENTRY_EXTIDS)] = "EntryExtIDs"
 
 	RegisterPrometheus()
 }
@@ -177,6 +187,23 @@ func (db *Overlay) GetAll(bucket []byte, sample interfaces.BinaryMarshallableAnd
 	return db.DB.GetAll(bucket, sample)
 }
 
+func (db *Overlay) Iterate(bucket []byte, options interfaces.IterateOptions) (interfaces.IIterator, error) {
+	return db.DB.Iterate(bucket, options)
+}
+
+// Snapshot takes a view of the database. The view is taken between multi batches, so it is
+// always at a directory block boundary.
+func (db *Overlay) Snapshot() (interfaces.IDatabaseSnapshot, error) {
+	db.BatchSemaphore.Lock()
+	defer db.BatchSemaphore.Unlock()
+
+	snapshotter, ok := db.DB.(interfaces.ISnapshotDatabase)
+	if !ok {
+		return nil, fmt.Errorf("The database does not support snapshots")
+	}
+	return snapshotter.Snapshot()
+}
+
 func (db *Overlay) Get(bucket, key []byte, destination interfaces.BinaryMarshallable) (interfaces.BinaryMarshallable, error) {
 	GetBucket(bucket)
 	return db.DB.Get(bucket, key, destination)
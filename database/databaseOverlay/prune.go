@@ -0,0 +1,75 @@
+package databaseOverlay
+
+import (
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// Pruning drops the content of old entries from their chain buckets. The ENTRY index and the
+// INCLUDED_IN records are kept, so the hashes of pruned entries stay known and entry sync
+// does not fetch them again.
+
+var EntryPruneHeightKey = []byte("EntryPruneHeight")
+
+// PruneEntry drops the content of the entry, keeping its hash in the index
+func (db *Overlay) PruneEntry(hash interfaces.IHash) error {
+	chainID, err := db.FetchPrimaryIndexBySecondaryIndex(ENTRY, hash)
+	if err != nil {
+		return err
+	}
+	if chainID == nil {
+		return nil
+	}
+	return db.DB.Delete(chainID.Bytes(), hash.Bytes())
+}
+
+// IsEntryPruned returns true if the entry is in the index but its content was pruned. Only the
+// entries of blocks below the prune height are pruned, the content of a newer entry that is
+// missing was lost instead
+func (db *Overlay) IsEntryPruned(hash interfaces.IHash) (bool, error) {
+	height, err := db.FetchEntryPruneHeight()
+	if err != nil || height == 0 {
+		return false, err
+	}
+	chainID, err := db.FetchPrimaryIndexBySecondaryIndex(ENTRY, hash)
+	if err != nil || chainID == nil {
+		return false, err
+	}
+	exists, err := db.DB.DoesKeyExist(chainID.Bytes(), hash.Bytes())
+	if err != nil || exists {
+		return false, err
+	}
+
+	keyMR, err := db.FetchIncludedIn(hash)
+	if err != nil || keyMR == nil {
+		return false, err
+	}
+	eblock, err := db.FetchEBlock(keyMR)
+	if err != nil || eblock == nil {
+		return false, err
+	}
+	return eblock.GetHeader().GetDBHeight() < height, nil
+}
+
+// SaveEntryPruneHeight records that the entries of every height below the given one were pruned
+func (db *Overlay) SaveEntryPruneHeight(height uint32) error {
+	buf := primitives.NewBuffer(nil)
+	buf.PushUInt32(height)
+	bs := new(primitives.ByteSlice)
+	bs.Bytes = buf.DeepCopyBytes()
+	return db.SaveKeyValueStore(bs, EntryPruneHeightKey)
+}
+
+// FetchEntryPruneHeight returns the next height to be pruned
+func (db *Overlay) FetchEntryPruneHeight() (uint32, error) {
+	bs := new(primitives.ByteSlice)
+	loaded, err := db.FetchKeyValueStore(EntryPruneHeightKey, bs)
+	if err != nil {
+		return 0, err
+	}
+	if loaded == nil {
+		return 0, nil
+	}
+	buf := primitives.NewBuffer(bs.Bytes)
+	return buf.PopUInt32()
+}
@@ -0,0 +1,101 @@
+package databaseOverlay_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+	. "github.com/FactomProject/factomd/testHelper"
+)
+
+func TestPruneEntry(t *testing.T) {
+	blocks := CreateFullTestBlockSet()
+	dbo := CreateEmptyTestDatabaseOverlay()
+	if err := SaveBlockSets(dbo, blocks); err != nil {
+		t.Fatal(err)
+	}
+
+	entry := blocks[len(blocks)-1].Entries[0]
+	other := blocks[len(blocks)-1].Entries[1]
+
+	if err := dbo.PruneEntry(entry.GetHash()); err != nil {
+		t.Error(err)
+	}
+	// nothing is reported as pruned until the pruner records its progress
+	pruned, err := dbo.IsEntryPruned(entry.GetHash())
+	if err != nil {
+		t.Error(err)
+	}
+	if pruned {
+		t.Errorf("Expected the entry not to be reported as pruned without a prune height")
+	}
+
+	// an entry missing from a block the pruner did not reach was lost, not pruned
+	if err := dbo.SaveEntryPruneHeight(uint32(len(blocks) - 1)); err != nil {
+		t.Error(err)
+	}
+	pruned, err = dbo.IsEntryPruned(entry.GetHash())
+	if err != nil {
+		t.Error(err)
+	}
+	if pruned {
+		t.Errorf("Expected the entry above the prune height not to be reported as pruned")
+	}
+
+	if err := dbo.SaveEntryPruneHeight(uint32(len(blocks))); err != nil {
+		t.Error(err)
+	}
+	next, err := dbo.FetchEntryPruneHeight()
+	if err != nil {
+		t.Error(err)
+	}
+	if int(next) != len(blocks) {
+		t.Errorf("Expected the pruner to continue at %v, got %v", len(blocks), next)
+	}
+
+	fetched, err := dbo.FetchEntry(entry.GetHash())
+	if err != nil {
+		t.Error(err)
+	}
+	if fetched != nil {
+		t.Errorf("Expected the content of the pruned entry to be gone")
+	}
+	pruned, err = dbo.IsEntryPruned(entry.GetHash())
+	if err != nil {
+		t.Error(err)
+	}
+	if !pruned {
+		t.Errorf("Expected the entry to be reported as pruned")
+	}
+	// the hash of the pruned entry stays in the index
+	chainID, err := dbo.FetchPrimaryIndexBySecondaryIndex(databaseOverlay.ENTRY, entry.GetHash())
+	if err != nil {
+		t.Error(err)
+	}
+	if chainID == nil || !chainID.IsSameAs(entry.GetChainID()) {
+		t.Errorf("Expected the index to point to chain %v, got %v", entry.GetChainID(), chainID)
+	}
+
+	fetched, err = dbo.FetchEntry(other.GetHash())
+	if err != nil {
+		t.Error(err)
+	}
+	if fetched == nil {
+		t.Errorf("Expected the other entry to be kept")
+	}
+	pruned, err = dbo.IsEntryPruned(other.GetHash())
+	if err != nil {
+		t.Error(err)
+	}
+	if pruned {
+		t.Errorf("Expected the other entry not to be reported as pruned")
+	}
+
+	// unknown entries are not pruned
+	pruned, err = dbo.IsEntryPruned(blocks[0].DBlock.GetKeyMR())
+	if err != nil {
+		t.Error(err)
+	}
+	if pruned {
+		t.Errorf("Expected an unknown hash not to be reported as pruned")
+	}
+}
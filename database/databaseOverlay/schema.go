@@ -0,0 +1,184 @@
+package databaseOverlay
+
+import (
+	"fmt"
+
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// The schema version of the database is the version of the last migration applied to it. A new
+// database starts at the current version, a database created before the version was recorded
+// is at version 0 and goes through every migration.
+
+var SchemaVersionKey = []byte("SchemaVersion")
+var MigrationCheckpointKey = []byte("MigrationCheckpoint")
+
+// MigrationCheckpointInterval is the number of steps between saves of the progress of a migration
+var MigrationCheckpointInterval uint32 = 100
+
+// A Migration upgrades the database to its version. The work is split into numbered steps,
+// usually one per directory block, and the progress is saved as the steps run so an
+// interrupted migration resumes where it stopped. Steps have to be safe to run twice.
+type Migration struct {
+	Version     uint32
+	Description string
+	// Steps returns the number of steps the migration takes on the database, nil for none
+	Steps func(db *Overlay) (uint32, error)
+	// Step runs one step of the migration
+	Step func(db *Overlay, step uint32) error
+}
+
+// MigrationProgress is reported after every step of a migration
+type MigrationProgress struct {
+	Version     uint32
+	Description string
+	Step        uint32
+	Steps       uint32
+}
+
+// Migrations is the ordered registry of the migrations of the database
+var Migrations = []*Migration{
+	{Version: 1, Description: "Record the schema version"},
+}
+
+// RegisterMigration adds a migration to the registry. Versions have to follow each other.
+func RegisterMigration(m *Migration) {
+	if m.Version != CurrentSchemaVersion()+1 {
+		panic(fmt.Sprintf("Migration to schema version %v registered after version %v", m.Version, CurrentSchemaVersion()))
+	}
+	Migrations = append(Migrations, m)
+}
+
+// CurrentSchemaVersion returns the schema version of the databases written by this code
+func CurrentSchemaVersion() uint32 {
+	return schemaVersion(Migrations)
+}
+
+func schemaVersion(migrations []*Migration) uint32 {
+	if len(migrations) == 0 {
+		return 0
+	}
+	return migrations[len(migrations)-1].Version
+}
+
+func (db *Overlay) SaveSchemaVersion(version uint32) error {
+	buf := primitives.NewBuffer(nil)
+	buf.PushUInt32(version)
+	bs := new(primitives.ByteSlice)
+	bs.Bytes = buf.DeepCopyBytes()
+	return db.SaveKeyValueStore(bs, SchemaVersionKey)
+}
+
+// FetchSchemaVersion returns the schema version of the database, and false if none was recorded
+func (db *Overlay) FetchSchemaVersion() (uint32, bool, error) {
+	bs := new(primitives.ByteSlice)
+	loaded, err := db.FetchKeyValueStore(SchemaVersionKey, bs)
+	if err != nil {
+		return 0, false, err
+	}
+	if loaded == nil {
+		return 0, false, nil
+	}
+	buf := primitives.NewBuffer(bs.Bytes)
+	version, err := buf.PopUInt32()
+	return version, true, err
+}
+
+func (db *Overlay) saveMigrationCheckpoint(version uint32, step uint32) error {
+	buf := primitives.NewBuffer(nil)
+	buf.PushUInt32(version)
+	buf.PushUInt32(step)
+	bs := new(primitives.ByteSlice)
+	bs.Bytes = buf.DeepCopyBytes()
+	return db.SaveKeyValueStore(bs, MigrationCheckpointKey)
+}
+
+// fetchMigrationCheckpoint returns the step the migration to the version resumes from
+func (db *Overlay) fetchMigrationCheckpoint(version uint32) (uint32, error) {
+	bs := new(primitives.ByteSlice)
+	loaded, err := db.FetchKeyValueStore(MigrationCheckpointKey, bs)
+	if err != nil || loaded == nil {
+		return 0, err
+	}
+	buf := primitives.NewBuffer(bs.Bytes)
+	v, err := buf.PopUInt32()
+	if err != nil {
+		return 0, err
+	}
+	step, err := buf.PopUInt32()
+	if err != nil {
+		return 0, err
+	}
+	if v != version {
+		return 0, nil
+	}
+	return step, nil
+}
+
+// Migrate brings the database up to the current schema version with the registered migrations
+func (db *Overlay) Migrate(progress func(MigrationProgress)) error {
+	return db.RunMigrations(Migrations, progress)
+}
+
+// RunMigrations applies the migrations above the schema version of the database, in order.
+// Databases without any blocks are new and only get the version recorded. Databases of a
+// newer schema version than the migrations know are refused.
+func (db *Overlay) RunMigrations(migrations []*Migration, progress func(MigrationProgress)) error {
+	latest := schemaVersion(migrations)
+	version, recorded, err := db.FetchSchemaVersion()
+	if err != nil {
+		return err
+	}
+	if version > latest {
+		return fmt.Errorf("The database is at schema version %v, this version of factomd only supports up to %v", version, latest)
+	}
+	if !recorded {
+		head, err := db.FetchDBlockHead()
+		if err != nil {
+			return err
+		}
+		if head == nil {
+			return db.SaveSchemaVersion(latest)
+		}
+	}
+
+	for _, m := range migrations {
+		if m.Version <= version {
+			continue
+		}
+
+		var steps uint32
+		if m.Steps != nil {
+			steps, err = m.Steps(db)
+			if err != nil {
+				return fmt.Errorf("Migration to schema version %v: %v", m.Version, err)
+			}
+		}
+		step, err := db.fetchMigrationCheckpoint(m.Version)
+		if err != nil {
+			return err
+		}
+		for ; step < steps; step++ {
+			if err := m.Step(db, step); err != nil {
+				return fmt.Errorf("Migration to schema version %v failed at step %v: %v", m.Version, step, err)
+			}
+			if (step+1)%MigrationCheckpointInterval == 0 {
+				if err := db.saveMigrationCheckpoint(m.Version, step+1); err != nil {
+					return err
+				}
+			}
+			if progress != nil {
+				progress(MigrationProgress{Version: m.Version, Description: m.Description, Step: step + 1, Steps: steps})
+			}
+		}
+
+		if err := db.SaveSchemaVersion(m.Version); err != nil {
+			return err
+		}
+		version = m.Version
+		if progress != nil && steps == 0 {
+			progress(MigrationProgress{Version: m.Version, Description: m.Description})
+		}
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package databaseOverlay_test
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/FactomProject/factomd/database/databaseOverlay"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestMigrateNewDatabase(t *testing.T) {
+	dbo := testHelper.CreateEmptyTestDatabaseOverlay()
+
+	_, recorded, err := dbo.FetchSchemaVersion()
+	if err != nil {
+		t.Error(err)
+	}
+	if recorded {
+		t.Errorf("Expected an empty database to have no schema version")
+	}
+
+	if err := dbo.Migrate(nil); err != nil {
+		t.Error(err)
+	}
+	version, recorded, err := dbo.FetchSchemaVersion()
+	if err != nil {
+		t.Error(err)
+	}
+	if !recorded || version != CurrentSchemaVersion() {
+		t.Errorf("Expected a new database to be at schema version %v, got %v", CurrentSchemaVersion(), version)
+	}
+}
+
+func TestRunMigrations(t *testing.T) {
+	dbo := testHelper.CreateAndPopulateTestDatabaseOverlay()
+
+	ran := map[uint32]int{}
+	fail := true
+	migrations := []*Migration{
+		{Version: 1, Description: "first"},
+		{
+			Version:     2,
+			Description: "second",
+			Steps:       func(db *Overlay) (uint32, error) { return 250, nil },
+			Step: func(db *Overlay, step uint32) error {
+				if step == 150 && fail {
+					fail = false
+					return fmt.Errorf("interrupted")
+				}
+				ran[step]++
+				return nil
+			},
+		},
+	}
+
+	var reported []MigrationProgress
+	progress := func(p MigrationProgress) {
+		reported = append(reported, p)
+	}
+
+	// the migrations of an existing database without a version all run
+	if err := dbo.RunMigrations(migrations, progress); err == nil {
+		t.Errorf("Expected the interrupted migration to fail")
+	}
+	version, _, err := dbo.FetchSchemaVersion()
+	if err != nil {
+		t.Error(err)
+	}
+	if version != 1 {
+		t.Errorf("Expected the database to stay at schema version 1, got %v", version)
+	}
+
+	// the second run resumes from the last checkpoint
+	if err := dbo.RunMigrations(migrations, progress); err != nil {
+		t.Error(err)
+	}
+	version, _, err = dbo.FetchSchemaVersion()
+	if err != nil {
+		t.Error(err)
+	}
+	if version != 2 {
+		t.Errorf("Expected the database to be at schema version 2, got %v", version)
+	}
+	for step := uint32(0); step < 250; step++ {
+		expected := 1
+		if step >= 100 && step < 150 {
+			expected = 2
+		}
+		if ran[step] != expected {
+			t.Errorf("Expected step %v to run %v times, ran %v", step, expected, ran[step])
+		}
+	}
+
+	last := reported[len(reported)-1]
+	if last.Version != 2 || last.Step != 250 || last.Steps != 250 {
+		t.Errorf("Expected the last progress report to be the end of the second migration, got %+v", last)
+	}
+
+	// nothing runs once the database is up to date
+	if err := dbo.RunMigrations(migrations, nil); err != nil {
+		t.Error(err)
+	}
+	if ran[0] != 1 {
+		t.Errorf("Expected the migrations not to run again")
+	}
+
+	// databases of a newer schema are refused
+	if err := dbo.SaveSchemaVersion(3); err != nil {
+		t.Error(err)
+	}
+	if err := dbo.RunMigrations(migrations, nil); err == nil {
+		t.Errorf("Expected a database of a newer schema to be refused")
+	}
+}
+
+func TestRegisterMigration(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected a migration out of order to panic")
+		}
+	}()
+	RegisterMigration(&Migration{Version: CurrentSchemaVersion() + 2})
+}
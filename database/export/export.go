@@ -0,0 +1,421 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+// Package export writes the blocks, entries, factoid transactions and entry credit commits of a
+// database to CSV files for analytics, one file per table and range of heights.
+package export
+
+import (
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/factoid"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+)
+
+// DefaultPartitionSize is the number of heights in each file
+const DefaultPartitionSize = 10000
+
+// CheckpointFile holds the progress of the export, in the output directory
+const CheckpointFile = "checkpoint.json"
+
+// The tables exported, each in its own directory
+const (
+	TableDBlocks      = "dblocks"
+	TableEntries      = "entries"
+	TableTransactions = "transactions"
+	TableCommits      = "commits"
+)
+
+var Tables = []string{TableDBlocks, TableEntries, TableTransactions, TableCommits}
+
+// Columns are the header rows of the tables
+var Columns = map[string][]string{
+	TableDBlocks:      {"height", "keymr", "fullhash", "prevkeymr", "timestamp", "ablock", "ecblock", "fblock", "eblocks"},
+	TableEntries:      {"height", "minute", "chainid", "entryhash", "eblock", "eblocksequence", "extids", "content", "pruned"},
+	TableTransactions: {"height", "txid", "timestamp", "index", "kind", "address", "amount"},
+	TableCommits:      {"height", "minute", "type", "entryhash", "ecaddress", "credits", "timestamp"},
+}
+
+// Checkpoint is the progress of an export. Heights below Next are written.
+type Checkpoint struct {
+	Next          uint32 `json:"next"`
+	PartitionSize uint32 `json:"partitionsize"`
+}
+
+type Exporter struct {
+	DB  *databaseOverlay.Overlay
+	Dir string
+	// PartitionSize is the number of heights in each file, DefaultPartitionSize if 0
+	PartitionSize uint32
+	// Progress is called after every file written
+	Progress func(from uint32, to uint32)
+}
+
+// Run exports the heights after the checkpoint, up to the head of the database. Each range of
+// heights is written to temporary files that are renamed once complete, then the checkpoint
+// is saved. The files of a range the head was in are rewritten once the range has more
+// heights.
+func (e *Exporter) Run() (*Checkpoint, error) {
+	size := e.PartitionSize
+	if size == 0 {
+		size = DefaultPartitionSize
+	}
+	cp, err := e.loadCheckpoint()
+	if err != nil {
+		return nil, err
+	}
+	if cp == nil {
+		cp = &Checkpoint{PartitionSize: size}
+	}
+	if cp.PartitionSize != size {
+		return nil, fmt.Errorf("The export in %v uses partitions of %v heights, not %v", e.Dir, cp.PartitionSize, size)
+	}
+
+	head, err := e.DB.FetchDBlockHead()
+	if err != nil {
+		return nil, err
+	}
+	if head == nil {
+		return cp, nil
+	}
+	last := head.GetDatabaseHeight()
+
+	for _, table := range Tables {
+		if err := os.MkdirAll(filepath.Join(e.Dir, table), 0755); err != nil {
+			return nil, err
+		}
+	}
+
+	from := cp.Next - cp.Next%size
+	if from < cp.Next {
+		// the last range was written up to the head at the time, replace it
+		if last < cp.Next {
+			return cp, nil
+		}
+		for _, table := range Tables {
+			if err := os.Remove(e.path(table, from, cp.Next-1)); err != nil && !os.IsNotExist(err) {
+				return nil, err
+			}
+		}
+	}
+
+	for ; from <= last; from += size {
+		to := from + size - 1
+		if to > last {
+			to = last
+		}
+		if err := e.writePartition(from, to); err != nil {
+			return nil, err
+		}
+		cp.Next = to + 1
+		if err := e.saveCheckpoint(cp); err != nil {
+			return nil, err
+		}
+		if e.Progress != nil {
+			e.Progress(from, to)
+		}
+	}
+	return cp, nil
+}
+
+func (e *Exporter) path(table string, from uint32, to uint32) string {
+	return filepath.Join(e.Dir, table, fmt.Sprintf("%09d-%09d.csv", from, to))
+}
+
+func (e *Exporter) loadCheckpoint() (*Checkpoint, error) {
+	data, err := ioutil.ReadFile(filepath.Join(e.Dir, CheckpointFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	cp := new(Checkpoint)
+	if err := json.Unmarshal(data, cp); err != nil {
+		return nil, fmt.Errorf("Reading %v: %v", CheckpointFile, err)
+	}
+	return cp, nil
+}
+
+func (e *Exporter) saveCheckpoint(cp *Checkpoint) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	filename := filepath.Join(e.Dir, CheckpointFile)
+	if err := ioutil.WriteFile(filename+".tmp", data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(filename+".tmp", filename)
+}
+
+// partition holds the files of the tables for a range of heights while they are written
+type partition struct {
+	files   map[string]*os.File
+	writers map[string]*csv.Writer
+}
+
+func (p *partition) write(table string, row ...string) error {
+	return p.writers[table].Write(row)
+}
+
+func (p *partition) close() error {
+	var err error
+	for table, f := range p.files {
+		p.writers[table].Flush()
+		if werr := p.writers[table].Error(); err == nil {
+			err = werr
+		}
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+func (e *Exporter) writePartition(from uint32, to uint32) (err error) {
+	p := &partition{files: map[string]*os.File{}, writers: map[string]*csv.Writer{}}
+	for _, table := range Tables {
+		f, err := os.Create(e.path(table, from, to) + ".tmp")
+		if err != nil {
+			p.close()
+			return err
+		}
+		p.files[table] = f
+		p.writers[table] = csv.NewWriter(f)
+		if err := p.write(table, Columns[table]...); err != nil {
+			p.close()
+			return err
+		}
+	}
+
+	for h := from; h <= to; h++ {
+		if err = e.writeHeight(p, h); err != nil {
+			break
+		}
+	}
+	if cerr := p.close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		for _, table := range Tables {
+			os.Remove(e.path(table, from, to) + ".tmp")
+		}
+		return err
+	}
+
+	for _, table := range Tables {
+		if err := os.Rename(e.path(table, from, to)+".tmp", e.path(table, from, to)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *Exporter) writeHeight(p *partition, h uint32) error {
+	dblock, err := e.DB.FetchDBlockByHeight(h)
+	if err != nil {
+		return err
+	}
+	if dblock == nil {
+		return fmt.Errorf("Missing directory block %v", h)
+	}
+	height := strconv.FormatUint(uint64(h), 10)
+
+	var ablock, ecblock, fblock string
+	for _, dbEntry := range dblock.GetDBEntries() {
+		switch dbEntry.GetChainID().String() {
+		case hex.EncodeToString(constants.ADMIN_CHAINID):
+			ablock = dbEntry.GetKeyMR().String()
+		case hex.EncodeToString(constants.EC_CHAINID):
+			ecblock = dbEntry.GetKeyMR().String()
+		case hex.EncodeToString(constants.FACTOID_CHAINID):
+			fblock = dbEntry.GetKeyMR().String()
+		}
+	}
+	eblocks := dblock.GetEBlockDBEntries()
+	err = p.write(TableDBlocks, height,
+		dblock.GetKeyMR().String(),
+		dblock.GetFullHash().String(),
+		dblock.GetHeader().GetPrevKeyMR().String(),
+		strconv.FormatInt(dblock.GetTimestamp().GetTimeSeconds(), 10),
+		ablock,
+		ecblock,
+		fblock,
+		strconv.Itoa(len(eblocks)))
+	if err != nil {
+		return err
+	}
+
+	for _, dbEntry := range eblocks {
+		if err := e.writeEBlock(p, height, dbEntry.GetKeyMR()); err != nil {
+			return err
+		}
+	}
+
+	fb, err := e.DB.FetchFBlockByHeight(h)
+	if err != nil {
+		return err
+	}
+	if fb != nil {
+		for i, tx := range fb.GetTransactions() {
+			if err := writeTransaction(p, height, i, tx); err != nil {
+				return err
+			}
+		}
+	}
+
+	ecb, err := e.DB.FetchECBlockByHeight(h)
+	if err != nil {
+		return err
+	}
+	if ecb != nil {
+		if err := writeCommits(p, height, ecb); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *Exporter) writeEBlock(p *partition, height string, keyMR interfaces.IHash) error {
+	eblock, err := e.DB.FetchEBlock(keyMR)
+	if err != nil {
+		return err
+	}
+	if eblock == nil {
+		return fmt.Errorf("Missing entry block %v", keyMR)
+	}
+	sequence := strconv.FormatUint(uint64(eblock.GetHeader().GetEBSequence()), 10)
+
+	// the entries are listed before the marker of the minute they were added in
+	minute := 0
+	var pending []interfaces.IHash
+	for _, hash := range eblock.GetEntryHashes() {
+		if !hash.IsMinuteMarker() {
+			pending = append(pending, hash)
+			continue
+		}
+		minute = int(hash.ToMinute())
+		for _, entryHash := range pending {
+			if err := e.writeEntry(p, height, minute, eblock.GetChainID(), entryHash, keyMR, sequence); err != nil {
+				return err
+			}
+		}
+		pending = nil
+	}
+	// entries after the last minute marker belong to the minute after it
+	for _, entryHash := range pending {
+		if err := e.writeEntry(p, height, minute+1, eblock.GetChainID(), entryHash, keyMR, sequence); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *Exporter) writeEntry(p *partition, height string, minute int, chainID interfaces.IHash, hash interfaces.IHash, keyMR interfaces.IHash, sequence string) error {
+	entry, err := e.DB.FetchEntry(hash)
+	if err != nil {
+		return err
+	}
+	var extIDs []string
+	content := ""
+	pruned := false
+	if entry == nil {
+		pruned, err = e.DB.IsEntryPruned(hash)
+		if err != nil {
+			return err
+		}
+		if !pruned {
+			return fmt.Errorf("Missing entry %v", hash)
+		}
+	} else {
+		for _, extID := range entry.ExternalIDs() {
+			extIDs = append(extIDs, hex.EncodeToString(extID))
+		}
+		content = hex.EncodeToString(entry.GetContent())
+	}
+	return p.write(TableEntries, height, strconv.Itoa(minute), chainID.String(), hash.String(), keyMR.String(), sequence,
+		strings.Join(extIDs, " "), content, strconv.FormatBool(pruned))
+}
+
+// writeTransaction writes a row for every input and output of the transaction
+func writeTransaction(p *partition, height string, index int, tx interfaces.ITransaction) error {
+	txid := tx.GetSigHash().String()
+	timestamp := strconv.FormatInt(tx.GetTimestamp().GetTimeMilli(), 10)
+	row := func(kind string, address string, amount uint64) error {
+		return p.write(TableTransactions, height, txid, timestamp, strconv.Itoa(index), kind, address, strconv.FormatUint(amount, 10))
+	}
+	for _, input := range tx.GetInputs() {
+		if err := row("input", primitives.ConvertFctAddressToUserStr(input.GetAddress()), input.GetAmount()); err != nil {
+			return err
+		}
+	}
+	for _, output := range tx.GetOutputs() {
+		if err := row("output", primitives.ConvertFctAddressToUserStr(output.GetAddress()), output.GetAmount()); err != nil {
+			return err
+		}
+	}
+	for _, ecOut := range tx.GetECOutputs() {
+		if err := row("ecoutput", primitives.ConvertECAddressToUserStr(ecOut.GetAddress()), ecOut.GetAmount()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeCommits writes the chain and entry commits of the block, with the minute marker that
+// follows them, like the entries
+func writeCommits(p *partition, height string, ecblock interfaces.IEntryCreditBlock) error {
+	minute := 0
+	var pending []interfaces.IECBlockEntry
+	flush := func() error {
+		for _, entry := range pending {
+			var kind string
+			var pubKey *primitives.ByteSlice32
+			var credits uint8
+			switch c := entry.(type) {
+			case *entryCreditBlock.CommitChain:
+				kind, pubKey, credits = "chain", c.ECPubKey, c.Credits
+			case *entryCreditBlock.CommitEntry:
+				kind, pubKey, credits = "entry", c.ECPubKey, c.Credits
+			}
+			err := p.write(TableCommits, height, strconv.Itoa(minute), kind,
+				entry.GetEntryHash().String(),
+				primitives.ConvertECAddressToUserStr(factoid.NewAddress(pubKey[:])),
+				strconv.Itoa(int(credits)),
+				strconv.FormatInt(entry.GetTimestamp().GetTimeMilli(), 10))
+			if err != nil {
+				return err
+			}
+		}
+		pending = nil
+		return nil
+	}
+
+	for _, entry := range ecblock.GetBody().GetEntries() {
+		switch entry.ECID() {
+		case constants.ECIDMinuteNumber:
+			minute = int(entry.(*entryCreditBlock.MinuteNumber).Number)
+			if err := flush(); err != nil {
+				return err
+			}
+		case constants.ECIDChainCommit, constants.ECIDEntryCommit:
+			pending = append(pending, entry)
+		}
+	}
+	minute++
+	return flush()
+}
@@ -0,0 +1,109 @@
+package export_test
+
+import (
+	"encoding/csv"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	. "github.com/FactomProject/factomd/database/export"
+	"github.com/FactomProject/factomd/testHelper"
+	"github.com/stretchr/testify/assert"
+)
+
+// files returns the names of the files of the table
+func files(t *testing.T, dir string, table string) []string {
+	infos, err := ioutil.ReadDir(filepath.Join(dir, table))
+	assert.Nil(t, err)
+	names := []string{}
+	for _, info := range infos {
+		names = append(names, info.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+// rows returns the rows of all the files of the table, without their headers
+func rows(t *testing.T, dir string, table string) [][]string {
+	all := [][]string{}
+	for _, name := range files(t, dir, table) {
+		f, err := os.Open(filepath.Join(dir, table, name))
+		if !assert.Nil(t, err) {
+			continue
+		}
+		records, err := csv.NewReader(f).ReadAll()
+		f.Close()
+		assert.Nil(t, err)
+		if assert.NotEmpty(t, records, name) {
+			assert.Equal(t, Columns[table], records[0], name)
+			all = append(all, records[1:]...)
+		}
+	}
+	return all
+}
+
+func TestExporter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "export")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	blocks := testHelper.CreateFullTestBlockSet()
+	dbo := testHelper.CreateEmptyTestDatabaseOverlay()
+	defer dbo.Close()
+	assert.Nil(t, testHelper.SaveBlockSets(dbo, blocks[:6]))
+
+	e := &Exporter{DB: dbo, Dir: dir, PartitionSize: 4}
+	cp, err := e.Run()
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.EqualValues(t, 6, cp.Next)
+	for _, table := range Tables {
+		assert.Equal(t, []string{"000000000-000000003.csv", "000000004-000000005.csv"}, files(t, dir, table), table)
+	}
+
+	// nothing new to export
+	cp, err = e.Run()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 6, cp.Next)
+
+	// a different partitioning does not resume the export
+	_, err = (&Exporter{DB: dbo, Dir: dir, PartitionSize: 5}).Run()
+	assert.NotNil(t, err)
+
+	// the range the head was in is rewritten with the new heights
+	assert.Nil(t, testHelper.SaveBlockSets(dbo, blocks[6:]))
+	cp, err = e.Run()
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.EqualValues(t, len(blocks), cp.Next)
+	for _, table := range Tables {
+		assert.Equal(t, []string{"000000000-000000003.csv", "000000004-000000007.csv", "000000008-000000009.csv"}, files(t, dir, table), table)
+	}
+
+	dblocks := rows(t, dir, TableDBlocks)
+	if assert.Len(t, dblocks, len(blocks)) {
+		for i, row := range dblocks {
+			assert.Equal(t, blocks[i].DBlock.GetKeyMR().String(), row[1])
+		}
+	}
+
+	entries := rows(t, dir, TableEntries)
+	count := 0
+	for _, set := range blocks {
+		count += len(set.Entries)
+	}
+	assert.Len(t, entries, count)
+	for _, row := range entries {
+		assert.NotEmpty(t, row[7], "content of %v", row[3])
+		assert.Equal(t, "false", row[8])
+	}
+
+	assert.NotEmpty(t, rows(t, dir, TableTransactions))
+	assert.NotEmpty(t, rows(t, dir, TableCommits))
+}
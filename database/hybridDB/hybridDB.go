@@ -1,6 +1,7 @@
 package hybridDB
 
 import (
+	"fmt"
 	"sync"
 
 	"github.com/FactomProject/factomd/common/interfaces"
@@ -158,6 +159,59 @@ func (db *HybridDB) GetAll(bucket []byte, sample interfaces.BinaryMarshallableAn
 	return db.persistentStorage.GetAll(bucket, sample)
 }
 
+// Iterate walks over the records of the persistent storage, the temporary storage only caches them
+func (db *HybridDB) Iterate(bucket []byte, options interfaces.IterateOptions) (interfaces.IIterator, error) {
+	db.Sem.RLock()
+	defer db.Sem.RUnlock()
+
+	return db.persistentStorage.Iterate(bucket, options)
+}
+
+// Snapshot takes a view of the persistent storage
+func (db *HybridDB) Snapshot() (interfaces.IDatabaseSnapshot, error) {
+	db.Sem.RLock()
+	defer db.Sem.RUnlock()
+
+	snapshotter, ok := db.persistentStorage.(interfaces.ISnapshotDatabase)
+	if !ok {
+		return nil, fmt.Errorf("The database does not support snapshots")
+	}
+	return snapshotter.Snapshot()
+}
+
+func (db *HybridDB) compacter() (interfaces.ICompactDatabase, error) {
+	compacter, ok := db.persistentStorage.(interfaces.ICompactDatabase)
+	if !ok {
+		return nil, fmt.Errorf("The database does not support compaction")
+	}
+	return compacter, nil
+}
+
+// Compact compacts the persistent storage
+func (db *HybridDB) Compact() error {
+	compacter, err := db.compacter()
+	if err != nil {
+		return err
+	}
+	return compacter.Compact()
+}
+
+func (db *HybridDB) BucketSize(bucket []byte) (int64, error) {
+	compacter, err := db.compacter()
+	if err != nil {
+		return 0, err
+	}
+	return compacter.BucketSize(bucket)
+}
+
+func (db *HybridDB) Size() (int64, error) {
+	compacter, err := db.compacter()
+	if err != nil {
+		return 0, err
+	}
+	return compacter.Size()
+}
+
 func (db *HybridDB) Clear(bucket []byte) error {
 	db.Sem.Lock()
 	defer db.Sem.Unlock()
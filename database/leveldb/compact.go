@@ -0,0 +1,42 @@
+package leveldb
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/goleveldb/leveldb/util"
+)
+
+var _ interfaces.ICompactDatabase = (*LevelDB)(nil)
+
+// Compact compacts every level of the database, dropping the deleted and overwritten records.
+// The database stays usable while it runs.
+func (db *LevelDB) Compact() error {
+	return db.lDB.CompactRange(util.Range{})
+}
+
+// BucketSize returns the approximate space of the tables holding the bucket
+func (db *LevelDB) BucketSize(bucket []byte) (int64, error) {
+	prefix := ExtendBucket(append([]byte{}, bucket...))
+	sizes, err := db.lDB.SizeOf([]util.Range{{Start: prefix, Limit: interfaces.PrefixEnd(prefix)}})
+	if err != nil {
+		return 0, err
+	}
+	return int64(sizes.Sum()), nil
+}
+
+// Size returns the space of the files of the database
+func (db *LevelDB) Size() (int64, error) {
+	var size int64
+	err := filepath.Walk(db.path, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
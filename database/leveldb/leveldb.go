@@ -14,6 +14,7 @@ import (
 
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/goleveldb/leveldb"
+	"github.com/FactomProject/goleveldb/leveldb/iterator"
 	"github.com/FactomProject/goleveldb/leveldb/opt"
 	"github.com/FactomProject/goleveldb/leveldb/util"
 )
@@ -25,6 +26,7 @@ type LevelDB struct {
 	lbatch *leveldb.Batch
 	ro     *opt.ReadOptions
 	wo     *opt.WriteOptions
+	path   string
 }
 
 var _ interfaces.IDatabase = (*LevelDB)(nil)
@@ -258,6 +260,127 @@ func (db *LevelDB) GetAll(bucket []byte, sample interfaces.BinaryMarshallableAnd
 	return answer, keys, nil
 }
 
+// Iterate walks over the records of the bucket selected by the options. The iterator reads
+// from a snapshot of the database, it does not block writes.
+func (db *LevelDB) Iterate(bucket []byte, options interfaces.IterateOptions) (interfaces.IIterator, error) {
+	db.dbLock.RLock()
+	defer db.dbLock.RUnlock()
+
+	ldbKey := ExtendBucket(append([]byte{}, bucket...))
+	rng := util.BytesPrefix(ldbKey)
+	if lower := options.Lower(); lower != nil {
+		rng.Start = append(append([]byte{}, ldbKey...), lower...)
+	}
+	if upper := options.Upper(); upper != nil {
+		rng.Limit = append(append([]byte{}, ldbKey...), upper...)
+	}
+
+	it := new(levelIterator)
+	it.iter = db.lDB.NewIterator(rng, db.ro)
+	it.bucketLen = len(ldbKey)
+	it.reverse = options.Reverse
+	it.limit = options.Limit
+	return it, nil
+}
+
+// IterateStored walks over all the records of the database, whatever their bucket, as LevelDB
+// cannot list its buckets. The keys are the ones the records are stored under, their bucket and
+// key joined by CombineBucketAndKey
+func (db *LevelDB) IterateStored(options interfaces.IterateOptions) (interfaces.IIterator, error) {
+	db.dbLock.RLock()
+	defer db.dbLock.RUnlock()
+
+	it := new(levelIterator)
+	it.iter = db.lDB.NewIterator(&util.Range{Start: options.Lower(), Limit: options.Upper()}, db.ro)
+	it.reverse = options.Reverse
+	it.limit = options.Limit
+	return it, nil
+}
+
+// PutStoredInBatch writes the records under the keys they are stored under, as returned by
+// IterateStored. The buckets of the records are not used
+func (db *LevelDB) PutStoredInBatch(records []interfaces.Record) error {
+	db.dbLock.Lock()
+	defer db.dbLock.Unlock()
+
+	batch := new(leveldb.Batch)
+	for _, v := range records {
+		hex, err := v.Data.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		batch.Put(v.Key, hex)
+		LevelDBPuts.Inc()
+	}
+	return db.lDB.Write(batch, db.wo)
+}
+
+type levelIterator struct {
+	iter      iterator.Iterator
+	bucketLen int
+	reverse   bool
+	limit     int
+	count     int
+	started   bool
+}
+
+var _ interfaces.IIterator = (*levelIterator)(nil)
+
+func (it *levelIterator) Next() bool {
+	if it.limit > 0 && it.count >= it.limit {
+		return false
+	}
+
+	var ok bool
+	switch {
+	case !it.started && it.reverse:
+		ok = it.iter.Last()
+	case !it.started:
+		ok = it.iter.First()
+	case it.reverse:
+		ok = it.iter.Prev()
+	default:
+		ok = it.iter.Next()
+	}
+	it.started = true
+	if ok {
+		it.count++
+	}
+	return ok
+}
+
+func (it *levelIterator) Key() []byte {
+	key := it.iter.Key()
+	if key == nil {
+		return nil
+	}
+	k := make([]byte, len(key)-it.bucketLen)
+	copy(k, key[it.bucketLen:])
+	return k
+}
+
+func (it *levelIterator) Value(destination interfaces.BinaryMarshallable) (interfaces.BinaryMarshallable, error) {
+	v := it.iter.Value()
+	if v == nil {
+		return nil, nil
+	}
+	vCopy := make([]byte, len(v))
+	copy(vCopy, v)
+	_, err := destination.UnmarshalBinaryData(vCopy)
+	if err != nil {
+		return nil, err
+	}
+	return destination, nil
+}
+
+func (it *levelIterator) Error() error {
+	return it.iter.Error()
+}
+
+func (it *levelIterator) Release() {
+	it.iter.Release()
+}
+
 func NewLevelDB(filename string, create bool) (interfaces.IDatabase, error) {
 	db := new(LevelDB)
 	var err error
@@ -286,6 +409,7 @@ func NewLevelDB(filename string, create bool) (interfaces.IDatabase, error) {
 		return nil, err
 	}
 	db.lDB = tlDB
+	db.path = filename
 
 	return db, nil
 }
@@ -310,3 +434,64 @@ func (db *LevelDB) DoesKeyExist(bucket, key []byte) (bool, error) {
 	ldbKey := CombineBucketAndKey(bucket, key)
 	return db.lDB.Has(ldbKey, db.ro)
 }
+
+var _ interfaces.ISnapshotDatabase = (*LevelDB)(nil)
+
+// Snapshot takes a view of the database as it is now, writes made afterwards are not part of it
+func (db *LevelDB) Snapshot() (interfaces.IDatabaseSnapshot, error) {
+	db.dbLock.RLock()
+	defer db.dbLock.RUnlock()
+
+	snap, err := db.lDB.GetSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	return &levelSnapshot{snap: snap}, nil
+}
+
+type levelSnapshot struct {
+	snap *leveldb.Snapshot
+}
+
+// levelSnapshotBatchSize is the number of records written to the copy at a time
+const levelSnapshotBatchSize = 1000
+
+func (s *levelSnapshot) CopyTo(path string) (uint64, error) {
+	err := os.MkdirAll(path, 0750)
+	if err != nil {
+		return 0, err
+	}
+	dst, err := leveldb.OpenFile(path, &opt.Options{ErrorIfExist: true})
+	if err != nil {
+		return 0, err
+	}
+	defer dst.Close()
+
+	iter := s.snap.NewIterator(nil, nil)
+	defer iter.Release()
+
+	var count uint64
+	batch := new(leveldb.Batch)
+	for iter.Next() {
+		// the batch copies the key and the value
+		batch.Put(iter.Key(), iter.Value())
+		count++
+		if batch.Len() >= levelSnapshotBatchSize {
+			if err := dst.Write(batch, nil); err != nil {
+				return count, err
+			}
+			batch.Reset()
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return count, err
+	}
+	if err := dst.Write(batch, nil); err != nil {
+		return count, err
+	}
+	return count, nil
+}
+
+func (s *levelSnapshot) Release() {
+	s.snap.Release()
+}
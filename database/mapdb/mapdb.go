@@ -5,6 +5,7 @@
 package mapdb
 
 import (
+	"bytes"
 	"sort"
 	"sync"
 
@@ -228,6 +229,92 @@ func (db *MapDB) GetAll(bucket []byte, sample interfaces.BinaryMarshallableAndCo
 	return answer, keys, nil
 }
 
+// Iterate walks over the records of the bucket selected by the options. The keys in range are
+// collected when the iterator is created, so later writes are not visible to it.
+func (db *MapDB) Iterate(bucket []byte, options interfaces.IterateOptions) (interfaces.IIterator, error) {
+	db.createCache(bucket)
+
+	db.Sem.RLock()
+	defer db.Sem.RUnlock()
+
+	it := new(mapIterator)
+	for k, v := range db.Cache[string(bucket)] {
+		key := []byte(k)
+		if options.InRange(key) {
+			it.keys = append(it.keys, key)
+			it.values = append(it.values, v)
+		}
+	}
+	sort.Sort(&it.byKey)
+	if options.Reverse {
+		for i, j := 0, len(it.keys)-1; i < j; i, j = i+1, j-1 {
+			it.swap(i, j)
+		}
+	}
+	if options.Limit > 0 && len(it.keys) > options.Limit {
+		it.keys = it.keys[:options.Limit]
+		it.values = it.values[:options.Limit]
+	}
+	it.index = -1
+	return it, nil
+}
+
+type mapIterator struct {
+	byKey
+	index int
+}
+
+var _ interfaces.IIterator = (*mapIterator)(nil)
+
+// byKey sorts the keys and the values together
+type byKey struct {
+	keys   [][]byte
+	values [][]byte
+}
+
+func (b *byKey) Len() int           { return len(b.keys) }
+func (b *byKey) Less(i, j int) bool { return bytes.Compare(b.keys[i], b.keys[j]) < 0 }
+func (b *byKey) Swap(i, j int)      { b.swap(i, j) }
+
+func (b *byKey) swap(i, j int) {
+	b.keys[i], b.keys[j] = b.keys[j], b.keys[i]
+	b.values[i], b.values[j] = b.values[j], b.values[i]
+}
+
+func (it *mapIterator) Next() bool {
+	if it.index < len(it.keys) {
+		it.index++
+	}
+	return it.index < len(it.keys)
+}
+
+func (it *mapIterator) Key() []byte {
+	if it.index < 0 || it.index >= len(it.keys) {
+		return nil
+	}
+	return it.keys[it.index]
+}
+
+func (it *mapIterator) Value(destination interfaces.BinaryMarshallable) (interfaces.BinaryMarshallable, error) {
+	if it.index < 0 || it.index >= len(it.values) || it.values[it.index] == nil {
+		return nil, nil
+	}
+	_, err := destination.UnmarshalBinaryData(it.values[it.index])
+	if err != nil {
+		return nil, err
+	}
+	return destination, nil
+}
+
+func (it *mapIterator) Error() error {
+	return nil
+}
+
+func (it *mapIterator) Release() {
+	it.keys = nil
+	it.values = nil
+}
+
 func (db *MapDB) Clear(bucket []byte) error {
 	db.Sem.Lock()
 	defer db.Sem.Unlock()
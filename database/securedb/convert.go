@@ -0,0 +1,186 @@
+package securedb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/leveldb"
+)
+
+// PassphraseEnv is the environment variable the passphrase of an encrypted database is read
+// from when no key file is given
+const PassphraseEnv = "FACTOMD_DB_PASSPHRASE"
+
+// ConvertBatchSize is the number of records EncryptInPlace and DecryptInPlace rewrite at once
+var ConvertBatchSize = 1000
+
+// LoadPassphrase reads the passphrase from the key file, or from PassphraseEnv if no file is
+// given. A trailing newline in the file is not part of the passphrase
+func LoadPassphrase(keyFile string) (string, error) {
+	var passphrase string
+	if keyFile != "" {
+		data, err := ioutil.ReadFile(keyFile)
+		if err != nil {
+			return "", err
+		}
+		passphrase = strings.TrimRight(string(data), "\r\n")
+	} else {
+		passphrase = os.Getenv(PassphraseEnv)
+	}
+	if passphrase == "" {
+		return "", fmt.Errorf("No passphrase for the encrypted database, set a key file or %s", PassphraseEnv)
+	}
+	return passphrase, nil
+}
+
+// EncryptInPlace encrypts the records of the given buckets, or of all of them if none are
+// given. Records already encrypted with the passphrase are left as they are, so an interrupted
+// run can be started again
+func EncryptInPlace(db interfaces.IDatabase, passphrase string, buckets [][]byte, progress func(bucket []byte, records int)) error {
+	e, err := NewEncryptedDBFromDatabase(db, passphrase, buckets)
+	if err != nil {
+		return err
+	}
+	return e.convert(true, progress)
+}
+
+// DecryptInPlace decrypts the records of the encrypted buckets, then drops the metadata of the
+// encrypted database, without which no record could be decrypted anymore. Records that are not
+// encrypted are left as they are, so an interrupted run can be started again
+func DecryptInPlace(db interfaces.IDatabase, passphrase string, progress func(bucket []byte, records int)) error {
+	encrypted, err := IsEncrypted(db)
+	if err != nil {
+		return err
+	}
+	if !encrypted {
+		return fmt.Errorf("The database is not encrypted")
+	}
+	e, err := OpenEncryptedDB(db, passphrase)
+	if err != nil {
+		return err
+	}
+	if err := e.convert(false, progress); err != nil {
+		return err
+	}
+	return db.Delete(EncyptedMetaData, EncyptedMetaData)
+}
+
+// storedKeysDatabase is implemented by the databases that cannot list their buckets, LevelDB and
+// Badger, to walk and rewrite all of their records by the keys they are stored under. Both store
+// a record under its bucket and key joined by a ';'
+type storedKeysDatabase interface {
+	IterateStored(options interfaces.IterateOptions) (interfaces.IIterator, error)
+	PutStoredInBatch(records []interfaces.Record) error
+}
+
+// convert rewrites the records of the encrypted buckets
+func (db *EncryptedDB) convert(encrypt bool, progress func(bucket []byte, records int)) error {
+	buckets := db.bucketList()
+	if buckets == nil {
+		var err error
+		buckets, err = db.db.ListAllBuckets()
+		if err != nil {
+			stored, ok := db.db.(storedKeysDatabase)
+			if !ok {
+				return err
+			}
+			// The metadata is the only record left as it is
+			metadata := leveldb.CombineBucketAndKey(EncyptedMetaData, EncyptedMetaData)
+			count, err := db.convertRecords(encrypt, stored.IterateStored, stored.PutStoredInBatch, metadata)
+			if err == nil && progress != nil {
+				progress(nil, count)
+			}
+			return err
+		}
+	}
+
+	for _, bucket := range buckets {
+		if bytes.Equal(bucket, EncyptedMetaData) {
+			continue
+		}
+		bucket := bucket
+		iterate := func(options interfaces.IterateOptions) (interfaces.IIterator, error) {
+			return db.db.Iterate(bucket, options)
+		}
+		put := func(records []interfaces.Record) error {
+			for i := range records {
+				records[i].Bucket = bucket
+			}
+			return db.db.PutInBatch(records)
+		}
+		count, err := db.convertRecords(encrypt, iterate, put, nil)
+		if err != nil {
+			return err
+		}
+		if progress != nil {
+			progress(bucket, count)
+		}
+	}
+	return nil
+}
+
+// convertRecords rewrites the records visited by the iterator, ConvertBatchSize at a time, but
+// the one under the skipped key. It returns the number of records visited
+func (db *EncryptedDB) convertRecords(encrypt bool, iterate func(interfaces.IterateOptions) (interfaces.IIterator, error), put func([]interfaces.Record) error, skip []byte) (int, error) {
+	var start []byte
+	count := 0
+	for {
+		it, err := iterate(interfaces.IterateOptions{Start: start, Limit: ConvertBatchSize})
+		if err != nil {
+			return count, err
+		}
+		var records []interfaces.Record
+		n := 0
+		for it.Next() {
+			n++
+			key := append([]byte{}, it.Key()...)
+			start = append(append([]byte{}, key...), 0)
+			if skip != nil && bytes.Equal(key, skip) {
+				continue
+			}
+			raw := new(primitives.ByteSlice)
+			if _, err := it.Value(raw); err != nil {
+				it.Release()
+				return count, err
+			}
+			if data := db.convertValue(raw.Bytes, encrypt); data != nil {
+				records = append(records, interfaces.Record{Key: key, Data: data})
+			}
+		}
+		err = it.Error()
+		it.Release()
+		if err != nil {
+			return count, err
+		}
+		if len(records) > 0 {
+			if err := put(records); err != nil {
+				return count, err
+			}
+		}
+		count += n
+		if n < ConvertBatchSize {
+			return count, nil
+		}
+	}
+}
+
+// convertValue returns the value to write in place of the stored one, or nil if it is already
+// encrypted, or decrypted, as asked
+func (db *EncryptedDB) convertValue(raw []byte, encrypt bool) interfaces.BinaryMarshallable {
+	plain := new(primitives.ByteSlice)
+	_, err := NewEncryptedMarshaler(db.encryptionkey, plain).UnmarshalBinaryData(raw)
+	isEncrypted := err == nil && int(binary.BigEndian.Uint32(raw[:4]))+4 == len(raw)
+	switch {
+	case encrypt && !isEncrypted:
+		return NewEncryptedMarshaler(db.encryptionkey, &primitives.ByteSlice{Bytes: raw})
+	case !encrypt && isEncrypted:
+		return plain
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package securedb_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/badgerdb"
+	"github.com/FactomProject/factomd/database/leveldb"
+	"github.com/FactomProject/factomd/database/mapdb"
+	. "github.com/FactomProject/factomd/database/securedb"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	secretBucket = []byte("KeyValueStore")
+	plainBucket  = []byte("DBlock")
+)
+
+func fillConvertDatabase(t *testing.T, db interfaces.IDatabase) {
+	for _, bucket := range [][]byte{secretBucket, plainBucket} {
+		for i := 0; i < 25; i++ {
+			err := db.Put(bucket, []byte(fmt.Sprintf("key%02d", i)), &primitives.ByteSlice{Bytes: []byte(fmt.Sprintf("%s value %d", bucket, i))})
+			assert.NoError(t, err)
+		}
+	}
+}
+
+// checkConvertDatabase reads all the records back, through the encryption if given
+func checkConvertDatabase(t *testing.T, db interfaces.IDatabase) {
+	for _, bucket := range [][]byte{secretBucket, plainBucket} {
+		for i := 0; i < 25; i++ {
+			v, err := db.Get(bucket, []byte(fmt.Sprintf("key%02d", i)), new(primitives.ByteSlice))
+			if assert.NoError(t, err) && assert.NotNil(t, v) {
+				assert.Equal(t, fmt.Sprintf("%s value %d", bucket, i), string(v.(*primitives.ByteSlice).Bytes))
+			}
+		}
+	}
+}
+
+func rawValue(t *testing.T, db interfaces.IDatabase, bucket []byte) string {
+	v, err := db.Get(bucket, []byte("key03"), new(primitives.ByteSlice))
+	assert.NoError(t, err)
+	return string(v.(*primitives.ByteSlice).Bytes)
+}
+
+func testConvertInPlace(t *testing.T, db interfaces.IDatabase) {
+	defer func(size int) { ConvertBatchSize = size }(ConvertBatchSize)
+	ConvertBatchSize = 7
+	fillConvertDatabase(t, db)
+
+	// Only the named bucket is encrypted, twice to check that a run can be repeated
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, EncryptInPlace(db, "secret", [][]byte{secretBucket}, nil))
+		encrypted, err := IsEncrypted(db)
+		assert.NoError(t, err)
+		assert.True(t, encrypted)
+		assert.NotEqual(t, "KeyValueStore value 3", rawValue(t, db, secretBucket))
+		assert.Equal(t, "DBlock value 3", rawValue(t, db, plainBucket))
+	}
+	e, err := NewEncryptedDBFromDatabase(db, "secret", [][]byte{secretBucket})
+	assert.NoError(t, err)
+	checkConvertDatabase(t, e)
+
+	_, err = NewEncryptedDBFromDatabase(db, "wrong", [][]byte{secretBucket})
+	assert.Error(t, err)
+	buckets, err := EncryptedBucketsOf(db)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{secretBucket}, buckets)
+
+	// Other buckets would be read as ciphertext, or not decrypted
+	_, err = NewEncryptedDBFromDatabase(db, "secret", nil)
+	assert.Error(t, err)
+	_, err = NewEncryptedDBFromDatabase(db, "secret", [][]byte{secretBucket, plainBucket})
+	assert.Error(t, err)
+	assert.Error(t, EncryptInPlace(db, "secret", nil, nil))
+	assert.Equal(t, "DBlock value 3", rawValue(t, db, plainBucket))
+
+	// Everything is encrypted once the database is decrypted
+	assert.NoError(t, DecryptInPlace(db, "secret", nil))
+	checkConvertDatabase(t, db)
+	assert.NoError(t, EncryptInPlace(db, "secret", nil, nil))
+	assert.NotEqual(t, "DBlock value 3", rawValue(t, db, plainBucket))
+	e, err = NewEncryptedDBFromDatabase(db, "secret", nil)
+	assert.NoError(t, err)
+	checkConvertDatabase(t, e)
+
+	assert.Error(t, DecryptInPlace(db, "wrong", nil))
+	assert.NoError(t, DecryptInPlace(db, "secret", nil))
+	encrypted, err := IsEncrypted(db)
+	assert.NoError(t, err)
+	assert.False(t, encrypted)
+	checkConvertDatabase(t, db)
+	assert.Error(t, DecryptInPlace(db, "secret", nil))
+}
+
+func TestConvertInPlaceMap(t *testing.T) {
+	db := new(mapdb.MapDB)
+	db.Init(nil)
+	testConvertInPlace(t, db)
+}
+
+func TestConvertInPlaceLevelDB(t *testing.T) {
+	dir, err := ioutil.TempDir("", "securedb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := leveldb.NewLevelDB(filepath.Join(dir, "ldb"), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	testConvertInPlace(t, db)
+}
+
+func TestConvertInPlaceBadger(t *testing.T) {
+	dir, err := ioutil.TempDir("", "securedb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := badgerdb.NewBadgerDB(dir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	testConvertInPlace(t, db)
+}
+
+func TestLoadPassphrase(t *testing.T) {
+	defer os.Setenv(PassphraseEnv, os.Getenv(PassphraseEnv))
+
+	os.Setenv(PassphraseEnv, "")
+	_, err := LoadPassphrase("")
+	assert.Error(t, err)
+
+	os.Setenv(PassphraseEnv, "from env")
+	p, err := LoadPassphrase("")
+	assert.NoError(t, err)
+	assert.Equal(t, "from env", p)
+
+	f, err := ioutil.TempFile("", "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString("from file\r\n")
+	f.Close()
+	p, err = LoadPassphrase(f.Name())
+	assert.NoError(t, err)
+	assert.Equal(t, "from file", p)
+
+	_, err = LoadPassphrase(f.Name() + ".missing")
+	assert.Error(t, err)
+}
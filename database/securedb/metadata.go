@@ -15,6 +15,9 @@ var _ = fmt.Sprint
 type SecureDBMetaData struct {
 	Salt      primitives.ByteSlice
 	Challenge primitives.ByteSlice
+	// Buckets are the names of the encrypted buckets in order, none if all of them are. The
+	// metadata written before the buckets could be chosen ends after the challenge
+	Buckets [][]byte
 }
 
 func NewSecureDBMetaData() *SecureDBMetaData {
@@ -31,6 +34,15 @@ func (m *SecureDBMetaData) IsSameAs(b *SecureDBMetaData) bool {
 		return false
 	}
 
+	if len(m.Buckets) != len(b.Buckets) {
+		return false
+	}
+	for i := range m.Buckets {
+		if !bytes.Equal(m.Buckets[i], b.Buckets[i]) {
+			return false
+		}
+	}
+
 	return true
 }
 
@@ -65,6 +77,26 @@ func (m *SecureDBMetaData) UnmarshalBinaryData(data []byte) (newData []byte, err
 	copy(m.Challenge.Bytes, newData[4:clen+4])
 	newData = newData[clen+4:]
 
+	m.Buckets = nil
+	if len(newData) == 0 {
+		return
+	}
+	count, err := bytesToUint32(newData[:4])
+	if err != nil {
+		return nil, err
+	}
+	newData = newData[4:]
+	for i := uint32(0); i < count; i++ {
+		blen, err := bytesToUint32(newData[:4])
+		if err != nil {
+			return nil, err
+		}
+		bucket := make([]byte, blen)
+		copy(bucket, newData[4:blen+4])
+		m.Buckets = append(m.Buckets, bucket)
+		newData = newData[blen+4:]
+	}
+
 	return
 }
 
@@ -90,6 +122,12 @@ func (m *SecureDBMetaData) MarshalBinary() (rval []byte, err error) {
 	}
 	buf.Write(data)
 
+	buf.Write(intToBytes(len(m.Buckets)))
+	for _, bucket := range m.Buckets {
+		buf.Write(intToBytes(len(bucket)))
+		buf.Write(bucket)
+	}
+
 	return buf.DeepCopyBytes(), nil
 }
 
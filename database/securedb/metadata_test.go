@@ -19,6 +19,10 @@ func TestSecureDBMetaData(t *testing.T) {
 		c.Bytes = random.RandByteSlice()
 		m.Challenge = c
 
+		for j := i % 3; j > 0; j-- {
+			m.Buckets = append(m.Buckets, random.RandByteSlice())
+		}
+
 		data, err := m.MarshalBinary()
 		if err != nil {
 			t.Error(err)
@@ -36,5 +40,17 @@ func TestSecureDBMetaData(t *testing.T) {
 		if !m.IsSameAs(m2) {
 			t.Errorf("Not same %x | %x", m.Challenge.Bytes, m2.Challenge.Bytes)
 		}
+
+		// Metadata written before the buckets were kept is of all of them
+		if len(m.Buckets) == 0 {
+			m3 := new(SecureDBMetaData)
+			nd, err = m3.UnmarshalBinaryData(data[:len(data)-4])
+			if err != nil {
+				t.Error(err)
+			}
+			if len(nd) != 0 || m3.Buckets != nil || !m.IsSameAs(m3) {
+				t.Errorf("Old metadata not read back")
+			}
+		}
 	}
 }
@@ -9,6 +9,8 @@ import (
 	"crypto/rand"
 	"crypto/subtle"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/FactomProject/factomd/common/interfaces"
@@ -39,6 +41,9 @@ type EncryptedDB struct {
 	// encryptionkey is a hash of the password and salt
 	encryptionkey []byte
 
+	// buckets are the only buckets encrypted, all of them if nil
+	buckets map[string]bool
+
 	// Allow the wallet to be locked, by gating access based
 	// on time.
 	UnlockedUntil time.Time
@@ -62,6 +67,55 @@ func NewEncryptedDB(filename, dbtype, password string) (*EncryptedDB, error) {
 	return e, nil
 }
 
+// NewEncryptedDBFromDatabase wraps an open database. Only the given buckets are encrypted, the
+// others are read and written as they are. All of them are encrypted if none are given. The
+// buckets are kept in the metadata of a new encrypted database, an existing one is refused if
+// they are not the buckets it was encrypted with
+func NewEncryptedDBFromDatabase(db interfaces.IDatabase, password string, buckets [][]byte) (*EncryptedDB, error) {
+	e := new(EncryptedDB)
+	e.db = db
+	if len(buckets) > 0 {
+		e.buckets = make(map[string]bool)
+		for _, b := range buckets {
+			e.buckets[string(b)] = true
+		}
+	}
+
+	err := e.initSecureDB(password)
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// OpenEncryptedDB wraps an open encrypted database, encrypting the buckets it was encrypted with
+func OpenEncryptedDB(db interfaces.IDatabase, password string) (*EncryptedDB, error) {
+	buckets, err := EncryptedBucketsOf(db)
+	if err != nil {
+		return nil, err
+	}
+	return NewEncryptedDBFromDatabase(db, password, buckets)
+}
+
+// EncryptedBucketsOf returns the buckets the database was encrypted with, nil for all of them
+func EncryptedBucketsOf(db interfaces.IDatabase) ([][]byte, error) {
+	m := new(SecureDBMetaData)
+	v, err := db.Get(EncyptedMetaData, EncyptedMetaData, m)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, fmt.Errorf("The database is not encrypted")
+	}
+	return m.Buckets, nil
+}
+
+// IsEncrypted returns true if the database holds the metadata of an encrypted database
+func IsEncrypted(db interfaces.IDatabase) (bool, error) {
+	return db.DoesKeyExist(EncyptedMetaData, EncyptedMetaData)
+}
+
 // InitSecureDB will init the Salt and metadata
 func (db *EncryptedDB) initSecureDB(password string) error {
 	m := new(SecureDBMetaData)
@@ -73,8 +127,13 @@ func (db *EncryptedDB) initSecureDB(password string) error {
 	if v == nil {
 		// need to init new metadata
 		db.initNewMetaData()
+		db.metadata.Buckets = db.bucketList()
 	} else {
 		db.metadata = m
+		// Records of the other buckets would be read as ciphertext, or decrypted when they are not
+		if !bucketListsEqual(m.Buckets, db.bucketList()) {
+			return fmt.Errorf("The database encrypts %s, not %s", bucketListString(m.Buckets), bucketListString(db.bucketList()))
+		}
 	}
 
 	key, err := GetKey(password, db.metadata.Salt.Bytes)
@@ -148,6 +207,44 @@ func (db *EncryptedDB) UnlockFor(password string, duration time.Duration) error
 	return nil
 }
 
+// bucketList returns the encrypted buckets in order, nil if all of them are
+func (db *EncryptedDB) bucketList() [][]byte {
+	var buckets [][]byte
+	for name := range db.buckets {
+		buckets = append(buckets, []byte(name))
+	}
+	sort.Slice(buckets, func(i, j int) bool { return bytes.Compare(buckets[i], buckets[j]) < 0 })
+	return buckets
+}
+
+func bucketListsEqual(a, b [][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !bytes.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func bucketListString(buckets [][]byte) string {
+	if len(buckets) == 0 {
+		return "all buckets"
+	}
+	names := make([]string, len(buckets))
+	for i, b := range buckets {
+		names[i] = string(b)
+	}
+	return "buckets " + strings.Join(names, ",")
+}
+
+// encrypts returns true if the records of the bucket are encrypted
+func (db *EncryptedDB) encrypts(bucket []byte) bool {
+	return db.buckets == nil || db.buckets[string(bucket)]
+}
+
 func (db *EncryptedDB) isLocked() bool {
 	// Not being used, means always unlocked
 	if db.UnlockedUntil == TimeCheck {
@@ -188,6 +285,9 @@ func (db *EncryptedDB) Get(bucket []byte, key []byte, destination interfaces.Bin
 	if db.isLocked() {
 		return nil, lockedError
 	}
+	if !db.encrypts(bucket) {
+		return db.db.Get(bucket, key, destination)
+	}
 
 	e := NewEncryptedMarshaler(db.encryptionkey, destination)
 	tmp, err := db.db.Get(bucket, key, e)
@@ -206,6 +306,9 @@ func (db *EncryptedDB) Put(bucket []byte, key []byte, data interfaces.BinaryMars
 	if db.isLocked() {
 		return lockedError
 	}
+	if !db.encrypts(bucket) {
+		return db.db.Put(bucket, key, data)
+	}
 
 	e := NewEncryptedMarshaler(db.encryptionkey, data)
 	return db.db.Put(bucket, key, e)
@@ -220,6 +323,10 @@ func (db *EncryptedDB) PutInBatch(records []interfaces.Record) error {
 	for i, r := range records {
 		cipherRecords[i].Bucket = r.Bucket
 		cipherRecords[i].Key = r.Key
+		if !db.encrypts(r.Bucket) {
+			cipherRecords[i].Data = r.Data
+			continue
+		}
 
 		e := NewEncryptedMarshaler(db.encryptionkey, r.Data)
 		cipherRecords[i].Data = e
@@ -253,6 +360,9 @@ func (db *EncryptedDB) GetAll(bucket []byte, sample interfaces.BinaryMarshallabl
 	if db.isLocked() {
 		return nil, nil, lockedError
 	}
+	if !db.encrypts(bucket) {
+		return db.db.GetAll(bucket, sample)
+	}
 
 	s := NewEncryptedMarshaler(db.encryptionkey, sample.(interfaces.BinaryMarshallable))
 
@@ -269,6 +379,91 @@ func (db *EncryptedDB) GetAll(bucket []byte, sample interfaces.BinaryMarshallabl
 	return originalSamples, keys, err
 }
 
+// Iterate walks over the records of the bucket selected by the options, decrypting the values
+func (db *EncryptedDB) Iterate(bucket []byte, options interfaces.IterateOptions) (interfaces.IIterator, error) {
+	if db.isLocked() {
+		return nil, lockedError
+	}
+
+	it, err := db.db.Iterate(bucket, options)
+	if err != nil {
+		return nil, err
+	}
+	if !db.encrypts(bucket) {
+		return it, nil
+	}
+	return &encryptedIterator{IIterator: it, encryptionkey: db.encryptionkey}, nil
+}
+
+var _ interfaces.ISnapshotDatabase = (*EncryptedDB)(nil)
+var _ interfaces.ICompactDatabase = (*EncryptedDB)(nil)
+
+// Snapshot takes a snapshot of the wrapped database. The records are copied as they are stored,
+// so the copy is encrypted with the same passphrase and holds the same metadata
+func (db *EncryptedDB) Snapshot() (interfaces.IDatabaseSnapshot, error) {
+	if db.isLocked() {
+		return nil, lockedError
+	}
+	snapshotter, ok := db.db.(interfaces.ISnapshotDatabase)
+	if !ok {
+		return nil, fmt.Errorf("The database does not support snapshots")
+	}
+	return snapshotter.Snapshot()
+}
+
+func (db *EncryptedDB) compacter() (interfaces.ICompactDatabase, error) {
+	if db.isLocked() {
+		return nil, lockedError
+	}
+	compacter, ok := db.db.(interfaces.ICompactDatabase)
+	if !ok {
+		return nil, fmt.Errorf("The database does not support compaction")
+	}
+	return compacter, nil
+}
+
+// Compact compacts the wrapped database, which keeps the records encrypted
+func (db *EncryptedDB) Compact() error {
+	compacter, err := db.compacter()
+	if err != nil {
+		return err
+	}
+	return compacter.Compact()
+}
+
+func (db *EncryptedDB) BucketSize(bucket []byte) (int64, error) {
+	compacter, err := db.compacter()
+	if err != nil {
+		return 0, err
+	}
+	return compacter.BucketSize(bucket)
+}
+
+func (db *EncryptedDB) Size() (int64, error) {
+	compacter, err := db.compacter()
+	if err != nil {
+		return 0, err
+	}
+	return compacter.Size()
+}
+
+type encryptedIterator struct {
+	interfaces.IIterator
+	encryptionkey []byte
+}
+
+func (it *encryptedIterator) Value(destination interfaces.BinaryMarshallable) (interfaces.BinaryMarshallable, error) {
+	e := NewEncryptedMarshaler(it.encryptionkey, destination)
+	tmp, err := it.IIterator.Value(e)
+	if err != nil {
+		return nil, err
+	}
+	if tmp == nil {
+		return nil, nil
+	}
+	return e.Original, nil
+}
+
 func (db *EncryptedDB) Init(filename string, dbtype string) {
 	var err error
 	switch dbtype {
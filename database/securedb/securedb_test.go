@@ -25,3 +25,30 @@ func TestSecureDB(t *testing.T) {
 
 	os.Remove("test.db")
 }
+
+func TestEncryptedDBForwardsCompaction(t *testing.T) {
+	os.Remove("compact.db")
+	defer os.Remove("compact.db")
+	s, err := NewEncryptedDB("compact.db", "Bolt", "rightPassword")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	if snap, err := s.Snapshot(); err != nil {
+		t.Error(err)
+	} else {
+		snap.Release()
+	}
+	if _, err := s.Size(); err != nil {
+		t.Error(err)
+	}
+	if _, err := s.BucketSize(EncyptedMetaData); err != nil {
+		t.Error(err)
+	}
+
+	s.Lock()
+	if _, err := s.Size(); err == nil {
+		t.Error("Should error when locked")
+	}
+}
@@ -0,0 +1,270 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+// Package snapshot describes, verifies and packs the point-in-time copies of a node's database
+// that are taken while the node runs.
+package snapshot
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/badgerdb"
+	"github.com/FactomProject/factomd/database/boltdb"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+	"github.com/FactomProject/factomd/database/leveldb"
+	"github.com/FactomProject/factomd/database/securedb"
+)
+
+// ManifestFile is the name of the manifest in the snapshot directory
+const ManifestFile = "snapshot.json"
+
+// Manifest describes a snapshot. The paths are relative to the snapshot directory, laid out the
+// same way as under the database path of the node, so the snapshot can be copied into place.
+type Manifest struct {
+	DBType   string `json:"dbtype"`
+	Network  string `json:"network"`
+	Database string `json:"database"`
+	Records  uint64 `json:"records"`
+	// Height and KeyMR of the directory block head of the snapshot
+	DBHeight uint32 `json:"dbheight"`
+	KeyMR    string `json:"keymr"`
+	// FastBoot is empty if the node had no fastboot file at or below the height of the snapshot
+	FastBoot       string    `json:"fastboot,omitempty"`
+	FastBootHeight uint32    `json:"fastbootheight,omitempty"`
+	Created        time.Time `json:"created"`
+}
+
+func WriteManifest(dir string, m *Manifest) error {
+	b, err := json.MarshalIndent(m, "", "\t")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, ManifestFile), b, 0644)
+}
+
+func ReadManifest(dir string) (*Manifest, error) {
+	b, err := ioutil.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		return nil, err
+	}
+	m := new(Manifest)
+	err = json.Unmarshal(b, m)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// OpenDatabase opens an existing database of the type
+func OpenDatabase(dbType string, path string) (interfaces.IDatabase, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	switch dbType {
+	case "LDB":
+		return leveldb.NewLevelDB(path, false)
+	case "Bolt":
+		return boltdb.NewBoltDB(nil, path), nil
+	case "Badger":
+		return badgerdb.NewBadgerDB(path, false)
+	}
+	return nil, fmt.Errorf("Snapshots of %v databases are not supported", dbType)
+}
+
+// OpenDatabaseWithKey opens an existing database of the type, read through the encryption if it
+// is encrypted. The passphrase comes from the key file, or from securedb.PassphraseEnv if no file
+// is given, and is only needed for an encrypted database
+func OpenDatabaseWithKey(dbType string, path string, keyFile string) (interfaces.IDatabase, error) {
+	db, err := OpenDatabase(dbType, path)
+	if err != nil {
+		return nil, err
+	}
+	wrapped, err := UnlockWithKey(keyFile)(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return wrapped, nil
+}
+
+// Unlock wraps a database opened from a snapshot in the decryption it needs
+type Unlock func(db interfaces.IDatabase) (interfaces.IDatabase, error)
+
+// UnlockWithKey returns an Unlock that decrypts an encrypted database with the passphrase of the
+// key file, see OpenDatabaseWithKey. A database that is not encrypted is left as it is
+func UnlockWithKey(keyFile string) Unlock {
+	return func(db interfaces.IDatabase) (interfaces.IDatabase, error) {
+		encrypted, err := securedb.IsEncrypted(db)
+		if err != nil || !encrypted {
+			return db, err
+		}
+		passphrase, err := securedb.LoadPassphrase(keyFile)
+		if err != nil {
+			return nil, err
+		}
+		return securedb.OpenEncryptedDB(db, passphrase)
+	}
+}
+
+// openSnapshot opens the database of a snapshot. An encrypted one needs unlock
+func openSnapshot(dbType string, path string, unlock Unlock) (interfaces.IDatabase, error) {
+	db, err := OpenDatabase(dbType, path)
+	if err != nil {
+		return nil, err
+	}
+	if unlock != nil {
+		wrapped, err := unlock(db)
+		if err != nil {
+			db.Close()
+			return nil, err
+		}
+		return wrapped, nil
+	}
+	encrypted, err := securedb.IsEncrypted(db)
+	if err == nil && encrypted {
+		err = fmt.Errorf("The database is encrypted, a passphrase is needed to read it")
+	}
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// Head returns the directory block head of the database
+func Head(dbType string, path string, unlock Unlock) (interfaces.IDirectoryBlock, error) {
+	db, err := openSnapshot(dbType, path, unlock)
+	if err != nil {
+		return nil, err
+	}
+	dbo := databaseOverlay.NewOverlay(db)
+	defer dbo.Close()
+
+	head, err := dbo.FetchDBlockHead()
+	if err != nil {
+		return nil, err
+	}
+	if head == nil {
+		return nil, fmt.Errorf("The database has no directory block head")
+	}
+	return head, nil
+}
+
+// Verify checks that the snapshot in the directory matches its manifest. The directory blocks
+// have to form an unbroken chain from the head down to the first block, and the fastboot file
+// has to pass its integrity check. An encrypted snapshot is read through unlock.
+func Verify(dir string, unlock Unlock) (*Manifest, error) {
+	m, err := ReadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := openSnapshot(m.DBType, filepath.Join(dir, m.Database), unlock)
+	if err != nil {
+		return nil, err
+	}
+	dbo := databaseOverlay.NewOverlay(db)
+	defer dbo.Close()
+
+	head, err := dbo.FetchDBlockHead()
+	if err != nil {
+		return nil, err
+	}
+	if head == nil {
+		return nil, fmt.Errorf("The snapshot has no directory block head")
+	}
+	if head.GetDatabaseHeight() != m.DBHeight || head.GetKeyMR().String() != m.KeyMR {
+		return nil, fmt.Errorf("The head of the snapshot is %v at height %v, the manifest says %v at height %v",
+			head.GetKeyMR(), head.GetDatabaseHeight(), m.KeyMR, m.DBHeight)
+	}
+
+	next := head
+	for h := int(m.DBHeight) - 1; h >= 0; h-- {
+		dblock, err := dbo.FetchDBlockByHeight(uint32(h))
+		if err != nil {
+			return nil, err
+		}
+		if dblock == nil {
+			return nil, fmt.Errorf("Directory block %v is missing from the snapshot", h)
+		}
+		if dblock.GetKeyMR().IsSameAs(next.GetHeader().GetPrevKeyMR()) == false {
+			return nil, fmt.Errorf("Directory block %v does not link to directory block %v", h+1, h)
+		}
+		next = dblock
+	}
+
+	if m.FastBoot != "" {
+		if m.FastBootHeight > m.DBHeight {
+			return nil, fmt.Errorf("The fastboot file at height %v is ahead of the database at height %v", m.FastBootHeight, m.DBHeight)
+		}
+		err = VerifyFastBoot(filepath.Join(dir, m.FastBoot))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// VerifyFastBoot checks the integrity hash at the start of a fastboot file
+func VerifyFastBoot(path string) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	h := primitives.NewZeroHash()
+	rest, err := h.UnmarshalBinaryData(b)
+	if err != nil {
+		return err
+	}
+	if h.IsSameAs(primitives.Sha(rest)) == false {
+		return fmt.Errorf("The fastboot file %v does not match its hash", path)
+	}
+	return nil
+}
+
+// WriteTar packs the snapshot in the directory into a tar stream
+func WriteTar(dir string, w io.Writer) error {
+	tw := tar.NewWriter(w)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(dir, path)
+		if err != nil || name == "." {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(name)
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
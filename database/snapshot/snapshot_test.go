@@ -0,0 +1,184 @@
+package snapshot_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/badgerdb"
+	"github.com/FactomProject/factomd/database/boltdb"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+	"github.com/FactomProject/factomd/database/leveldb"
+	"github.com/FactomProject/factomd/database/securedb"
+	. "github.com/FactomProject/factomd/database/snapshot"
+	"github.com/FactomProject/factomd/testHelper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snapshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	open := map[string]func(path string) (interfaces.IDatabase, error){
+		"LDB": func(path string) (interfaces.IDatabase, error) { return leveldb.NewLevelDB(path, true) },
+		"Bolt": func(path string) (interfaces.IDatabase, error) {
+			return boltdb.NewAndCreateBoltDB(nil, path), nil
+		},
+		"Badger": func(path string) (interfaces.IDatabase, error) { return badgerdb.NewBadgerDB(path, true) },
+	}
+	for dbType, f := range open {
+		db, err := f(filepath.Join(dir, dbType, "live.db"))
+		if !assert.Nil(t, err, dbType) {
+			continue
+		}
+		dbo := databaseOverlay.NewOverlay(db)
+		testHelper.PopulateTestDatabaseOverlay(dbo)
+
+		snap, err := dbo.Snapshot()
+		if !assert.Nil(t, err, dbType) {
+			continue
+		}
+		// written after the view was taken
+		after := []byte("after")
+		assert.Nil(t, dbo.Put(after, after, primitives.Sha(after)))
+
+		m := new(Manifest)
+		m.DBType = dbType
+		m.Network = "LOCAL"
+		m.Database = filepath.Join("LOCAL", "copy.db")
+		target := filepath.Join(dir, dbType, "snapshot")
+		m.Records, err = snap.CopyTo(filepath.Join(target, m.Database))
+		snap.Release()
+		assert.Nil(t, err, dbType)
+		assert.NotZero(t, m.Records, dbType)
+		dbo.Close()
+
+		head, err := Head(dbType, filepath.Join(target, m.Database), nil)
+		if !assert.Nil(t, err, dbType) {
+			continue
+		}
+		assert.Equal(t, uint32(testHelper.BlockCount-1), head.GetDatabaseHeight(), dbType)
+		m.DBHeight = head.GetDatabaseHeight()
+		m.KeyMR = head.GetKeyMR().String()
+
+		// the copy does not have the record written after the view
+		copied, err := OpenDatabase(dbType, filepath.Join(target, m.Database))
+		if assert.Nil(t, err, dbType) {
+			exists, err := copied.DoesKeyExist(after, after)
+			assert.Nil(t, err, dbType)
+			assert.False(t, exists, dbType)
+			copied.Close()
+		}
+
+		// a fastboot file is checked against its hash
+		state := []byte("state")
+		m.FastBoot = "FastBoot_LOCAL.db"
+		assert.Nil(t, ioutil.WriteFile(filepath.Join(target, m.FastBoot), append(primitives.Sha(state).Bytes(), state...), 0644))
+
+		assert.Nil(t, WriteManifest(target, m), dbType)
+		verified, err := Verify(target, nil)
+		assert.Nil(t, err, dbType)
+		assert.Equal(t, m.KeyMR, verified.KeyMR, dbType)
+
+		assert.Nil(t, ioutil.WriteFile(filepath.Join(target, m.FastBoot), append(primitives.Sha(state).Bytes(), "other"...), 0644))
+		_, err = Verify(target, nil)
+		assert.NotNil(t, err, dbType)
+
+		m.FastBoot = ""
+		m.DBHeight++
+		assert.Nil(t, WriteManifest(target, m), dbType)
+		_, err = Verify(target, nil)
+		assert.NotNil(t, err, dbType)
+
+		// every file of the snapshot is in the tar stream
+		var buf bytes.Buffer
+		assert.Nil(t, WriteTar(target, &buf), dbType)
+		names := map[string]bool{}
+		tr := tar.NewReader(&buf)
+		for {
+			h, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if !assert.Nil(t, err, dbType) {
+				break
+			}
+			names[h.Name] = true
+		}
+		assert.True(t, names[ManifestFile], dbType)
+		assert.True(t, names["FastBoot_LOCAL.db"], dbType)
+		assert.True(t, names["LOCAL/copy.db"], dbType)
+	}
+}
+
+func TestSnapshotEncrypted(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snapshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := leveldb.NewLevelDB(filepath.Join(dir, "live.db"), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := securedb.NewEncryptedDBFromDatabase(db, "secret", [][]byte{databaseOverlay.DIRECTORYBLOCK})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dbo := databaseOverlay.NewOverlay(e)
+	testHelper.PopulateTestDatabaseOverlay(dbo)
+
+	snap, err := dbo.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "copy.db")
+	_, err = snap.CopyTo(path)
+	snap.Release()
+	dbo.Close()
+	assert.Nil(t, err)
+
+	// the copy keeps the encryption, so it can only be read with the passphrase
+	_, err = Head("LDB", path, nil)
+	assert.NotNil(t, err)
+	_, err = Head("LDB", path, func(db interfaces.IDatabase) (interfaces.IDatabase, error) {
+		return securedb.OpenEncryptedDB(db, "wrong")
+	})
+	assert.NotNil(t, err)
+	head, err := Head("LDB", path, func(db interfaces.IDatabase) (interfaces.IDatabase, error) {
+		return securedb.OpenEncryptedDB(db, "secret")
+	})
+	if assert.Nil(t, err) {
+		assert.Equal(t, uint32(testHelper.BlockCount-1), head.GetDatabaseHeight())
+	}
+
+	// the tools open it with the passphrase of a key file
+	keyFile := filepath.Join(dir, "passphrase.txt")
+	if err := ioutil.WriteFile(keyFile, []byte("secret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	db, err = OpenDatabaseWithKey("LDB", path, keyFile)
+	if assert.Nil(t, err) {
+		dbo = databaseOverlay.NewOverlay(db)
+		head, err = dbo.FetchDBlockHead()
+		if assert.Nil(t, err) && assert.NotNil(t, head) {
+			assert.Equal(t, uint32(testHelper.BlockCount-1), head.GetDatabaseHeight())
+		}
+		dbo.Close()
+	}
+	if err := ioutil.WriteFile(keyFile, []byte("wrong"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	_, err = OpenDatabaseWithKey("LDB", path, keyFile)
+	assert.NotNil(t, err)
+}
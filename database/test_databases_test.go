@@ -5,6 +5,7 @@
 package database_test
 
 import (
+	"encoding/binary"
 	"fmt"
 	"os"
 	"testing"
@@ -19,8 +20,10 @@ import (
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/primitives"
 	"github.com/FactomProject/factomd/common/primitives/random"
+	"github.com/FactomProject/factomd/database/badgerdb"
 	"github.com/FactomProject/factomd/database/boltdb"
 	"github.com/FactomProject/factomd/database/databaseOverlay"
+	"github.com/FactomProject/factomd/database/hybridDB"
 	"github.com/FactomProject/factomd/database/leveldb"
 	"github.com/FactomProject/factomd/database/mapdb"
 	"github.com/FactomProject/factomd/database/securedb"
@@ -69,7 +72,7 @@ func TestOneDatabase(t *testing.T) {
 }
 
 func TestAllDatabases(t *testing.T) {
-	totalTests := 4
+	totalTests := 5
 
 	// Secure Bolt
 	for i := 0; i < totalTests; i++ {
@@ -80,7 +83,7 @@ func TestAllDatabases(t *testing.T) {
 		testDB(t, m, i)
 		CleanupTest(t, m)
 	}
-	t.Log("Finished Secure Bolt DB (1/6)")
+	t.Log("Finished Secure Bolt DB (1/7)")
 
 	// Secure LDB
 	for i := 0; i < totalTests; i++ {
@@ -91,7 +94,7 @@ func TestAllDatabases(t *testing.T) {
 		testDB(t, m, i)
 		CleanupTest(t, m)
 	}
-	t.Log("Finished Secure LDB (2/6)")
+	t.Log("Finished Secure LDB (2/7)")
 
 	// Secure Map
 	for i := 0; i < totalTests; i++ {
@@ -102,7 +105,7 @@ func TestAllDatabases(t *testing.T) {
 		testDB(t, m, i)
 		CleanupTest(t, m)
 	}
-	t.Log("Finished Secure Map (3/6)")
+	t.Log("Finished Secure Map (3/7)")
 
 	// Bolt
 	for i := 0; i < totalTests; i++ {
@@ -110,7 +113,7 @@ func TestAllDatabases(t *testing.T) {
 		testDB(t, m, i)
 		CleanupTest(t, m)
 	}
-	t.Log("Finished Bolt DB (4/6)")
+	t.Log("Finished Bolt DB (4/7)")
 
 	// Level
 	for i := 0; i < totalTests; i++ {
@@ -121,7 +124,7 @@ func TestAllDatabases(t *testing.T) {
 		testDB(t, m, i)
 		CleanupTest(t, m)
 	}
-	t.Log("Finished LDB (5/6)")
+	t.Log("Finished LDB (5/7)")
 
 	// Map
 	for i := 0; i < totalTests; i++ {
@@ -129,7 +132,18 @@ func TestAllDatabases(t *testing.T) {
 		testDB(t, m, i)
 		CleanupTest(t, m)
 	}
-	t.Log("Finished Map (3/6)")
+	t.Log("Finished Map (6/7)")
+
+	// Badger
+	for i := 0; i < totalTests; i++ {
+		m, err := badgerdb.NewBadgerDB(dbFilename, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testDB(t, m, i)
+		CleanupTest(t, m)
+	}
+	t.Log("Finished Badger (7/7)")
 }
 
 func testDB(t *testing.T, m interfaces.IDatabase, i int) {
@@ -142,7 +156,21 @@ func testDB(t *testing.T, m interfaces.IDatabase, i int) {
 		testDoesKeyExist(t, m)
 	case 3:
 		testGetAll(t, m)
+	case 4:
+		testIterate(t, m)
+	}
+}
+
+// The hybrid database and the overlay pass the iterators of the database they wrap through
+func TestIterateWrappers(t *testing.T) {
+	h, err := hybridDB.NewLevelMapHybridDB(dbFilename, true)
+	if err != nil {
+		t.Fatal(err)
 	}
+	testIterate(t, h)
+	CleanupTest(t, h)
+
+	testIterate(t, databaseOverlay.NewOverlay(new(mapdb.MapDB)))
 }
 
 func testPutGetDelete(t *testing.T, m interfaces.IDatabase) {
@@ -317,6 +345,100 @@ func testGetAll(t *testing.T, m interfaces.IDatabase) {
 	}
 }
 
+// testIterate checks the iterators against a bucket of 300 records keyed by their 2 byte big
+// endian index
+func testIterate(t *testing.T, m interfaces.IDatabase) {
+	defer CleanupTest(t, m)
+
+	bucket := []byte("Iterate")
+	key := func(i int) []byte {
+		k := make([]byte, 2)
+		binary.BigEndian.PutUint16(k, uint16(i))
+		return k
+	}
+
+	var batch []interfaces.Record
+	for i := 0; i < 300; i++ {
+		batch = append(batch, interfaces.Record{Bucket: bucket, Key: key(i), Data: &primitives.ByteSlice{Bytes: key(i)}})
+	}
+	// a bucket sharing the name as a prefix must not be visited
+	batch = append(batch, interfaces.Record{Bucket: []byte("IterateMore"), Key: key(0), Data: &primitives.ByteSlice{Bytes: key(0)}})
+	if err := m.PutInBatch(batch); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]struct {
+		Options  interfaces.IterateOptions
+		From, To int // expected indexes, inclusive
+	}{
+		"all":            {interfaces.IterateOptions{}, 0, 299},
+		"reverse":        {interfaces.IterateOptions{Reverse: true}, 299, 0},
+		"prefix":         {interfaces.IterateOptions{Prefix: []byte{1}}, 256, 299},
+		"reverse prefix": {interfaces.IterateOptions{Prefix: []byte{0}, Reverse: true}, 255, 0},
+		"range":          {interfaces.IterateOptions{Start: key(15), End: key(32)}, 15, 31},
+		"reverse range":  {interfaces.IterateOptions{Start: key(15), End: key(32), Reverse: true}, 31, 15},
+		"prefix start":   {interfaces.IterateOptions{Prefix: []byte{1}, Start: key(290)}, 290, 299},
+		"prefix end":     {interfaces.IterateOptions{Prefix: []byte{0}, End: key(3)}, 0, 2},
+		"limit":          {interfaces.IterateOptions{Limit: 3}, 0, 2},
+		"reverse limit":  {interfaces.IterateOptions{Reverse: true, Limit: 260}, 299, 40},
+		"empty range":    {interfaces.IterateOptions{Start: key(20), End: key(20)}, -1, -1},
+	}
+
+	for name, c := range cases {
+		var expected [][]byte
+		if c.From >= 0 {
+			step := 1
+			if c.To < c.From {
+				step = -1
+			}
+			for i := c.From; i != c.To+step; i += step {
+				expected = append(expected, key(i))
+			}
+		}
+
+		it, err := m.Iterate(bucket, c.Options)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		var keys [][]byte
+		for it.Next() {
+			v, err := it.Value(new(primitives.ByteSlice))
+			if err != nil {
+				t.Errorf("%s: %v", name, err)
+			} else if !primitives.AreBytesEqual(v.(*primitives.ByteSlice).Bytes, it.Key()) {
+				t.Errorf("%s: value %x does not belong to key %x", name, v.(*primitives.ByteSlice).Bytes, it.Key())
+			}
+			keys = append(keys, it.Key())
+		}
+		if err := it.Error(); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+		it.Release()
+
+		if len(keys) != len(expected) {
+			t.Errorf("%s: expected %v keys, got %v", name, len(expected), len(keys))
+			continue
+		}
+		for i := range keys {
+			if !primitives.AreBytesEqual(keys[i], expected[i]) {
+				t.Errorf("%s: expected key %x at %v, got %x", name, expected[i], i, keys[i])
+				break
+			}
+		}
+	}
+
+	it, err := m.Iterate([]byte("IterateMissing"), interfaces.IterateOptions{})
+	if err != nil {
+		t.Error(err)
+	} else {
+		if it.Next() {
+			t.Errorf("Expected no records in a missing bucket")
+		}
+		it.Release()
+	}
+}
+
 func testNilRetreive(t *testing.T, m interfaces.IDatabase) {
 	o := databaseOverlay.NewOverlay(m)
 	//totalEntries := 10000
@@ -0,0 +1,154 @@
+package verify
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+)
+
+// Balances are the factoid and entry credit balances of every address after a height
+type Balances struct {
+	Height      uint32
+	Factoid     map[[32]byte]int64
+	EntryCredit map[[32]byte]int64
+}
+
+func NewBalances() *Balances {
+	b := new(Balances)
+	b.Factoid = map[[32]byte]int64{}
+	b.EntryCredit = map[[32]byte]int64{}
+	return b
+}
+
+// ReplayBalances reconstructs the balances from genesis up to and including the height, the
+// way the node processes the blocks: the factoid block first, then the entry credit block.
+// Transactions spending more than an address holds are returned as discrepancies.
+// The replay stops at the first height with missing blocks.
+func ReplayBalances(dbo *databaseOverlay.Overlay, height uint32) (*Balances, []Discrepancy, error) {
+	return replay(dbo, height, nil)
+}
+
+// replay reconstructs the balances up to the height, calling after with the balances of every
+// height along the way
+func replay(dbo *databaseOverlay.Overlay, height uint32, after func(b *Balances)) (*Balances, []Discrepancy, error) {
+	b := NewBalances()
+	discrepancies := []Discrepancy{}
+	for h := uint32(0); h <= height; h++ {
+		fblock, err := dbo.FetchFBlockByHeight(h)
+		if err != nil {
+			return nil, nil, err
+		}
+		ecblock, err := dbo.FetchECBlockByHeight(h)
+		if err != nil {
+			return nil, nil, err
+		}
+		if fblock == nil || ecblock == nil {
+			discrepancies = append(discrepancies, Discrepancy{Height: h, Kind: KindBalance, Error: "Balances can not be replayed past missing blocks"})
+			return b, discrepancies, nil
+		}
+
+		for _, tx := range fblock.GetTransactions() {
+			if err := b.applyTransaction(tx, fblock.GetExchRate()); err != nil {
+				discrepancies = append(discrepancies, Discrepancy{Height: h, Kind: KindTransaction, Hash: tx.GetSigHash().String(), Error: err.Error()})
+			}
+		}
+		for _, entry := range ecblock.GetBody().GetEntries() {
+			switch entry.ECID() {
+			case constants.ECIDChainCommit:
+				c := entry.(*entryCreditBlock.CommitChain)
+				b.EntryCredit[c.ECPubKey.Fixed()] -= int64(c.Credits)
+			case constants.ECIDEntryCommit:
+				c := entry.(*entryCreditBlock.CommitEntry)
+				b.EntryCredit[c.ECPubKey.Fixed()] -= int64(c.Credits)
+			}
+		}
+		b.Height = h
+		if after != nil {
+			after(b)
+		}
+	}
+	return b, discrepancies, nil
+}
+
+// applyTransaction moves the amounts of the transaction, if its inputs are covered
+func (b *Balances) applyTransaction(tx interfaces.ITransaction, factoshisPerEC uint64) error {
+	for _, input := range tx.GetInputs() {
+		if b.Factoid[input.GetAddress().Fixed()] < int64(input.GetAmount()) {
+			return fmt.Errorf("Not enough factoids (%d) at %v to cover the input (%d)",
+				b.Factoid[input.GetAddress().Fixed()], input.GetAddress(), input.GetAmount())
+		}
+	}
+	for _, input := range tx.GetInputs() {
+		b.Factoid[input.GetAddress().Fixed()] -= int64(input.GetAmount())
+	}
+	for _, output := range tx.GetOutputs() {
+		b.Factoid[output.GetAddress().Fixed()] += int64(output.GetAmount())
+	}
+	for _, ecOut := range tx.GetECOutputs() {
+		if factoshisPerEC == 0 {
+			return fmt.Errorf("Exchange rate is zero")
+		}
+		b.EntryCredit[ecOut.GetAddress().Fixed()] += int64(ecOut.GetAmount()) / int64(factoshisPerEC)
+	}
+	return nil
+}
+
+// Compare returns a discrepancy for every address whose balance differs from the expected one.
+// Addresses missing from either side have a balance of zero.
+func (b *Balances) Compare(expected *Balances) []Discrepancy {
+	discrepancies := []Discrepancy{}
+	compare := func(kind string, have, want map[[32]byte]int64) {
+		keys := map[[32]byte]bool{}
+		for k := range have {
+			keys[k] = true
+		}
+		for k := range want {
+			keys[k] = true
+		}
+		sorted := make([][32]byte, 0, len(keys))
+		for k := range keys {
+			sorted = append(sorted, k)
+		}
+		sort.Slice(sorted, func(i, j int) bool {
+			return bytes.Compare(sorted[i][:], sorted[j][:]) < 0
+		})
+		for _, k := range sorted {
+			if have[k] != want[k] {
+				discrepancies = append(discrepancies, Discrepancy{
+					Height: b.Height,
+					Kind:   KindBalance,
+					Hash:   primitives.NewHash(k[:]).String(),
+					Error:  fmt.Sprintf("%v balance %d from the blocks, %d in the SaveState", kind, have[k], want[k]),
+				})
+			}
+		}
+	}
+	compare("Factoid", b.Factoid, expected.Factoid)
+	compare("Entry credit", b.EntryCredit, expected.EntryCredit)
+	return discrepancies
+}
+
+// checkBalances replays the balances up to the head, and compares them with the SaveState at
+// its height
+func checkBalances(dbo *databaseOverlay.Overlay, head uint32, saveState *Balances) ([]Discrepancy, error) {
+	var compared []Discrepancy
+	_, discrepancies, err := replay(dbo, head, func(b *Balances) {
+		if saveState != nil && b.Height == saveState.Height {
+			compared = b.Compare(saveState)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if saveState != nil && saveState.Height > head {
+		compared = []Discrepancy{{Height: saveState.Height, Kind: KindBalance,
+			Error: fmt.Sprintf("The SaveState is above the head of the database %v", head)}}
+	}
+	return append(discrepancies, compared...), nil
+}
@@ -0,0 +1,420 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+// Package verify checks the integrity of every block and entry of a node's database, and
+// reports what does not add up.
+package verify
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/adminBlock"
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/directoryBlock"
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/factoid"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+)
+
+// The kinds of the discrepancies
+const (
+	KindDBlock      = "dblock"
+	KindABlock      = "ablock"
+	KindECBlock     = "ecblock"
+	KindFBlock      = "fblock"
+	KindEBlock      = "eblock"
+	KindEntry       = "entry"
+	KindTransaction = "transaction"
+	KindBalance     = "balance"
+)
+
+// A Discrepancy is one thing in the database that does not check out
+type Discrepancy struct {
+	Height uint32 `json:"height"`
+	Kind   string `json:"kind"`
+	// Hash of the block, entry or transaction, or the address of a balance
+	Hash  string `json:"hash,omitempty"`
+	Error string `json:"error"`
+}
+
+type Report struct {
+	Head          uint32 `json:"head"`
+	DBlocks       int    `json:"dblocks"`
+	EBlocks       int    `json:"eblocks"`
+	Entries       int    `json:"entries"`
+	PrunedEntries int    `json:"prunedentries"`
+	Transactions  int    `json:"transactions"`
+	// Height of the SaveState the balances were compared with, if any
+	BalancesHeight *uint32       `json:"balancesheight,omitempty"`
+	Discrepancies  []Discrepancy `json:"discrepancies"`
+	Duration       string        `json:"duration"`
+	OK             bool          `json:"ok"`
+}
+
+type Options struct {
+	// Workers verifying heights in parallel, the number of CPUs if 0
+	Workers int
+	// The balances reconstructed from genesis are compared with these, if set
+	SaveState *Balances
+	// Progress is called with the number of heights verified
+	Progress func(done uint32, total uint32)
+}
+
+// result is what the verification of one height found
+type result struct {
+	height        uint32
+	discrepancies []Discrepancy
+	eblocks       int
+	entries       int
+	pruned        int
+	transactions  int
+	err           error
+}
+
+func (r *result) add(kind string, hash interfaces.IHash, format string, args ...interface{}) {
+	d := Discrepancy{Height: r.height, Kind: kind, Error: fmt.Sprintf(format, args...)}
+	if hash != nil {
+		d.Hash = hash.String()
+	}
+	r.discrepancies = append(r.discrepancies, d)
+}
+
+// Run verifies every height of the database, from genesis to the head. Errors reading the
+// database stop the verification, everything else is reported as a discrepancy.
+func Run(dbo *databaseOverlay.Overlay, opts Options) (*Report, error) {
+	start := time.Now()
+	head, err := dbo.FetchDBlockHead()
+	if err != nil {
+		return nil, err
+	}
+	if head == nil {
+		return nil, fmt.Errorf("The database has no directory blocks")
+	}
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	r := new(Report)
+	r.Head = head.GetDatabaseHeight()
+	total := r.Head + 1
+
+	// The balances are replayed in order, next to the workers
+	var balanceDiscrepancies []Discrepancy
+	var balanceErr error
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		balanceDiscrepancies, balanceErr = checkBalances(dbo, r.Head, opts.SaveState)
+	}()
+
+	heights := make(chan uint32)
+	results := make(chan *result)
+	for i := 0; i < workers; i++ {
+		go func() {
+			for h := range heights {
+				results <- verifyHeight(dbo, h)
+			}
+		}()
+	}
+	go func() {
+		for h := uint32(0); h < total; h++ {
+			heights <- h
+		}
+		close(heights)
+	}()
+
+	for done := uint32(1); done <= total; done++ {
+		res := <-results
+		if res.err != nil && err == nil {
+			err = fmt.Errorf("Reading height %v: %v", res.height, res.err)
+		}
+		r.DBlocks++
+		r.EBlocks += res.eblocks
+		r.Entries += res.entries
+		r.PrunedEntries += res.pruned
+		r.Transactions += res.transactions
+		r.Discrepancies = append(r.Discrepancies, res.discrepancies...)
+		if opts.Progress != nil {
+			opts.Progress(done, total)
+		}
+	}
+	wg.Wait()
+	if err != nil {
+		return nil, err
+	}
+	if balanceErr != nil {
+		return nil, balanceErr
+	}
+	r.Discrepancies = append(r.Discrepancies, balanceDiscrepancies...)
+	if opts.SaveState != nil {
+		height := opts.SaveState.Height
+		r.BalancesHeight = &height
+	}
+
+	sort.SliceStable(r.Discrepancies, func(i, j int) bool {
+		return r.Discrepancies[i].Height < r.Discrepancies[j].Height
+	})
+	if r.Discrepancies == nil {
+		r.Discrepancies = []Discrepancy{}
+	}
+	r.OK = len(r.Discrepancies) == 0
+	r.Duration = time.Since(start).String()
+	return r, nil
+}
+
+// verifyHeight checks the directory block at the height, the blocks it references, and their
+// links to the blocks before them
+func verifyHeight(dbo *databaseOverlay.Overlay, h uint32) *result {
+	r := &result{height: h}
+	r.err = verifyBlocks(dbo, h, r)
+	return r
+}
+
+func verifyBlocks(dbo *databaseOverlay.Overlay, h uint32, r *result) error {
+	dblock, err := dbo.FetchDBlockByHeight(h)
+	if err != nil {
+		return err
+	}
+	if dblock == nil {
+		r.add(KindDBlock, nil, "Missing directory block")
+		return nil
+	}
+	key, err := dbo.FetchDBKeyMRByHeight(h)
+	if err != nil {
+		return err
+	}
+
+	// Computing the KeyMR rebuilds the BodyMR of the header, keep the one that was saved
+	stored := primitives.NewHash(dblock.GetHeader().GetBodyMR().Bytes())
+	keyMR := dblock.GetKeyMR()
+	if !stored.IsSameAs(dblock.GetHeader().GetBodyMR()) {
+		r.add(KindDBlock, key, "BodyMR %v does not match the entries %v", stored, dblock.GetHeader().GetBodyMR())
+	}
+	if !keyMR.IsSameAs(key) {
+		r.add(KindDBlock, key, "KeyMR %v does not match the key it is saved under", keyMR)
+	}
+	if dblock.GetHeader().GetDBHeight() != h {
+		r.add(KindDBlock, key, "Saved at height %v with height %v", h, dblock.GetHeader().GetDBHeight())
+	}
+
+	var prev interfaces.IDirectoryBlock
+	if h > 0 {
+		prev, err = dbo.FetchDBlockByHeight(h - 1)
+		if err != nil {
+			return err
+		}
+	}
+	// A missing previous block is reported at its own height
+	if h == 0 || prev != nil {
+		if err := directoryBlock.CheckBlockPairIntegrity(dblock, prev); err != nil {
+			r.add(KindDBlock, key, "%v", err)
+		}
+	}
+
+	entries := dblock.GetDBEntries()
+	if len(entries) < 3 ||
+		!entries[0].GetChainID().IsSameAs(primitives.NewHash(constants.ADMIN_CHAINID)) ||
+		!entries[1].GetChainID().IsSameAs(primitives.NewHash(constants.EC_CHAINID)) ||
+		!entries[2].GetChainID().IsSameAs(primitives.NewHash(constants.FACTOID_CHAINID)) {
+		r.add(KindDBlock, key, "Does not start with the admin, entry credit and factoid blocks")
+		return nil
+	}
+
+	if err := verifyABlock(dbo, h, entries[0].GetKeyMR(), r); err != nil {
+		return err
+	}
+	if err := verifyECBlock(dbo, h, entries[1].GetKeyMR(), r); err != nil {
+		return err
+	}
+	if err := verifyFBlock(dbo, h, entries[2].GetKeyMR(), r); err != nil {
+		return err
+	}
+	for _, dbEntry := range entries[3:] {
+		if err := verifyEBlock(dbo, h, dbEntry, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func verifyABlock(dbo *databaseOverlay.Overlay, h uint32, key interfaces.IHash, r *result) error {
+	ablock, err := dbo.FetchABlock(key)
+	if err != nil {
+		return err
+	}
+	if ablock == nil {
+		r.add(KindABlock, key, "Missing admin block")
+		return nil
+	}
+	if hash := ablock.DatabasePrimaryIndex(); !hash.IsSameAs(key) {
+		r.add(KindABlock, key, "Hash %v does not match the directory block", hash)
+	}
+
+	var prev interfaces.IAdminBlock
+	if h > 0 {
+		if prev, err = dbo.FetchABlockByHeight(h - 1); err != nil {
+			return err
+		}
+	}
+	if h == 0 || prev != nil {
+		if err := adminBlock.CheckBlockPairIntegrity(ablock, prev); err != nil {
+			r.add(KindABlock, key, "%v", err)
+		}
+	}
+	return nil
+}
+
+func verifyECBlock(dbo *databaseOverlay.Overlay, h uint32, key interfaces.IHash, r *result) error {
+	ecblock, err := dbo.FetchECBlock(key)
+	if err != nil {
+		return err
+	}
+	if ecblock == nil {
+		r.add(KindECBlock, key, "Missing entry credit block")
+		return nil
+	}
+	if hash := ecblock.DatabasePrimaryIndex(); !hash.IsSameAs(key) {
+		r.add(KindECBlock, key, "Hash %v does not match the directory block", hash)
+	}
+
+	var prev interfaces.IEntryCreditBlock
+	if h > 0 {
+		if prev, err = dbo.FetchECBlockByHeight(h - 1); err != nil {
+			return err
+		}
+	}
+	if h == 0 || prev != nil {
+		if err := entryCreditBlock.CheckBlockPairIntegrity(ecblock, prev); err != nil {
+			r.add(KindECBlock, key, "%v", err)
+		}
+	}
+	return nil
+}
+
+func verifyFBlock(dbo *databaseOverlay.Overlay, h uint32, key interfaces.IHash, r *result) error {
+	fblock, err := dbo.FetchFBlock(key)
+	if err != nil {
+		return err
+	}
+	if fblock == nil {
+		r.add(KindFBlock, key, "Missing factoid block")
+		return nil
+	}
+	if hash := fblock.DatabasePrimaryIndex(); !hash.IsSameAs(key) {
+		r.add(KindFBlock, key, "KeyMR %v does not match the directory block", hash)
+	}
+
+	var prev interfaces.IFBlock
+	if h > 0 {
+		if prev, err = dbo.FetchFBlockByHeight(h - 1); err != nil {
+			return err
+		}
+	}
+	if h == 0 || prev != nil {
+		if err := factoid.CheckBlockPairIntegrity(fblock, prev); err != nil {
+			r.add(KindFBlock, key, "%v", err)
+		}
+	}
+
+	for _, tx := range fblock.GetTransactions() {
+		r.transactions++
+		if err := tx.ValidateSignatures(); err != nil {
+			r.add(KindTransaction, tx.GetSigHash(), "%v", err)
+		}
+	}
+	return nil
+}
+
+func verifyEBlock(dbo *databaseOverlay.Overlay, h uint32, dbEntry interfaces.IDBEntry, r *result) error {
+	key := dbEntry.GetKeyMR()
+	eblock, err := dbo.FetchEBlock(key)
+	if err != nil {
+		return err
+	}
+	if eblock == nil {
+		r.add(KindEBlock, key, "Missing entry block")
+		return nil
+	}
+	r.eblocks++
+
+	keyMR, err := eblock.KeyMR()
+	if err != nil {
+		r.add(KindEBlock, key, "%v", err)
+	} else if !keyMR.IsSameAs(key) {
+		r.add(KindEBlock, key, "KeyMR %v does not match the directory block", keyMR)
+	}
+	header := eblock.GetHeader()
+	if !header.GetChainID().IsSameAs(dbEntry.GetChainID()) {
+		r.add(KindEBlock, key, "Chain %v does not match the directory block chain %v", header.GetChainID(), dbEntry.GetChainID())
+	}
+	if header.GetDBHeight() != h {
+		r.add(KindEBlock, key, "Has height %v", header.GetDBHeight())
+	}
+
+	if header.GetEBSequence() == 0 {
+		if !header.GetPrevKeyMR().IsZero() {
+			r.add(KindEBlock, key, "First block of the chain has a PrevKeyMR")
+		}
+	} else {
+		prev, err := dbo.FetchEBlock(header.GetPrevKeyMR())
+		if err != nil {
+			return err
+		}
+		if prev == nil {
+			r.add(KindEBlock, key, "Missing previous entry block %v", header.GetPrevKeyMR())
+		} else {
+			if prev.GetHeader().GetEBSequence()+1 != header.GetEBSequence() {
+				r.add(KindEBlock, key, "Sequence %v follows sequence %v", header.GetEBSequence(), prev.GetHeader().GetEBSequence())
+			}
+			if !prev.GetHeader().GetChainID().IsSameAs(header.GetChainID()) {
+				r.add(KindEBlock, key, "Previous entry block is in chain %v", prev.GetHeader().GetChainID())
+			}
+			if prev.GetHeader().GetDBHeight() >= h {
+				r.add(KindEBlock, key, "Previous entry block is at height %v", prev.GetHeader().GetDBHeight())
+			}
+			if hash, err := prev.Hash(); err != nil || !hash.IsSameAs(header.GetPrevFullHash()) {
+				r.add(KindEBlock, key, "PrevFullHash does not match the previous entry block")
+			}
+		}
+	}
+
+	for _, hash := range eblock.GetEntryHashes() {
+		if hash.IsMinuteMarker() {
+			continue
+		}
+		r.entries++
+		entry, err := dbo.FetchEntry(hash)
+		if err != nil {
+			return err
+		}
+		if entry == nil {
+			pruned, err := dbo.IsEntryPruned(hash)
+			if err != nil {
+				return err
+			}
+			if pruned {
+				r.pruned++
+			} else {
+				r.add(KindEntry, hash, "Missing entry")
+			}
+			continue
+		}
+		if !entry.GetHash().IsSameAs(hash) {
+			r.add(KindEntry, hash, "Content hashes to %v", entry.GetHash())
+		}
+		if !entry.GetChainID().IsSameAs(header.GetChainID()) {
+			r.add(KindEntry, hash, "Chain %v does not match the entry block chain %v", entry.GetChainID(), header.GetChainID())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package verify_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/entryBlock"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+	. "github.com/FactomProject/factomd/database/verify"
+	"github.com/FactomProject/factomd/testHelper"
+	"github.com/stretchr/testify/assert"
+)
+
+// createTestBlocks returns the test block sets with the entry blocks numbered in their chains,
+// and the directory blocks linked to them
+func createTestBlocks() []*testHelper.BlockSet {
+	blocks := testHelper.CreateFullTestBlockSet()
+	for i := 1; i < len(blocks); i++ {
+		prev, set := blocks[i-1], blocks[i]
+		for j, pair := range [][]*entryBlock.EBlock{{prev.EBlock, set.EBlock}, {prev.AnchorEBlock, set.AnchorEBlock}} {
+			keyMR, _ := pair[0].KeyMR()
+			hash, _ := pair[0].Hash()
+			pair[1].Header.SetEBSequence(pair[0].Header.GetEBSequence() + 1)
+			pair[1].Header.SetPrevKeyMR(keyMR)
+			pair[1].Header.SetPrevFullHash(hash)
+			keyMR, _ = pair[1].KeyMR()
+			set.DBlock.SetEntryHash(keyMR, pair[1].GetChainID(), 3+j)
+		}
+		set.DBlock.GetHeader().SetPrevKeyMR(prev.DBlock.GetKeyMR())
+		set.DBlock.GetHeader().SetPrevFullHash(prev.DBlock.GetFullHash())
+		set.DBlock.GetKeyMR()
+	}
+	return blocks
+}
+
+// createTestDatabase saves the block sets the way the node does
+func createTestDatabase(t *testing.T, blocks []*testHelper.BlockSet) *databaseOverlay.Overlay {
+	dbo := testHelper.CreateEmptyTestDatabaseOverlay()
+	assert.Nil(t, testHelper.SaveBlockSets(dbo, blocks))
+	return dbo
+}
+
+func TestRun(t *testing.T) {
+	dbo := createTestDatabase(t, createTestBlocks())
+	defer dbo.Close()
+
+	r, err := Run(dbo, Options{Workers: 4})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.True(t, r.OK, "%v", r.Discrepancies)
+	assert.Empty(t, r.Discrepancies)
+	assert.EqualValues(t, testHelper.BlockCount-1, r.Head)
+	assert.Equal(t, testHelper.BlockCount, r.DBlocks)
+	assert.NotZero(t, r.EBlocks)
+	assert.NotZero(t, r.Entries)
+	assert.NotZero(t, r.Transactions)
+	assert.Nil(t, r.BalancesHeight)
+}
+
+func TestRunDiscrepancies(t *testing.T) {
+	blocks := createTestBlocks()
+	dbo := createTestDatabase(t, blocks)
+	defer dbo.Close()
+
+	// the content of an entry replaced by another one of the chain
+	entry := blocks[5].Entries[0]
+	assert.Nil(t, dbo.DB.Put(entry.GetChainID().Bytes(), entry.GetHash().Bytes(), blocks[5].Entries[1]))
+	// an entry block replaced by the next one of the chain
+	keyMR, err := blocks[7].EBlock.KeyMR()
+	assert.Nil(t, err)
+	assert.Nil(t, dbo.DB.Put(databaseOverlay.ENTRYBLOCK, keyMR.Bytes(), blocks[8].EBlock))
+	// a factoid block gone
+	assert.Nil(t, dbo.DB.Delete(databaseOverlay.FACTOIDBLOCK, blocks[9].FBlock.DatabasePrimaryIndex().Bytes()))
+
+	r, err := Run(dbo, Options{})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.False(t, r.OK)
+
+	found := map[string]bool{}
+	for _, d := range r.Discrepancies {
+		switch {
+		case d.Height == 5 && d.Kind == KindEntry && d.Hash == entry.GetHash().String():
+			found["entry"] = true
+		case d.Height == 7 && d.Kind == KindEBlock && d.Hash == keyMR.String():
+			found["eblock"] = true
+		case d.Height == 9 && d.Kind == KindFBlock:
+			found["fblock"] = true
+		case d.Height == 9 && d.Kind == KindBalance:
+			found["balance"] = true
+		}
+	}
+	assert.True(t, found["entry"], "%v", r.Discrepancies)
+	assert.True(t, found["eblock"], "%v", r.Discrepancies)
+	assert.True(t, found["fblock"], "%v", r.Discrepancies)
+	assert.True(t, found["balance"], "%v", r.Discrepancies)
+
+	// nothing outside the tampered blocks, and the blocks that link to them, is reported
+	for _, d := range r.Discrepancies {
+		assert.True(t, d.Height >= 5 && d.Height <= 10, "%v", d)
+	}
+}
+
+func TestRunPrunedEntries(t *testing.T) {
+	blocks := createTestBlocks()
+	dbo := createTestDatabase(t, blocks)
+	defer dbo.Close()
+
+	assert.Nil(t, dbo.PruneEntry(blocks[3].Entries[0].GetHash()))
+	assert.Nil(t, dbo.SaveEntryPruneHeight(4))
+
+	r, err := Run(dbo, Options{})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.True(t, r.OK, "%v", r.Discrepancies)
+	assert.Equal(t, 1, r.PrunedEntries)
+}
+
+func TestBalances(t *testing.T) {
+	s := testHelper.CreateAndPopulateTestState()
+	dbo := s.DB.(*databaseOverlay.Overlay)
+	head := s.GetHighestSavedBlk()
+
+	// the balances replayed from the blocks are the ones the node got processing them
+	b, discrepancies, err := ReplayBalances(dbo, head)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Empty(t, discrepancies)
+	saveState := NewBalances()
+	saveState.Height = head
+	for k, v := range s.FactoidBalancesP {
+		saveState.Factoid[k] = v
+	}
+	for k, v := range s.ECBalancesP {
+		saveState.EntryCredit[k] = v
+	}
+	assert.NotEmpty(t, saveState.Factoid)
+	assert.Empty(t, b.Compare(saveState))
+}
+
+func TestRunSaveState(t *testing.T) {
+	dbo := createTestDatabase(t, createTestBlocks())
+	defer dbo.Close()
+
+	head := uint32(testHelper.BlockCount - 2)
+	saveState, _, err := ReplayBalances(dbo, head)
+	if !assert.Nil(t, err) {
+		return
+	}
+	r, err := Run(dbo, Options{SaveState: saveState})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.True(t, r.OK, "%v", r.Discrepancies)
+	if assert.NotNil(t, r.BalancesHeight) {
+		assert.Equal(t, head, *r.BalancesHeight)
+	}
+
+	for k := range saveState.Factoid {
+		saveState.Factoid[k]++
+		break
+	}
+	r, err = Run(dbo, Options{SaveState: saveState})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.False(t, r.OK)
+	if assert.Len(t, r.Discrepancies, 1) {
+		assert.Equal(t, KindBalance, r.Discrepancies[0].Kind)
+		assert.Equal(t, head, r.Discrepancies[0].Height)
+	}
+}
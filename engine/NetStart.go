@@ -127,6 +127,11 @@ func NetStart(s *state.State, p *FactomParams) {
 	} else {
 		p.ControlPanelPortOverride = s.ControlPanelPort
 	}
+	if 999 < p.DebugApiPortOverride { // The command line flag exists and seems reasonable.
+		s.DebugApiPort = p.DebugApiPortOverride
+	} else {
+		p.DebugApiPortOverride = s.DebugApiPort
+	}
 
 	if p.BlkTime > 0 {
 		s.DirectoryBlockInSeconds = p.BlkTime
@@ -162,6 +167,10 @@ func NetStart(s *state.State, p *FactomParams) {
 		s.FactomdTLSEnable = true
 	}
 
+	if p.ApiReadOnly {
+		s.ApiReadOnly = true
+	}
+
 	if p.FactomdLocations != "" {
 		if len(s.FactomdLocations) > 0 {
 			s.FactomdLocations += ","
@@ -309,6 +318,7 @@ func NetStart(s *state.State, p *FactomParams) {
 	os.Stderr.WriteString(fmt.Sprintf("%20s %v\n", "selfaddr", s.FactomdLocations))
 	os.Stderr.WriteString(fmt.Sprintf("%20s \"%s\"\n", "rpcuser", s.RpcUser))
 	os.Stderr.WriteString(fmt.Sprintf("%20s \"%s\"\n", "corsdomains", s.CorsDomains))
+	os.Stderr.WriteString(fmt.Sprintf("%20s %v\n", "api read only", s.ApiReadOnly))
 	os.Stderr.WriteString(fmt.Sprintf("%20s %d\n", "Start 2nd Sync at ht", s.EntryBlockDBHeightComplete))
 
 	os.Stderr.WriteString(fmt.Sprintf("%20s %d\n", "faultTimeout", elections.FaultTimeout))
@@ -321,6 +331,7 @@ func NetStart(s *state.State, p *FactomParams) {
 	os.Stderr.WriteString(fmt.Sprintf("%20s \"%d\"\n", "TCP port", s.PortNumber))
 	os.Stderr.WriteString(fmt.Sprintf("%20s \"%s\"\n", "pprof port", logPort))
 	os.Stderr.WriteString(fmt.Sprintf("%20s \"%d\"\n", "Control Panel port", s.ControlPanelPort))
+	os.Stderr.WriteString(fmt.Sprintf("%20s \"%d\"\n", "Debug API port", s.DebugApiPort))
 
 	//************************************************
 	// Actually setup the Network
@@ -438,7 +449,7 @@ func NetStart(s *state.State, p *FactomParams) {
 
 	// Start live feed service
 	config := s.Cfg.(*util.FactomdConfig)
-	if config.LiveFeedAPI.EnableLiveFeedAPI || p.EnableLiveFeedAPI {
+	if config.LiveFeedAPI.EnableLiveFeedAPI || p.EnableLiveFeedAPI || len(config.LiveFeedReceiver) > 0 {
 		s.EventService.ConfigService(s, config, p)
 	}
 
@@ -572,6 +583,20 @@ func NetStart(s *state.State, p *FactomParams) {
 	} else {
 		fnodes[0].State.SetUseTorrent(false)
 	}
+	// Sync from a block archive if one was given. Only does so for the first node
+	if p.ImportArchive != "" {
+		go fnodes[0].State.ImportArchive(p.ImportArchive)
+	}
+	// Backfill a live feed receiver from the database. Only does so for the first node
+	if p.EventReplayStart >= 0 {
+		end := uint32(math.MaxUint32)
+		if p.EventReplayEnd >= 0 {
+			end = uint32(p.EventReplayEnd)
+		}
+		if err := fnodes[0].State.StartLiveFeedReplay(p.EventReplayReceiver, uint32(p.EventReplayStart), end, p.EventReplayRate); err != nil {
+			fmt.Fprintf(os.Stderr, "Live feed replay not started: %v\n", err)
+		}
+	}
 
 	if p.Journal != "" {
 		go LoadJournal(s, p.Journal)
@@ -678,6 +703,9 @@ func startServer(i int, fnode *FactomNode, load bool) {
 	fnode.State.EntrySync = entrySync
 	go fnode.State.EntrySync.SyncHeight()
 	go fnode.State.WriteEntries()
+	go fnode.State.IndexAddressTransactions()
+	go fnode.State.IndexEntryExtIDs()
+	go fnode.State.PruneEntries()
 
 	go Timer(fnode.State)
 	go elections.Run(fnode.State)
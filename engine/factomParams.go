@@ -45,7 +45,7 @@ func init() {
 	flag.BoolVar(&p.Journaling, "journaling", false, "Write a journal of all messages received. Default is off.")
 	flag.BoolVar(&p.Follower, "follower", false, "If true, force node to be a follower.  Only used when replaying a journal.")
 	flag.BoolVar(&p.Leader, "leader", true, "If true, force node to be a leader.  Only used when replaying a journal.")
-	flag.StringVar(&p.Db, "db", "", "Override the Database in the Config file and use this Database implementation. Options Map, LDB, or Bolt")
+	flag.StringVar(&p.Db, "db", "", "Override the Database in the Config file and use this Database implementation. Options Map, LDB, Bolt or Badger")
 	flag.StringVar(&p.CloneDB, "clonedb", "", "Override the main node and use this database for the clones in a Network.")
 	flag.StringVar(&p.NetworkName, "network", "", "Network to join: MAIN, TEST or LOCAL")
 	flag.StringVar(&p.Peers, "peers", "", "Array of peer addresses. ")
@@ -63,11 +63,13 @@ func init() {
 	flag.StringVar(&p.RpcPassword, "rpcpass", "", "Password to protect factomd local API. Ignored if rpcuser is blank")
 	flag.BoolVar(&p.FactomdTLS, "tls", false, "Set to true to require encrypted connections to factomd API and Control Panel") //to get tls, run as "factomd -tls=true"
 	flag.StringVar(&p.FactomdLocations, "selfaddr", "", "comma separated IPAddresses and DNS names of this factomd to use when creating a cert file")
+	flag.BoolVar(&p.ApiReadOnly, "apireadonly", false, "If true, the API rejects all write and debug calls")
 	flag.IntVar(&p.MemProfileRate, "mpr", 512*1024, "Set the Memory Profile Rate to update profiling per X bytes allocated. Default 512K, set to 1 to profile everything, 0 to disable.")
 	flag.BoolVar(&p.ExposeProfiling, "exposeprofiler", false, "Setting this exposes the profiling port to outside localhost.")
 	flag.StringVar(&p.LogPort, "logPort", "6060", "Port for pprof logging")
 	flag.IntVar(&p.PortOverride, "port", 0, "Port where we serve WSAPI;  default 8088")
 	flag.IntVar(&p.ControlPanelPortOverride, "controlpanelport", 0, "Port for control panel webserver;  Default 8090")
+	flag.IntVar(&p.DebugApiPortOverride, "debugapiport", 0, "Port where we serve the debug API, instead of the WSAPI port")
 	flag.IntVar(&p.NetworkPortOverride, "networkport", 0, "Port for p2p network; default 8110")
 	flag.BoolVar(&p.Fast, "fast", true, "If true, Factomd will fast-boot from a file.")
 	flag.IntVar(&p.FastSaveRate, "fastsaverate", 1000, "Save a fastboot file every so many blocks. Should be > 1000 for live systems.")
@@ -80,6 +82,8 @@ func init() {
 	// 	Torrent Plugin
 	flag.BoolVar(&p.TorManage, "tormanage", false, "Use torrent dbstate manager. Must have plugin binary installed and in $PATH")
 	flag.BoolVar(&p.TorUpload, "torupload", false, "Be a torrent uploader")
+	// Block archive
+	flag.StringVar(&p.ImportArchive, "importarchive", "", "Sync from a block archive file written by the BlockArchive utility, checking the signatures of each block")
 	// Logstash connection (if used)
 	flag.BoolVar(&p.UseLogstash, "logstash", false, "If true, use Logstash")
 	flag.StringVar(&p.LogstashURL, "logurl", "localhost:8345", "Endpoint URL for Logstash")
@@ -100,8 +104,13 @@ func init() {
 	flag.IntVar(&p.EventSenderPort, "eventsenderport", 0, "Port for the events sender (client)")
 	flag.StringVar(&p.EventFormat, "eventformat", "", "Event output format for the events receiver, protobuf|json; default protobuf")
 	flag.BoolVar(&p.EventSendStateChange, "eventsendstatechange", false, "Send only StateChange events when the state of an entity changes instead of the full entity; default false")
+	flag.BoolVar(&p.EventSendBalanceChanges, "eventsendbalancechanges", false, "Send BalanceChange events for the addresses of the factoid transactions in a block; default false")
 	flag.StringVar(&p.EventBroadcastContent, "eventbroadcastcontent", "", "Settings for including content in the event messages always|once|never; default once")
 	flag.BoolVar(&p.EventReplayDuringStartup, "eventreplayduringstartup", false, "Replay events since the last save state during startup; default false")
+	flag.StringVar(&p.EventReplayReceiver, "eventreplayreceiver", "", "Name of the live feed receiver to replay the blocks of -eventreplaystart to; default the [LiveFeedAPI] receiver")
+	flag.IntVar(&p.EventReplayStart, "eventreplaystart", -1, "Replay the directory blocks from this height out of the database to a live feed receiver, alongside the live events")
+	flag.IntVar(&p.EventReplayEnd, "eventreplayend", -1, "Last height to replay with -eventreplaystart; default the highest saved block")
+	flag.IntVar(&p.EventReplayRate, "eventreplayrate", 0, "Directory blocks per second to replay with -eventreplaystart; default 10")
 	flag.BoolVar(&p.PersistentReconnect, "persistentreconnect", false, "Persistently try to reconnect with LiveFeed listener(s)")
 
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/FactomProject/factomd/common/globals"
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/events/eventconfig"
 	"github.com/FactomProject/factomd/events/eventinput"
 	"github.com/FactomProject/factomd/events/eventmessages/generated/eventmessages"
 	"github.com/FactomProject/factomd/events/eventservices"
@@ -15,12 +16,15 @@ import (
 
 type EventService interface {
 	ConfigService(state StateEventServices, config *util.FactomdConfig, factomParams *globals.FactomParams)
-	ConfigSender(state StateEventServices, sender eventservices.EventSender)
+	ConfigSender(state StateEventServices, senders ...eventservices.EventSender)
 	EmitRegistrationEvent(msg interfaces.IMsg)
 	EmitStateChangeEvent(msg interfaces.IMsg, entityState eventmessages.EntityState)
 	EmitDirectoryBlockCommitEvent(dbState interfaces.IDBState)
 	EmitDirectoryBlockAnchorEvent(dirBlockInfo interfaces.IDirBlockInfo)
 	EmitReplayDirectoryBlockCommit(msg interfaces.IMsg)
+	EmitHistoryDirectoryBlockCommit(receiver string, msg interfaces.IMsg) error
+	HasReceiver(receiver string) bool
+	EmitFactoidTransactionEvent(transaction interfaces.ITransaction, entityState eventmessages.EntityState, blockHeight uint32, minuteNumber int)
 	EmitProcessListEventNewBlock(newBlockHeight uint32)
 	EmitProcessListEventNewMinute(newMinute int, blockHeight uint32)
 	EmitNodeInfoMessage(messageCode eventmessages.NodeMessageCode, message string)
@@ -29,8 +33,8 @@ type EventService interface {
 }
 
 type eventEmitter struct {
-	parentState StateEventServices
-	eventSender eventservices.EventSender
+	parentState  StateEventServices
+	eventSenders []eventservices.EventSender
 }
 
 func NewEventService() EventService {
@@ -39,50 +43,82 @@ func NewEventService() EventService {
 
 func (eventEmitter *eventEmitter) ConfigService(state StateEventServices, config *util.FactomdConfig, factomParams *globals.FactomParams) {
 	eventEmitter.parentState = state
-	eventEmitter.eventSender = eventservices.NewEventSender(config, factomParams)
+	eventEmitter.eventSenders = eventservices.NewEventSenders(config, factomParams)
 }
 
-func (eventEmitter *eventEmitter) ConfigSender(state StateEventServices, eventSender eventservices.EventSender) {
+func (eventEmitter *eventEmitter) ConfigSender(state StateEventServices, eventSenders ...eventservices.EventSender) {
 	eventEmitter.parentState = state
-	eventEmitter.eventSender = eventSender
+	eventEmitter.eventSenders = eventSenders
+}
+
+// mappingOptions are the settings of a receiver the mapping of an event depends on
+type mappingOptions struct {
+	broadcastContent      eventconfig.BroadcastContent
+	sendStateChangeEvents bool
 }
 
 func (eventEmitter *eventEmitter) Send(event eventinput.EventInput) error {
+	return eventEmitter.sendTo(eventEmitter.eventSenders, event, false)
+}
+
+// sendTo maps the event and queues it for the senders. A requested event is sent whether or not
+// the node is done booting, and waits for room in a full queue instead of being dropped
+func (eventEmitter *eventEmitter) sendTo(eventSenders []eventservices.EventSender, event eventinput.EventInput, requested bool) error {
 	if eventEmitter.parentState.GetRunState() > runstate.Running { // Stop queuing messages to the events channel when shutting down
 		return nil
 	}
 
-	// Only send info messages when EventReplayDuringStartup is disabled
-	if !eventEmitter.eventSender.ReplayDuringStartup() && !eventEmitter.parentState.IsRunLeader() {
-		switch event.(type) {
-		case *eventinput.ProcessListEvent:
-		case *eventinput.NodeMessageEvent:
-		default:
-			return nil
+	// The event is mapped once for the receivers with the same settings
+	mapped := make(map[mappingOptions]*eventmessages.FactomEvent)
+	for _, eventSender := range eventSenders {
+		// Only send info messages when EventReplayDuringStartup is disabled
+		if !requested && !eventSender.ReplayDuringStartup() && !eventEmitter.parentState.IsRunLeader() {
+			switch event.(type) {
+			case *eventinput.ProcessListEvent:
+			case *eventinput.NodeMessageEvent:
+			default:
+				continue
+			}
 		}
-	}
 
-	broadcastContent := eventEmitter.eventSender.GetBroadcastContent()
-	sendStateChangeEvents := eventEmitter.eventSender.IsSendStateChangeEvents()
-	factomEvent, err := eventservices.MapToFactomEvent(event, broadcastContent, sendStateChangeEvents)
-	if err != nil {
-		return fmt.Errorf("failed to map to factom event: %v\n", err)
-	}
-	if factomEvent == nil {
-		return nil
-	}
+		if _, ok := event.(*eventinput.BalanceChangeEvent); ok && !eventSender.IsSendBalanceChanges() {
+			continue
+		}
+
+		options := mappingOptions{eventSender.GetBroadcastContent(), eventSender.IsSendStateChangeEvents()}
+		factomEvent, ok := mapped[options]
+		if !ok {
+			var err error
+			factomEvent, err = eventservices.MapToFactomEvent(event, options.broadcastContent, options.sendStateChangeEvents)
+			if err != nil {
+				return fmt.Errorf("failed to map to factom event: %v\n", err)
+			}
+			if factomEvent != nil {
+				factomEvent.IdentityChainID = eventEmitter.parentState.GetIdentityChainID().Bytes()
+			}
+			mapped[options] = factomEvent
+		}
 
-	factomEvent.IdentityChainID = eventEmitter.parentState.GetIdentityChainID().Bytes()
-	select {
-	case eventEmitter.eventSender.GetEventQueue() <- factomEvent:
-	default:
-		eventEmitter.eventSender.IncreaseDroppedFromQueueCounter()
+		factomEvent = eventSender.GetFilter().Apply(factomEvent)
+		if factomEvent == nil {
+			continue
+		}
+		factomEvent = eventSender.Stamp(factomEvent)
+		if requested {
+			eventSender.GetEventQueue() <- factomEvent
+			continue
+		}
+		select {
+		case eventSender.GetEventQueue() <- factomEvent:
+		default:
+			eventSender.IncreaseDroppedFromQueueCounter()
+		}
 	}
 	return nil
 }
 
 func (eventEmitter *eventEmitter) EmitRegistrationEvent(msg interfaces.IMsg) {
-	if eventEmitter.eventSender != nil {
+	if len(eventEmitter.eventSenders) > 0 {
 		switch msg.(type) { // Do not fill the channel with message we don't need (like EOM's)
 		case *messages.CommitChainMsg, *messages.CommitEntryMsg, *messages.RevealEntryMsg:
 			event := eventinput.NewRegistrationEvent(eventEmitter.GetStreamSource(), msg)
@@ -92,7 +128,7 @@ func (eventEmitter *eventEmitter) EmitRegistrationEvent(msg interfaces.IMsg) {
 }
 
 func (eventEmitter *eventEmitter) EmitStateChangeEvent(msg interfaces.IMsg, entityState eventmessages.EntityState) {
-	if eventEmitter.eventSender != nil {
+	if len(eventEmitter.eventSenders) > 0 {
 		switch msg.(type) {
 		case *messages.CommitChainMsg, *messages.CommitEntryMsg, *messages.RevealEntryMsg, *messages.DBStateMsg:
 			event := eventinput.NewStateChangeEvent(eventEmitter.GetStreamSource(), entityState, msg)
@@ -102,56 +138,132 @@ func (eventEmitter *eventEmitter) EmitStateChangeEvent(msg interfaces.IMsg, enti
 }
 
 func (eventEmitter *eventEmitter) EmitDirectoryBlockCommitEvent(dbState interfaces.IDBState) {
-	if eventEmitter.eventSender != nil {
+	if len(eventEmitter.eventSenders) > 0 {
 		event := eventinput.NewDirectoryBlockEvent(eventEmitter.GetStreamSource(), dbState)
 		eventEmitter.Send(event)
+		eventEmitter.emitFactoidBlockEvents(eventEmitter.GetStreamSource(), dbState.GetFactoidBlock())
 	}
 }
 
 func (eventEmitter *eventEmitter) EmitDirectoryBlockAnchorEvent(dirBlockInfo interfaces.IDirBlockInfo) {
-	if eventEmitter.eventSender != nil {
+	if len(eventEmitter.eventSenders) > 0 {
 		event := eventinput.NewAnchorEvent(eventEmitter.GetStreamSource(), dirBlockInfo)
 		eventEmitter.Send(event)
 	}
 }
 
 func (eventEmitter *eventEmitter) EmitReplayDirectoryBlockCommit(msg interfaces.IMsg) {
-	if eventEmitter.eventSender != nil {
+	if len(eventEmitter.eventSenders) > 0 {
 		event := eventinput.NewReplayDirectoryBlockEvent(eventmessages.EventSource_REPLAY_BOOT, msg)
 		eventEmitter.Send(event)
+		if dbStateMsg, ok := msg.(*messages.DBStateMsg); ok {
+			eventEmitter.emitFactoidBlockEvents(eventmessages.EventSource_REPLAY_BOOT, dbStateMsg.FactoidBlock)
+		}
+	}
+}
+
+// EmitHistoryDirectoryBlockCommit sends a directory block from the database, and the factoid
+// events derived from it, to the one receiver that asked for them
+func (eventEmitter *eventEmitter) EmitHistoryDirectoryBlockCommit(receiver string, msg interfaces.IMsg) error {
+	eventSender := eventEmitter.getEventSender(receiver)
+	if eventSender == nil {
+		return fmt.Errorf("no live feed receiver named %q", receiver)
+	}
+	eventSenders := []eventservices.EventSender{eventSender}
+
+	event := eventinput.NewReplayDirectoryBlockEvent(eventmessages.EventSource_REPLAY_HISTORY, msg)
+	if err := eventEmitter.sendTo(eventSenders, event, true); err != nil {
+		return err
+	}
+	if dbStateMsg, ok := msg.(*messages.DBStateMsg); ok {
+		return eventEmitter.sendFactoidBlockEvents(eventSenders, eventmessages.EventSource_REPLAY_HISTORY, dbStateMsg.FactoidBlock, true)
 	}
+	return nil
+}
+
+// HasReceiver tells whether there is a receiver with the name, the [LiveFeedAPI] receiver has none
+func (eventEmitter *eventEmitter) HasReceiver(receiver string) bool {
+	return eventEmitter.getEventSender(receiver) != nil
+}
+
+func (eventEmitter *eventEmitter) getEventSender(receiver string) eventservices.EventSender {
+	for _, eventSender := range eventEmitter.eventSenders {
+		if eventSender.GetName() == receiver {
+			return eventSender
+		}
+	}
+	return nil
+}
+
+func (eventEmitter *eventEmitter) EmitFactoidTransactionEvent(transaction interfaces.ITransaction, entityState eventmessages.EntityState, blockHeight uint32, minuteNumber int) {
+	if len(eventEmitter.eventSenders) > 0 {
+		event := eventinput.NewFactoidTransactionEvent(eventEmitter.GetStreamSource(), entityState, transaction, blockHeight, minuteNumber)
+		eventEmitter.Send(event)
+	}
+}
+
+// emitFactoidBlockEvents sends the transactions of the factoid block as committed, followed by
+// the balance changes of each transaction if a receiver wants them
+func (eventEmitter *eventEmitter) emitFactoidBlockEvents(streamSource eventmessages.EventSource, block interfaces.IFBlock) {
+	eventEmitter.sendFactoidBlockEvents(eventEmitter.eventSenders, streamSource, block, false)
+}
+
+func (eventEmitter *eventEmitter) sendFactoidBlockEvents(eventSenders []eventservices.EventSender, streamSource eventmessages.EventSource, block interfaces.IFBlock, requested bool) error {
+	if block == nil {
+		return nil
+	}
+	sendBalanceChanges := false
+	for _, eventSender := range eventSenders {
+		sendBalanceChanges = sendBalanceChanges || eventSender.IsSendBalanceChanges()
+	}
+
+	for i, transaction := range block.GetTransactions() {
+		minute := eventservices.TransactionMinute(block.GetEndOfPeriod(), i)
+		event := eventinput.NewFactoidTransactionEvent(streamSource, eventmessages.EntityState_COMMITTED_TO_DIRECTORY_BLOCK, transaction, block.GetDBHeight(), minute)
+		if err := eventEmitter.sendTo(eventSenders, event, requested); err != nil {
+			return err
+		}
+		if sendBalanceChanges {
+			for _, event := range eventinput.NewBalanceChangeEvents(streamSource, transaction, block.GetDBHeight(), block.GetExchRate()) {
+				if err := eventEmitter.sendTo(eventSenders, event, requested); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
 }
 
 func (eventEmitter *eventEmitter) EmitProcessListEventNewBlock(newBlockHeight uint32) {
-	if eventEmitter.eventSender != nil {
+	if len(eventEmitter.eventSenders) > 0 {
 		event := eventinput.ProcessListEventNewBlock(eventEmitter.GetStreamSource(), newBlockHeight)
 		eventEmitter.Send(event)
 	}
 }
 
 func (eventEmitter *eventEmitter) EmitProcessListEventNewMinute(newMinute int, blockHeight uint32) {
-	if eventEmitter.eventSender != nil {
+	if len(eventEmitter.eventSenders) > 0 {
 		event := eventinput.ProcessListEventNewMinute(eventEmitter.GetStreamSource(), newMinute, blockHeight)
 		eventEmitter.Send(event)
 	}
 }
 
 func (eventEmitter *eventEmitter) EmitNodeInfoMessage(messageCode eventmessages.NodeMessageCode, message string) {
-	if eventEmitter.eventSender != nil {
+	if len(eventEmitter.eventSenders) > 0 {
 		event := eventinput.NodeInfoMessageF(messageCode, message)
 		eventEmitter.Send(event)
 	}
 }
 
 func (eventEmitter *eventEmitter) EmitNodeInfoMessageF(messageCode eventmessages.NodeMessageCode, format string, values ...interface{}) {
-	if eventEmitter.eventSender != nil {
+	if len(eventEmitter.eventSenders) > 0 {
 		event := eventinput.NodeInfoMessageF(messageCode, format, values...)
 		eventEmitter.Send(event)
 	}
 }
 
 func (eventEmitter *eventEmitter) EmitNodeErrorMessage(messageCode eventmessages.NodeMessageCode, message string, values interface{}) {
-	if eventEmitter.eventSender != nil {
+	if len(eventEmitter.eventSenders) > 0 {
 		event := eventinput.NodeErrorMessage(messageCode, message, values)
 		eventEmitter.Send(event)
 	}
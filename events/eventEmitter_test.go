@@ -9,6 +9,7 @@ import (
 	"github.com/FactomProject/factomd/events/eventconfig"
 	"github.com/FactomProject/factomd/events/eventinput"
 	"github.com/FactomProject/factomd/events/eventmessages/generated/eventmessages"
+	"github.com/FactomProject/factomd/events/eventservices"
 	"github.com/prometheus/client_golang/prometheus"
 	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/assert"
@@ -25,10 +26,10 @@ func TestEventEmitter_Send(t *testing.T) {
 				parentState: StateMock{
 					IdentityChainID: primitives.NewZeroHash(),
 				},
-				eventSender: &mockEventSender{
+				eventSenders: []eventservices.EventSender{&mockEventSender{
 					eventsOutQueue:          make(chan *eventmessages.FactomEvent, 0),
 					droppedFromQueueCounter: prometheus.NewCounter(prometheus.CounterOpts{}),
-				},
+				}},
 			},
 			Event: eventinput.NodeInfoMessageF(eventmessages.NodeMessageCode_GENERAL, "test message of node: %s", "node name"),
 			Assertion: func(t *testing.T, eventService *mockEventSender, err error) {
@@ -39,9 +40,9 @@ func TestEventEmitter_Send(t *testing.T) {
 		},
 		"not-running": {
 			Emitter: &eventEmitter{
-				eventSender: &mockEventSender{
+				eventSenders: []eventservices.EventSender{&mockEventSender{
 					eventsOutQueue: make(chan *eventmessages.FactomEvent, 5000),
-				},
+				}},
 				parentState: StateMock{
 					RunState: runstate.Stopping,
 				},
@@ -54,10 +55,10 @@ func TestEventEmitter_Send(t *testing.T) {
 		},
 		"nil-event": {
 			Emitter: &eventEmitter{
-				eventSender: &mockEventSender{
+				eventSenders: []eventservices.EventSender{&mockEventSender{
 					eventsOutQueue:      make(chan *eventmessages.FactomEvent, 5000),
 					replayDuringStartup: true,
-				},
+				}},
 				parentState: StateMock{},
 			},
 			Event: nil,
@@ -68,10 +69,10 @@ func TestEventEmitter_Send(t *testing.T) {
 		},
 		"mute-replay-starting": {
 			Emitter: &eventEmitter{
-				eventSender: &mockEventSender{
+				eventSenders: []eventservices.EventSender{&mockEventSender{
 					eventsOutQueue:      make(chan *eventmessages.FactomEvent, 5000),
 					replayDuringStartup: false,
-				},
+				}},
 				parentState: StateMock{
 					RunLeader: false,
 				},
@@ -87,7 +88,7 @@ func TestEventEmitter_Send(t *testing.T) {
 	for name, testCase := range testCases {
 		t.Run(name, func(t *testing.T) {
 			err := testCase.Emitter.Send(testCase.Event)
-			testCase.Assertion(t, testCase.Emitter.eventSender.(*mockEventSender), err)
+			testCase.Assertion(t, testCase.Emitter.eventSenders[0].(*mockEventSender), err)
 		})
 	}
 }
@@ -103,7 +104,7 @@ func TestEventsService_SendFillupQueue(t *testing.T) {
 		parentState: StateMock{
 			IdentityChainID: primitives.NewZeroHash(),
 		},
-		eventSender: eventSender,
+		eventSenders: []eventservices.EventSender{eventSender},
 	}
 
 	event := eventinput.NodeInfoMessageF(eventmessages.NodeMessageCode_GENERAL, "test message of node: %s", "node name")
@@ -125,18 +126,27 @@ func getCounterValue(t *testing.T, counter prometheus.Counter) float64 {
 }
 
 type mockEventSender struct {
+	name                    string
 	eventsOutQueue          chan *eventmessages.FactomEvent
 	droppedFromQueueCounter prometheus.Counter
 	notSentCounter          prometheus.Counter
 	replayDuringStartup     bool
+	sendBalanceChanges      bool
+	filter                  *eventservices.EventFilter
 }
 
+func (m *mockEventSender) GetName() string {
+	return m.name
+}
 func (m *mockEventSender) GetBroadcastContent() eventconfig.BroadcastContent {
 	return eventconfig.BroadcastAlways
 }
 func (m *mockEventSender) IsSendStateChangeEvents() bool {
 	return true
 }
+func (m *mockEventSender) IsSendBalanceChanges() bool {
+	return m.sendBalanceChanges
+}
 func (m *mockEventSender) ReplayDuringStartup() bool {
 	return m.replayDuringStartup
 }
@@ -147,6 +157,13 @@ func (m *mockEventSender) GetEventQueue() chan *eventmessages.FactomEvent {
 	return m.eventsOutQueue
 }
 
+func (m *mockEventSender) Stamp(event *eventmessages.FactomEvent) *eventmessages.FactomEvent {
+	return event
+}
+func (m *mockEventSender) GetFilter() *eventservices.EventFilter {
+	return m.filter
+}
+
 func (m *mockEventSender) Shutdown() {}
 
 type StateMock struct {
@@ -171,3 +188,39 @@ func (s StateMock) IsRunLeader() bool {
 func (s StateMock) GetEventService() EventService {
 	return s.Service
 }
+
+func TestEventEmitter_SendMultipleReceivers(t *testing.T) {
+	full := &mockEventSender{
+		eventsOutQueue:          make(chan *eventmessages.FactomEvent, 1),
+		droppedFromQueueCounter: prometheus.NewCounter(prometheus.CounterOpts{}),
+		replayDuringStartup:     true,
+	}
+	other := &mockEventSender{
+		eventsOutQueue:          make(chan *eventmessages.FactomEvent, 5),
+		droppedFromQueueCounter: prometheus.NewCounter(prometheus.CounterOpts{}),
+		replayDuringStartup:     true,
+	}
+	filtered := &mockEventSender{
+		eventsOutQueue:          make(chan *eventmessages.FactomEvent, 5),
+		droppedFromQueueCounter: prometheus.NewCounter(prometheus.CounterOpts{}),
+		replayDuringStartup:     true,
+		filter:                  &eventservices.EventFilter{EventTypes: map[string]bool{"processListEvent": true}},
+	}
+	eventEmitter := &eventEmitter{
+		parentState: StateMock{
+			IdentityChainID: primitives.NewZeroHash(),
+		},
+		eventSenders: []eventservices.EventSender{full, other, filtered},
+	}
+
+	event := eventinput.NodeInfoMessageF(eventmessages.NodeMessageCode_GENERAL, "test message of node: %s", "node name")
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, eventEmitter.Send(event))
+	}
+
+	assert.Equal(t, 1, len(full.eventsOutQueue))
+	assert.Equal(t, float64(2), getCounterValue(t, full.droppedFromQueueCounter))
+	assert.Equal(t, 3, len(other.eventsOutQueue))
+	assert.Equal(t, float64(0), getCounterValue(t, other.droppedFromQueueCounter))
+	assert.Equal(t, 0, len(filtered.eventsOutQueue))
+}
@@ -48,6 +48,23 @@ type NodeMessageEvent struct {
 	NodeMessage *eventmessages.NodeMessage
 }
 
+type FactoidTransactionEvent struct {
+	EventSource  eventmessages.EventSource
+	EntityState  eventmessages.EntityState
+	Payload      interfaces.ITransaction
+	BlockHeight  uint32
+	MinuteNumber int
+}
+
+type BalanceChangeEvent struct {
+	EventSource   eventmessages.EventSource
+	Address       interfaces.IAddress
+	EntryCredit   bool
+	Change        int64
+	TransactionID interfaces.IHash
+	BlockHeight   uint32
+}
+
 func (event RegistrationEvent) GetStreamSource() eventmessages.EventSource {
 	return event.EventSource
 }
@@ -108,6 +125,22 @@ func (event NodeMessageEvent) GetNodeMessage() *eventmessages.NodeMessage {
 	return event.NodeMessage
 }
 
+func (event FactoidTransactionEvent) GetStreamSource() eventmessages.EventSource {
+	return event.EventSource
+}
+
+func (event FactoidTransactionEvent) GetEntityState() eventmessages.EntityState {
+	return event.EntityState
+}
+
+func (event FactoidTransactionEvent) GetPayload() interfaces.ITransaction {
+	return event.Payload
+}
+
+func (event BalanceChangeEvent) GetStreamSource() eventmessages.EventSource {
+	return event.EventSource
+}
+
 func NewRegistrationEvent(streamSource eventmessages.EventSource, msg interfaces.IMsg) *RegistrationEvent {
 	return &RegistrationEvent{
 		EventSource: streamSource,
@@ -144,6 +177,57 @@ func NewAnchorEvent(streamSource eventmessages.EventSource, dbDirBlockInfo inter
 	}
 }
 
+func NewFactoidTransactionEvent(streamSource eventmessages.EventSource, entityState eventmessages.EntityState, transaction interfaces.ITransaction, blockHeight uint32, minuteNumber int) *FactoidTransactionEvent {
+	return &FactoidTransactionEvent{
+		EventSource:  streamSource,
+		EntityState:  entityState,
+		Payload:      transaction,
+		BlockHeight:  blockHeight,
+		MinuteNumber: minuteNumber,
+	}
+}
+
+// NewBalanceChangeEvents returns the net change of the balance of each address of the transaction,
+// in the order the addresses first appear in it. Entry credit outputs are converted to entry
+// credits at the exchange rate of the block
+func NewBalanceChangeEvents(streamSource eventmessages.EventSource, transaction interfaces.ITransaction, blockHeight uint32, exchangeRate uint64) []*BalanceChangeEvent {
+	var events []*BalanceChangeEvent
+	changes := make(map[[33]byte]*BalanceChangeEvent)
+	add := func(address interfaces.IAddress, entryCredit bool, change int64) {
+		var key [33]byte
+		copy(key[:], address.Bytes())
+		if entryCredit {
+			key[32] = 1
+		}
+		event, ok := changes[key]
+		if !ok {
+			event = &BalanceChangeEvent{
+				EventSource:   streamSource,
+				Address:       address,
+				EntryCredit:   entryCredit,
+				TransactionID: transaction.GetSigHash(),
+				BlockHeight:   blockHeight,
+			}
+			changes[key] = event
+			events = append(events, event)
+		}
+		event.Change += change
+	}
+
+	for _, input := range transaction.GetInputs() {
+		add(input.GetAddress(), false, -int64(input.GetAmount()))
+	}
+	for _, output := range transaction.GetOutputs() {
+		add(output.GetAddress(), false, int64(output.GetAmount()))
+	}
+	if exchangeRate > 0 {
+		for _, output := range transaction.GetECOutputs() {
+			add(output.GetAddress(), true, int64(output.GetAmount()/exchangeRate))
+		}
+	}
+	return events
+}
+
 func ProcessListEventNewBlock(streamSource eventmessages.EventSource, newBlockHeight uint32) *ProcessListEvent {
 	return &ProcessListEvent{
 		EventSource: streamSource,
@@ -10,6 +10,7 @@ import (
 	"github.com/FactomProject/factomd/common/factoid"
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/messages"
+	"github.com/FactomProject/factomd/common/primitives"
 	"github.com/FactomProject/factomd/events/eventinput"
 	"github.com/FactomProject/factomd/events/eventmessages/generated/eventmessages"
 	"github.com/FactomProject/factomd/testHelper"
@@ -106,6 +107,49 @@ func TestEventInput_NodeErrorMessage(t *testing.T) {
 	}
 }
 
+func TestEventInput_FactoidTransactionEvent(t *testing.T) {
+	transaction := new(factoid.Transaction)
+	factoidTransactionEvent := eventinput.NewFactoidTransactionEvent(eventmessages.EventSource_LIVE, eventmessages.EntityState_ACCEPTED, transaction, 10, 3)
+
+	assert.NotNil(t, factoidTransactionEvent)
+	assert.Equal(t, eventmessages.EventSource_LIVE, factoidTransactionEvent.GetStreamSource())
+	assert.Equal(t, eventmessages.EntityState_ACCEPTED, factoidTransactionEvent.GetEntityState())
+	assert.Equal(t, transaction, factoidTransactionEvent.GetPayload())
+	assert.Equal(t, uint32(10), factoidTransactionEvent.BlockHeight)
+	assert.Equal(t, 3, factoidTransactionEvent.MinuteNumber)
+}
+
+func TestEventInput_BalanceChangeEvents(t *testing.T) {
+	from := factoid.NewAddress(primitives.Sha([]byte("from")).Bytes())
+	to := factoid.NewAddress(primitives.Sha([]byte("to")).Bytes())
+	transaction := new(factoid.Transaction)
+	transaction.AddInput(from, 5000)
+	transaction.AddOutput(to, 3000)
+	transaction.AddOutput(from, 1000)
+	transaction.AddECOutput(to, 500)
+
+	events := eventinput.NewBalanceChangeEvents(eventmessages.EventSource_LIVE, transaction, 10, 100)
+	if assert.Equal(t, 3, len(events)) {
+		expected := []struct {
+			Address     interfaces.IAddress
+			EntryCredit bool
+			Change      int64
+		}{
+			{from, false, -4000},
+			{to, false, 3000},
+			{to, true, 5},
+		}
+		for i, event := range events {
+			assert.Equal(t, eventmessages.EventSource_LIVE, event.GetStreamSource())
+			assert.Equal(t, expected[i].Address, event.Address)
+			assert.Equal(t, expected[i].EntryCredit, event.EntryCredit)
+			assert.Equal(t, expected[i].Change, event.Change)
+			assert.Equal(t, transaction.GetSigHash(), event.TransactionID)
+			assert.Equal(t, uint32(10), event.BlockHeight)
+		}
+	}
+}
+
 type mockDBState struct{}
 
 func (*mockDBState) GetDirectoryBlock() interfaces.IDirectoryBlock {
@@ -28,18 +28,21 @@ const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 type EventSource int32
 
 const (
-	EventSource_LIVE        EventSource = 0
-	EventSource_REPLAY_BOOT EventSource = 1
+	EventSource_LIVE           EventSource = 0
+	EventSource_REPLAY_BOOT    EventSource = 1
+	EventSource_REPLAY_HISTORY EventSource = 2
 )
 
 var EventSource_name = map[int32]string{
 	0: "LIVE",
 	1: "REPLAY_BOOT",
+	2: "REPLAY_HISTORY",
 }
 
 var EventSource_value = map[string]int32{
-	"LIVE":        0,
-	"REPLAY_BOOT": 1,
+	"LIVE":           0,
+	"REPLAY_BOOT":    1,
+	"REPLAY_HISTORY": 2,
 }
 
 func (x EventSource) String() string {
@@ -145,6 +148,7 @@ type FactomEvent struct {
 	EventSource     EventSource `protobuf:"varint,1,opt,name=eventSource,proto3,enum=eventmessages.EventSource" json:"eventSource,omitempty"`
 	FactomNodeName  string      `protobuf:"bytes,2,opt,name=factomNodeName,proto3" json:"factomNodeName,omitempty"`
 	IdentityChainID []byte      `protobuf:"bytes,3,opt,name=identityChainID,proto3" json:"identityChainID,omitempty"`
+	Sequence        uint64      `protobuf:"varint,12,opt,name=sequence,proto3" json:"sequence,omitempty"`
 	// Types that are valid to be assigned to Event:
 	//	*FactomEvent_ChainCommit
 	//	*FactomEvent_EntryCommit
@@ -154,6 +158,8 @@ type FactomEvent struct {
 	//	*FactomEvent_ProcessListEvent
 	//	*FactomEvent_NodeMessage
 	//	*FactomEvent_DirectoryBlockAnchor
+	//	*FactomEvent_FactoidTransaction
+	//	*FactomEvent_BalanceChange
 	Event                isFactomEvent_Event `protobuf_oneof:"event"`
 	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
 	XXX_unrecognized     []byte              `json:"-"`
@@ -223,6 +229,12 @@ type FactomEvent_NodeMessage struct {
 type FactomEvent_DirectoryBlockAnchor struct {
 	DirectoryBlockAnchor *DirectoryBlockAnchor `protobuf:"bytes,11,opt,name=directoryBlockAnchor,proto3,oneof" json:"directoryBlockAnchor,omitempty"`
 }
+type FactomEvent_FactoidTransaction struct {
+	FactoidTransaction *FactoidTransaction `protobuf:"bytes,13,opt,name=factoidTransaction,proto3,oneof" json:"factoidTransaction,omitempty"`
+}
+type FactomEvent_BalanceChange struct {
+	BalanceChange *BalanceChange `protobuf:"bytes,14,opt,name=balanceChange,proto3,oneof" json:"balanceChange,omitempty"`
+}
 
 func (*FactomEvent_ChainCommit) isFactomEvent_Event()          {}
 func (*FactomEvent_EntryCommit) isFactomEvent_Event()          {}
@@ -232,6 +244,8 @@ func (*FactomEvent_DirectoryBlockCommit) isFactomEvent_Event() {}
 func (*FactomEvent_ProcessListEvent) isFactomEvent_Event()     {}
 func (*FactomEvent_NodeMessage) isFactomEvent_Event()          {}
 func (*FactomEvent_DirectoryBlockAnchor) isFactomEvent_Event() {}
+func (*FactomEvent_FactoidTransaction) isFactomEvent_Event()   {}
+func (*FactomEvent_BalanceChange) isFactomEvent_Event()        {}
 
 func (m *FactomEvent) GetEvent() isFactomEvent_Event {
 	if m != nil {
@@ -261,6 +275,13 @@ func (m *FactomEvent) GetIdentityChainID() []byte {
 	return nil
 }
 
+func (m *FactomEvent) GetSequence() uint64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
 func (m *FactomEvent) GetChainCommit() *ChainCommit {
 	if x, ok := m.GetEvent().(*FactomEvent_ChainCommit); ok {
 		return x.ChainCommit
@@ -317,6 +338,20 @@ func (m *FactomEvent) GetDirectoryBlockAnchor() *DirectoryBlockAnchor {
 	return nil
 }
 
+func (m *FactomEvent) GetFactoidTransaction() *FactoidTransaction {
+	if x, ok := m.GetEvent().(*FactomEvent_FactoidTransaction); ok {
+		return x.FactoidTransaction
+	}
+	return nil
+}
+
+func (m *FactomEvent) GetBalanceChange() *BalanceChange {
+	if x, ok := m.GetEvent().(*FactomEvent_BalanceChange); ok {
+		return x.BalanceChange
+	}
+	return nil
+}
+
 // XXX_OneofWrappers is for the internal use of the proto package.
 func (*FactomEvent) XXX_OneofWrappers() []interface{} {
 	return []interface{}{
@@ -328,6 +363,8 @@ func (*FactomEvent) XXX_OneofWrappers() []interface{} {
 		(*FactomEvent_ProcessListEvent)(nil),
 		(*FactomEvent_NodeMessage)(nil),
 		(*FactomEvent_DirectoryBlockAnchor)(nil),
+		(*FactomEvent_FactoidTransaction)(nil),
+		(*FactomEvent_BalanceChange)(nil),
 	}
 }
 
@@ -751,6 +788,143 @@ func (m *DirectoryBlockCommit) GetEntryBlockEntries() []*EntryBlockEntry {
 	return nil
 }
 
+// A factoid transaction that is accepted into the process list, or committed to a directory block
+type FactoidTransaction struct {
+	EntityState          EntityState  `protobuf:"varint,1,opt,name=entityState,proto3,enum=eventmessages.EntityState" json:"entityState,omitempty"`
+	Transaction          *Transaction `protobuf:"bytes,2,opt,name=transaction,proto3" json:"transaction,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *FactoidTransaction) Reset()         { *m = FactoidTransaction{} }
+func (m *FactoidTransaction) String() string { return proto.CompactTextString(m) }
+func (*FactoidTransaction) ProtoMessage()    {}
+func (*FactoidTransaction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d6566f2e3579336b, []int{6}
+}
+func (m *FactoidTransaction) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *FactoidTransaction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_FactoidTransaction.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *FactoidTransaction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FactoidTransaction.Merge(m, src)
+}
+func (m *FactoidTransaction) XXX_Size() int {
+	return m.Size()
+}
+func (m *FactoidTransaction) XXX_DiscardUnknown() {
+	xxx_messageInfo_FactoidTransaction.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FactoidTransaction proto.InternalMessageInfo
+
+func (m *FactoidTransaction) GetEntityState() EntityState {
+	if m != nil {
+		return m.EntityState
+	}
+	return EntityState_REQUESTED
+}
+
+func (m *FactoidTransaction) GetTransaction() *Transaction {
+	if m != nil {
+		return m.Transaction
+	}
+	return nil
+}
+
+// The change of the balance of an address by a factoid transaction in a directory block. The change of a factoid
+// address is in factoshis, that of an entry credit address in entry credits
+type BalanceChange struct {
+	Address              []byte   `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	EntryCredit          bool     `protobuf:"varint,2,opt,name=entryCredit,proto3" json:"entryCredit,omitempty"`
+	Change               int64    `protobuf:"varint,3,opt,name=change,proto3" json:"change,omitempty"`
+	TransactionID        []byte   `protobuf:"bytes,4,opt,name=transactionID,proto3" json:"transactionID,omitempty"`
+	BlockHeight          uint32   `protobuf:"varint,5,opt,name=blockHeight,proto3" json:"blockHeight,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BalanceChange) Reset()         { *m = BalanceChange{} }
+func (m *BalanceChange) String() string { return proto.CompactTextString(m) }
+func (*BalanceChange) ProtoMessage()    {}
+func (*BalanceChange) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d6566f2e3579336b, []int{7}
+}
+func (m *BalanceChange) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *BalanceChange) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_BalanceChange.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *BalanceChange) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BalanceChange.Merge(m, src)
+}
+func (m *BalanceChange) XXX_Size() int {
+	return m.Size()
+}
+func (m *BalanceChange) XXX_DiscardUnknown() {
+	xxx_messageInfo_BalanceChange.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BalanceChange proto.InternalMessageInfo
+
+func (m *BalanceChange) GetAddress() []byte {
+	if m != nil {
+		return m.Address
+	}
+	return nil
+}
+
+func (m *BalanceChange) GetEntryCredit() bool {
+	if m != nil {
+		return m.EntryCredit
+	}
+	return false
+}
+
+func (m *BalanceChange) GetChange() int64 {
+	if m != nil {
+		return m.Change
+	}
+	return 0
+}
+
+func (m *BalanceChange) GetTransactionID() []byte {
+	if m != nil {
+		return m.TransactionID
+	}
+	return nil
+}
+
+func (m *BalanceChange) GetBlockHeight() uint32 {
+	if m != nil {
+		return m.BlockHeight
+	}
+	return 0
+}
+
 // ====  ENTRY CREDIT BLOCK =====
 type EntryCreditBlock struct {
 	Header               *EntryCreditBlockHeader  `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
@@ -764,7 +938,7 @@ func (m *EntryCreditBlock) Reset()         { *m = EntryCreditBlock{} }
 func (m *EntryCreditBlock) String() string { return proto.CompactTextString(m) }
 func (*EntryCreditBlock) ProtoMessage()    {}
 func (*EntryCreditBlock) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d6566f2e3579336b, []int{6}
+	return fileDescriptor_d6566f2e3579336b, []int{8}
 }
 func (m *EntryCreditBlock) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -822,7 +996,7 @@ func (m *EntryCreditBlockHeader) Reset()         { *m = EntryCreditBlockHeader{}
 func (m *EntryCreditBlockHeader) String() string { return proto.CompactTextString(m) }
 func (*EntryCreditBlockHeader) ProtoMessage()    {}
 func (*EntryCreditBlockHeader) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d6566f2e3579336b, []int{7}
+	return fileDescriptor_d6566f2e3579336b, []int{9}
 }
 func (m *EntryCreditBlockHeader) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -903,7 +1077,7 @@ func (m *EntryCreditBlockEntry) Reset()         { *m = EntryCreditBlockEntry{} }
 func (m *EntryCreditBlockEntry) String() string { return proto.CompactTextString(m) }
 func (*EntryCreditBlockEntry) ProtoMessage()    {}
 func (*EntryCreditBlockEntry) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d6566f2e3579336b, []int{8}
+	return fileDescriptor_d6566f2e3579336b, []int{10}
 }
 func (m *EntryCreditBlockEntry) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1027,7 +1201,7 @@ func (m *IncreaseBalance) Reset()         { *m = IncreaseBalance{} }
 func (m *IncreaseBalance) String() string { return proto.CompactTextString(m) }
 func (*IncreaseBalance) ProtoMessage()    {}
 func (*IncreaseBalance) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d6566f2e3579336b, []int{9}
+	return fileDescriptor_d6566f2e3579336b, []int{11}
 }
 func (m *IncreaseBalance) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1095,7 +1269,7 @@ func (m *MinuteNumber) Reset()         { *m = MinuteNumber{} }
 func (m *MinuteNumber) String() string { return proto.CompactTextString(m) }
 func (*MinuteNumber) ProtoMessage()    {}
 func (*MinuteNumber) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d6566f2e3579336b, []int{10}
+	return fileDescriptor_d6566f2e3579336b, []int{12}
 }
 func (m *MinuteNumber) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1142,7 +1316,7 @@ func (m *ServerIndexNumber) Reset()         { *m = ServerIndexNumber{} }
 func (m *ServerIndexNumber) String() string { return proto.CompactTextString(m) }
 func (*ServerIndexNumber) ProtoMessage()    {}
 func (*ServerIndexNumber) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d6566f2e3579336b, []int{11}
+	return fileDescriptor_d6566f2e3579336b, []int{13}
 }
 func (m *ServerIndexNumber) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1191,7 +1365,7 @@ func (m *NodeMessage) Reset()         { *m = NodeMessage{} }
 func (m *NodeMessage) String() string { return proto.CompactTextString(m) }
 func (*NodeMessage) ProtoMessage()    {}
 func (*NodeMessage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d6566f2e3579336b, []int{12}
+	return fileDescriptor_d6566f2e3579336b, []int{14}
 }
 func (m *NodeMessage) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1256,7 +1430,7 @@ func (m *ProcessListEvent) Reset()         { *m = ProcessListEvent{} }
 func (m *ProcessListEvent) String() string { return proto.CompactTextString(m) }
 func (*ProcessListEvent) ProtoMessage()    {}
 func (*ProcessListEvent) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d6566f2e3579336b, []int{13}
+	return fileDescriptor_d6566f2e3579336b, []int{15}
 }
 func (m *ProcessListEvent) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1341,7 +1515,7 @@ func (m *NewBlockEvent) Reset()         { *m = NewBlockEvent{} }
 func (m *NewBlockEvent) String() string { return proto.CompactTextString(m) }
 func (*NewBlockEvent) ProtoMessage()    {}
 func (*NewBlockEvent) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d6566f2e3579336b, []int{14}
+	return fileDescriptor_d6566f2e3579336b, []int{16}
 }
 func (m *NewBlockEvent) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1389,7 +1563,7 @@ func (m *NewMinuteEvent) Reset()         { *m = NewMinuteEvent{} }
 func (m *NewMinuteEvent) String() string { return proto.CompactTextString(m) }
 func (*NewMinuteEvent) ProtoMessage()    {}
 func (*NewMinuteEvent) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d6566f2e3579336b, []int{15}
+	return fileDescriptor_d6566f2e3579336b, []int{17}
 }
 func (m *NewMinuteEvent) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1443,6 +1617,8 @@ func init() {
 	proto.RegisterType((*EntryReveal)(nil), "eventmessages.EntryReveal")
 	proto.RegisterType((*StateChange)(nil), "eventmessages.StateChange")
 	proto.RegisterType((*DirectoryBlockCommit)(nil), "eventmessages.DirectoryBlockCommit")
+	proto.RegisterType((*FactoidTransaction)(nil), "eventmessages.FactoidTransaction")
+	proto.RegisterType((*BalanceChange)(nil), "eventmessages.BalanceChange")
 	proto.RegisterType((*EntryCreditBlock)(nil), "eventmessages.EntryCreditBlock")
 	proto.RegisterType((*EntryCreditBlockHeader)(nil), "eventmessages.EntryCreditBlockHeader")
 	proto.RegisterType((*EntryCreditBlockEntry)(nil), "eventmessages.EntryCreditBlockEntry")
@@ -1458,95 +1634,103 @@ func init() {
 func init() { proto.RegisterFile("eventmessages/factomEvents.proto", fileDescriptor_d6566f2e3579336b) }
 
 var fileDescriptor_d6566f2e3579336b = []byte{
-	// 1399 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x58, 0xcf, 0x73, 0xdb, 0xc4,
-	0x17, 0xb7, 0xfc, 0x23, 0x89, 0x9f, 0xec, 0xc4, 0xdd, 0x49, 0xfb, 0xf5, 0x37, 0x14, 0xd7, 0x23,
-	0x0a, 0x13, 0x32, 0x8c, 0x3b, 0x13, 0x98, 0x01, 0x06, 0x28, 0xd8, 0xb2, 0x52, 0xbb, 0x75, 0xec,
-	0xb0, 0x71, 0xe9, 0xa4, 0x97, 0x8c, 0x2c, 0x6d, 0x13, 0x81, 0x2d, 0x65, 0x24, 0x39, 0x6d, 0xff,
-	0x08, 0x0e, 0xbd, 0xc1, 0x81, 0x3f, 0x80, 0x23, 0x07, 0x4e, 0xfc, 0x03, 0x1c, 0x39, 0x70, 0x87,
-	0x29, 0x47, 0xfe, 0x09, 0x66, 0x77, 0x65, 0x6b, 0xb5, 0x52, 0xda, 0xb4, 0x3d, 0xc5, 0xfb, 0xf6,
-	0xf3, 0xd9, 0x7d, 0xef, 0xed, 0x67, 0xdf, 0x3e, 0x05, 0x9a, 0xe4, 0x9c, 0xb8, 0xe1, 0x8c, 0x04,
-	0x81, 0x79, 0x42, 0x82, 0x5b, 0x8f, 0x4c, 0x2b, 0xf4, 0x66, 0x06, 0xb5, 0x05, 0xad, 0x33, 0xdf,
-	0x0b, 0x3d, 0x54, 0x4d, 0x20, 0xb6, 0x6e, 0x9c, 0x78, 0xde, 0xc9, 0x94, 0xdc, 0x62, 0x93, 0x93,
-	0xf9, 0xa3, 0x5b, 0xa1, 0x33, 0x23, 0x41, 0x68, 0xce, 0xce, 0x38, 0x7e, 0xab, 0x91, 0x5c, 0xd1,
-	0xb4, 0x67, 0x8e, 0xdb, 0x99, 0x7a, 0xd6, 0x77, 0xd1, 0xbc, 0x96, 0x9c, 0xb7, 0x1d, 0x9f, 0x58,
-	0xa1, 0xe7, 0x3f, 0x15, 0x31, 0xd2, 0x1a, 0xc4, 0x0d, 0x93, 0xf3, 0x59, 0x5e, 0x3b, 0xb6, 0x80,
-	0xd0, 0xfe, 0x2d, 0x81, 0xba, 0x17, 0x07, 0x83, 0x3e, 0x07, 0x95, 0x71, 0x0e, 0xbd, 0xb9, 0x6f,
-	0x91, 0xba, 0xd2, 0x54, 0xb6, 0xd7, 0x77, 0xb7, 0x5a, 0x89, 0x75, 0x5a, 0x46, 0x8c, 0xc0, 0x22,
-	0x1c, 0xbd, 0x07, 0xeb, 0x3c, 0x33, 0x43, 0xcf, 0x26, 0x43, 0x73, 0x46, 0xea, 0xf9, 0xa6, 0xb2,
-	0x5d, 0xc6, 0x92, 0x15, 0x6d, 0xc3, 0x86, 0x63, 0x13, 0x37, 0x74, 0xc2, 0xa7, 0xfa, 0xa9, 0xe9,
-	0xb8, 0xfd, 0x6e, 0xbd, 0xd0, 0x54, 0xb6, 0x2b, 0x58, 0x36, 0xa3, 0xdb, 0xa0, 0x5a, 0xf4, 0xa7,
-	0xee, 0xcd, 0x66, 0x4e, 0x58, 0x2f, 0x36, 0x95, 0x6d, 0x35, 0xe5, 0x8f, 0x1e, 0x23, 0x7a, 0x39,
-	0x2c, 0x12, 0x28, 0x9f, 0x65, 0x25, 0xe2, 0x97, 0x32, 0xf9, 0x46, 0x8c, 0xa0, 0x7c, 0x81, 0xb0,
-	0xe4, 0x63, 0x72, 0x4e, 0xcc, 0x69, 0x7d, 0xe5, 0x62, 0x3e, 0x47, 0x2c, 0xf9, 0x7c, 0x48, 0xf9,
-	0x41, 0x68, 0x86, 0x44, 0x3f, 0x35, 0xdd, 0x13, 0x52, 0x5f, 0xcd, 0xe4, 0x1f, 0xc6, 0x08, 0xca,
-	0x17, 0x08, 0xe8, 0x08, 0x36, 0x93, 0x27, 0x1f, 0x05, 0xb2, 0xc6, 0x16, 0x7a, 0x47, 0x5a, 0xa8,
-	0x9b, 0x01, 0xed, 0xe5, 0x70, 0xe6, 0x12, 0x68, 0x1f, 0x6a, 0x67, 0xbe, 0x67, 0x91, 0x20, 0x18,
-	0x38, 0x41, 0xc8, 0xce, 0xb4, 0x5e, 0x66, 0xcb, 0xde, 0x90, 0x96, 0x3d, 0x90, 0x60, 0xbd, 0x1c,
-	0x4e, 0x51, 0x69, 0xa4, 0xae, 0x67, 0x93, 0x7d, 0x4e, 0xaa, 0x43, 0x66, 0xa4, 0xc3, 0x18, 0x41,
-	0x23, 0x15, 0x08, 0xe9, 0x48, 0xdb, 0xae, 0x75, 0xea, 0xf9, 0x75, 0xf5, 0x12, 0x91, 0x72, 0x68,
-	0x3a, 0x52, 0x6e, 0xef, 0xac, 0x42, 0x89, 0xb1, 0xb5, 0xbf, 0xf2, 0xa0, 0x0a, 0x62, 0x61, 0x6a,
-	0x67, 0x72, 0x63, 0x27, 0x70, 0x91, 0xda, 0x63, 0x04, 0x16, 0xe1, 0xa8, 0x19, 0x69, 0xb3, 0xdf,
-	0xed, 0x99, 0xc1, 0x29, 0x93, 0x7a, 0x05, 0x8b, 0x26, 0x74, 0x1d, 0xca, 0x4c, 0x0c, 0x6c, 0x9e,
-	0x2b, 0x3c, 0x36, 0x20, 0x04, 0xc5, 0xc7, 0x64, 0x6a, 0x33, 0x51, 0x57, 0x30, 0xfb, 0x8d, 0x3e,
-	0x81, 0xf2, 0xb2, 0x50, 0x2c, 0xd5, 0xca, 0x4b, 0x49, 0x6b, 0x51, 0x4a, 0x5a, 0xe3, 0x05, 0x02,
-	0xc7, 0x60, 0x54, 0x87, 0x55, 0xcb, 0x27, 0xb6, 0x13, 0x06, 0x4c, 0xa5, 0x55, 0xbc, 0x18, 0xa2,
-	0x5d, 0xd8, 0xe4, 0x92, 0x66, 0xe3, 0x83, 0xf9, 0x64, 0xea, 0x58, 0xf7, 0xc8, 0x53, 0x26, 0xc6,
-	0x0a, 0xce, 0x9c, 0xa3, 0x9e, 0x07, 0xce, 0x89, 0x6b, 0x86, 0x73, 0x9f, 0x30, 0xb1, 0x55, 0x70,
-	0x6c, 0xa0, 0x7b, 0x9d, 0x13, 0x3f, 0x70, 0x3c, 0x97, 0x29, 0xa6, 0x8a, 0x17, 0x43, 0xed, 0xe7,
-	0x3c, 0xa8, 0xc2, 0x75, 0x7a, 0xc3, 0x0c, 0x27, 0xf2, 0x97, 0x97, 0xf3, 0x97, 0xc8, 0x55, 0xe1,
-	0x35, 0x73, 0x55, 0xbc, 0x5c, 0xae, 0x4a, 0x97, 0xcd, 0xd5, 0xca, 0x0b, 0x72, 0xb5, 0x9a, 0xcc,
-	0xd5, 0x6f, 0x4a, 0x94, 0xab, 0xa8, 0x56, 0xbc, 0x59, 0xae, 0x3e, 0x82, 0x12, 0xf3, 0x8e, 0xe5,
-	0x49, 0xdd, 0x6d, 0x64, 0xd5, 0x28, 0x76, 0x29, 0xf8, 0x96, 0x1c, 0xfc, 0xfa, 0x39, 0xd4, 0xbe,
-	0x57, 0x40, 0x15, 0x0a, 0x17, 0x6a, 0x00, 0x70, 0x77, 0xd8, 0x61, 0x29, 0x2c, 0x0d, 0x82, 0x45,
-	0x8e, 0x2e, 0xff, 0xca, 0x77, 0x6d, 0x42, 0x9d, 0xef, 0x11, 0xe7, 0xe4, 0x34, 0x64, 0x9e, 0x56,
-	0xb1, 0x68, 0xd2, 0x7e, 0x29, 0xc0, 0x66, 0x56, 0xfd, 0x43, 0x06, 0xac, 0x27, 0xab, 0x02, 0x73,
-	0x4e, 0xdd, 0x7d, 0xfb, 0x85, 0x25, 0x05, 0x4b, 0x24, 0xf4, 0x29, 0x40, 0xfc, 0x46, 0x47, 0x49,
-	0xfe, 0xbf, 0xb4, 0x44, 0x7b, 0x09, 0xc0, 0x02, 0x18, 0x7d, 0x09, 0x15, 0xf1, 0xe9, 0x8d, 0xf2,
-	0xfc, 0x96, 0x44, 0xde, 0x13, 0x20, 0x38, 0x41, 0x40, 0xf7, 0xa0, 0x26, 0x28, 0x8f, 0x2f, 0x52,
-	0xcc, 0x2c, 0xd5, 0x86, 0x04, 0xc3, 0x29, 0x22, 0xfa, 0x2c, 0x7a, 0xd2, 0xd8, 0x28, 0xa8, 0x97,
-	0x9a, 0x85, 0x8c, 0x48, 0x62, 0xb9, 0x60, 0x11, 0x8d, 0x06, 0x70, 0x85, 0x24, 0x94, 0xe4, 0x10,
-	0x5a, 0x6f, 0x0a, 0x97, 0x50, 0x5c, 0x9a, 0xa8, 0x3d, 0x53, 0xa0, 0x26, 0x7b, 0x8c, 0xbe, 0x80,
-	0x95, 0x53, 0x62, 0xda, 0xc4, 0x8f, 0xce, 0xe9, 0xdd, 0x97, 0x84, 0xd8, 0x63, 0x60, 0x1c, 0x91,
-	0xd0, 0x6d, 0x58, 0x25, 0x91, 0x5f, 0x79, 0xe6, 0xd7, 0xcd, 0x97, 0xf0, 0xb9, 0x77, 0x0b, 0x92,
-	0xf6, 0xa7, 0x02, 0xd7, 0xb2, 0xb7, 0x40, 0x5b, 0xb0, 0x36, 0xf1, 0x6c, 0x51, 0xe0, 0xcb, 0x31,
-	0x6a, 0x01, 0x3a, 0xf3, 0xc9, 0xb9, 0xe3, 0xcd, 0x03, 0x8e, 0x16, 0x6a, 0x56, 0xc6, 0x0c, 0xda,
-	0xa1, 0xaf, 0x2f, 0xb7, 0xee, 0xcd, 0xa7, 0x53, 0xe1, 0x85, 0x48, 0xd9, 0x65, 0xf1, 0x17, 0x53,
-	0xe2, 0xa7, 0x08, 0x6f, 0xf2, 0x2d, 0xb1, 0x42, 0xdd, 0x9b, 0xbb, 0xbc, 0xcd, 0x29, 0x62, 0xd1,
-	0xa4, 0x3d, 0x2b, 0xc0, 0xd5, 0xcc, 0xc8, 0xe5, 0x16, 0x4b, 0x79, 0xc3, 0x16, 0x2b, 0xff, 0xaa,
-	0x2d, 0xd6, 0x5d, 0xd8, 0x70, 0x5c, 0xcb, 0x27, 0x66, 0x40, 0x3a, 0xe6, 0xd4, 0x74, 0x2d, 0x12,
-	0x5d, 0x10, 0x59, 0x50, 0xfd, 0x24, 0xaa, 0x97, 0xc3, 0x32, 0x11, 0xb5, 0xa1, 0x32, 0x73, 0xdc,
-	0x79, 0x48, 0x86, 0xf3, 0xd9, 0x84, 0xf8, 0xd1, 0x25, 0x91, 0x6f, 0xda, 0xbe, 0x00, 0xe9, 0xe5,
-	0x70, 0x82, 0x82, 0x0e, 0xe0, 0x4a, 0x40, 0xfc, 0x73, 0xe2, 0xf7, 0x5d, 0x9b, 0x3c, 0x89, 0xd6,
-	0xe1, 0x2f, 0x71, 0x53, 0xee, 0xdb, 0x64, 0x5c, 0x2f, 0x87, 0xd3, 0xe4, 0xce, 0xff, 0xe0, 0x2a,
-	0xc9, 0xca, 0xbc, 0xf6, 0xa3, 0x02, 0x1b, 0x52, 0x50, 0x17, 0x3e, 0x40, 0xca, 0x0b, 0x1e, 0xa0,
-	0x9b, 0x50, 0x0d, 0x7d, 0xd3, 0x0d, 0x4c, 0x2b, 0x74, 0x3c, 0xda, 0x4c, 0x73, 0xd9, 0x25, 0x8d,
-	0x68, 0x13, 0x4a, 0x0e, 0xf5, 0x8a, 0x65, 0xb7, 0x88, 0xf9, 0x00, 0x5d, 0x83, 0x15, 0x73, 0xc6,
-	0x44, 0x53, 0x64, 0xe6, 0x68, 0xa4, 0xed, 0x42, 0x45, 0x4c, 0x13, 0xd2, 0xa4, 0xcc, 0x2a, 0x4c,
-	0x84, 0x09, 0x9b, 0xd6, 0x86, 0x2b, 0xa9, 0x94, 0xa0, 0x0f, 0xb2, 0xf2, 0xc9, 0xd9, 0xe9, 0x09,
-	0xed, 0x27, 0x05, 0x54, 0xa1, 0x49, 0x44, 0x5f, 0x81, 0x1a, 0xa5, 0x5b, 0xf7, 0xec, 0xc5, 0x9b,
-	0xd8, 0xb8, 0xb8, 0xab, 0xa4, 0x28, 0x2c, 0x52, 0xd0, 0x0e, 0x94, 0xa6, 0xe4, 0x9c, 0x4c, 0xa3,
-	0x17, 0x67, 0x53, 0xe2, 0x0e, 0xe8, 0x1c, 0xe6, 0x10, 0x7a, 0x8d, 0xa2, 0x89, 0x31, 0x79, 0xc2,
-	0x5f, 0x99, 0x32, 0x16, 0x4d, 0xda, 0xaf, 0x0a, 0xd4, 0xe4, 0x76, 0x18, 0x75, 0xa1, 0xea, 0x92,
-	0xc7, 0xfc, 0x60, 0x59, 0x1b, 0xcd, 0xef, 0xd0, 0x75, 0xd9, 0x4d, 0x11, 0xd3, 0xcb, 0xe1, 0x24,
-	0x09, 0xdd, 0x81, 0x75, 0x97, 0x3c, 0xe6, 0x49, 0xe7, 0xcb, 0xe4, 0x33, 0xdf, 0xa9, 0x61, 0x02,
-	0xd4, 0xcb, 0x61, 0x89, 0xd6, 0x41, 0xe9, 0xc6, 0x5e, 0xfb, 0x18, 0xaa, 0x89, 0xed, 0xe9, 0xa7,
-	0xda, 0x62, 0xfb, 0xa8, 0xac, 0xf0, 0x33, 0x91, 0xac, 0xda, 0x01, 0xac, 0x27, 0x37, 0xa4, 0xed,
-	0xce, 0x72, 0xc3, 0x88, 0x14, 0x1b, 0xe4, 0x5a, 0x95, 0x4f, 0xd5, 0xaa, 0x9d, 0x6d, 0x50, 0x85,
-	0x0f, 0x48, 0xb4, 0x06, 0xc5, 0x41, 0xff, 0x1b, 0xa3, 0x96, 0x43, 0x1b, 0xa0, 0x62, 0xe3, 0x60,
-	0xd0, 0x3e, 0x3a, 0xee, 0x8c, 0x46, 0xe3, 0x9a, 0xb2, 0xf3, 0x90, 0xf5, 0x47, 0xcb, 0x1e, 0xa0,
-	0x0a, 0x65, 0x6c, 0x7c, 0x7d, 0xdf, 0x38, 0x1c, 0x1b, 0xdd, 0x5a, 0x0e, 0x55, 0x60, 0xad, 0xad,
-	0xeb, 0xc6, 0x01, 0x1d, 0x29, 0x74, 0x84, 0x8d, 0xbb, 0x86, 0x4e, 0x47, 0x79, 0xd4, 0x84, 0xeb,
-	0xfa, 0x68, 0x7f, 0xbf, 0x3f, 0x1e, 0x1b, 0xdd, 0xe3, 0xf1, 0xe8, 0xb8, 0xdb, 0xc7, 0x86, 0x3e,
-	0x1e, 0xe1, 0xa3, 0xe3, 0xce, 0x60, 0xa4, 0xdf, 0xab, 0x15, 0x76, 0xde, 0x87, 0x12, 0x3b, 0x7a,
-	0xba, 0x7f, 0x7f, 0xb8, 0x37, 0xaa, 0xe5, 0x90, 0x0a, 0xab, 0x0f, 0xda, 0x78, 0xd8, 0x1f, 0xde,
-	0xa9, 0x29, 0xa8, 0x0c, 0x25, 0x03, 0xe3, 0x11, 0xae, 0xe5, 0x77, 0x0c, 0xd8, 0x90, 0x14, 0x46,
-	0xa1, 0x77, 0x8c, 0xa1, 0x81, 0xdb, 0x03, 0xce, 0x3b, 0x1c, 0xb7, 0x31, 0xf7, 0x03, 0x60, 0xe5,
-	0xf0, 0x68, 0xa8, 0x33, 0x2f, 0x2a, 0xb0, 0x76, 0xd8, 0xbb, 0x3f, 0xee, 0x8e, 0x1e, 0x0c, 0x6b,
-	0x85, 0x4e, 0xfb, 0xf7, 0xe7, 0x0d, 0xe5, 0x8f, 0xe7, 0x0d, 0xe5, 0xef, 0xe7, 0x0d, 0xe5, 0x87,
-	0x7f, 0x1a, 0x39, 0x68, 0x5a, 0xde, 0xac, 0xc5, 0x3f, 0x8d, 0xa3, 0x3f, 0x76, 0xf2, 0xac, 0x1f,
-	0x26, 0xff, 0xa9, 0x30, 0x59, 0x61, 0x2d, 0xd9, 0x87, 0xff, 0x05, 0x00, 0x00, 0xff, 0xff, 0x60,
-	0x04, 0x7d, 0x07, 0x8e, 0x10, 0x00, 0x00,
+	// 1531 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x58, 0x4d, 0x73, 0xdb, 0x44,
+	0x18, 0xb6, 0xfc, 0x91, 0xc4, 0xaf, 0xec, 0xc4, 0xdd, 0x49, 0x8b, 0x08, 0xc5, 0x35, 0xa2, 0x30,
+	0x21, 0xc3, 0xa4, 0x33, 0x81, 0x19, 0x60, 0x28, 0x05, 0x7f, 0x28, 0xb5, 0x5b, 0xc7, 0x0e, 0x6b,
+	0x97, 0x4e, 0x7a, 0xc9, 0xc8, 0xd2, 0x36, 0x11, 0xd8, 0x52, 0x90, 0xe4, 0xb4, 0xfd, 0x11, 0xcc,
+	0xd0, 0x1b, 0x1c, 0xb8, 0x70, 0x63, 0x38, 0x71, 0xe0, 0xc4, 0x1f, 0xe0, 0xc8, 0x81, 0x3b, 0x4c,
+	0xf9, 0x23, 0xcc, 0xee, 0xca, 0xf6, 0x6a, 0xa5, 0xb4, 0x69, 0x73, 0x4a, 0xf6, 0xdd, 0xe7, 0x79,
+	0x77, 0xf7, 0xd9, 0xf7, 0x63, 0x65, 0xa8, 0x91, 0x53, 0xe2, 0x86, 0x13, 0x12, 0x04, 0xe6, 0x11,
+	0x09, 0x6e, 0x3c, 0x34, 0xad, 0xd0, 0x9b, 0x18, 0xd4, 0x16, 0x6c, 0x9f, 0xf8, 0x5e, 0xe8, 0xa1,
+	0x72, 0x0c, 0xb1, 0x71, 0xed, 0xc8, 0xf3, 0x8e, 0xc6, 0xe4, 0x06, 0x9b, 0x1c, 0x4d, 0x1f, 0xde,
+	0x08, 0x9d, 0x09, 0x09, 0x42, 0x73, 0x72, 0xc2, 0xf1, 0x1b, 0xd5, 0xb8, 0x47, 0xd3, 0x9e, 0x38,
+	0x6e, 0x63, 0xec, 0x59, 0xdf, 0x44, 0xf3, 0x7a, 0x7c, 0xde, 0x76, 0x7c, 0x62, 0x85, 0x9e, 0xff,
+	0x44, 0xc4, 0x48, 0x3e, 0x88, 0x1b, 0xc6, 0xe7, 0xd3, 0x76, 0xed, 0xd8, 0x02, 0x42, 0xff, 0x79,
+	0x19, 0xd4, 0xdd, 0xc5, 0x61, 0xd0, 0x4d, 0x50, 0x19, 0x67, 0xe0, 0x4d, 0x7d, 0x8b, 0x68, 0x4a,
+	0x4d, 0xd9, 0x5c, 0xdd, 0xd9, 0xd8, 0x8e, 0xf9, 0xd9, 0x36, 0x16, 0x08, 0x2c, 0xc2, 0xd1, 0xbb,
+	0xb0, 0xca, 0x95, 0xe9, 0x79, 0x36, 0xe9, 0x99, 0x13, 0xa2, 0x65, 0x6b, 0xca, 0x66, 0x11, 0x4b,
+	0x56, 0xb4, 0x09, 0x6b, 0x8e, 0x4d, 0xdc, 0xd0, 0x09, 0x9f, 0x34, 0x8f, 0x4d, 0xc7, 0xed, 0xb4,
+	0xb4, 0x5c, 0x4d, 0xd9, 0x2c, 0x61, 0xd9, 0x8c, 0x36, 0x60, 0x25, 0x20, 0xdf, 0x4e, 0x89, 0x6b,
+	0x11, 0xad, 0x54, 0x53, 0x36, 0xf3, 0x78, 0x3e, 0x46, 0xb7, 0x40, 0xb5, 0x28, 0xac, 0xe9, 0x4d,
+	0x26, 0x4e, 0xa8, 0xe5, 0x6b, 0xca, 0xa6, 0x9a, 0xd8, 0x6b, 0x73, 0x81, 0x68, 0x67, 0xb0, 0x48,
+	0xa0, 0x7c, 0xa6, 0x58, 0xc4, 0x2f, 0xa4, 0xf2, 0x8d, 0x05, 0x82, 0xf2, 0x05, 0xc2, 0x9c, 0x8f,
+	0xc9, 0x29, 0x31, 0xc7, 0xda, 0xd2, 0xd9, 0x7c, 0x8e, 0x98, 0xf3, 0xf9, 0x90, 0xf2, 0x83, 0xd0,
+	0x0c, 0x49, 0xf3, 0xd8, 0x74, 0x8f, 0x88, 0xb6, 0x9c, 0xca, 0x1f, 0x2c, 0x10, 0x94, 0x2f, 0x10,
+	0xd0, 0x01, 0xac, 0xc7, 0xa3, 0x22, 0x3a, 0xc8, 0x0a, 0x73, 0xf4, 0xb6, 0xe4, 0xa8, 0x95, 0x02,
+	0x6d, 0x67, 0x70, 0xaa, 0x0b, 0xb4, 0x07, 0x95, 0x13, 0xdf, 0xb3, 0x48, 0x10, 0x74, 0x9d, 0x20,
+	0x64, 0xf7, 0xad, 0x15, 0x99, 0xdb, 0x6b, 0x92, 0xdb, 0x7d, 0x09, 0xd6, 0xce, 0xe0, 0x04, 0x95,
+	0x9e, 0xd4, 0xf5, 0x6c, 0xb2, 0xc7, 0x49, 0x1a, 0xa4, 0x9e, 0xb4, 0xb7, 0x40, 0xd0, 0x93, 0x0a,
+	0x84, 0xe4, 0x49, 0xeb, 0xae, 0x75, 0xec, 0xf9, 0x9a, 0x7a, 0x8e, 0x93, 0x72, 0x68, 0xf2, 0xa4,
+	0xdc, 0x8e, 0x06, 0x80, 0xa2, 0xb4, 0x18, 0xfa, 0xa6, 0x1b, 0x98, 0x56, 0xe8, 0x78, 0xae, 0x56,
+	0x66, 0x8e, 0xdf, 0x92, 0x1c, 0xef, 0x26, 0x80, 0xed, 0x0c, 0x4e, 0xa1, 0xa3, 0x16, 0x94, 0x47,
+	0xe6, 0xd8, 0x74, 0xad, 0xd9, 0xdd, 0xae, 0x32, 0x7f, 0x57, 0x25, 0x7f, 0x0d, 0x11, 0xd3, 0xce,
+	0xe0, 0x38, 0xa9, 0xb1, 0x0c, 0x05, 0x86, 0xd7, 0xff, 0xc9, 0x82, 0x2a, 0xc4, 0x31, 0x4b, 0x52,
+	0x96, 0x25, 0x2c, 0x38, 0xce, 0x4a, 0xd2, 0x05, 0x02, 0x8b, 0x70, 0x54, 0x8b, 0xd2, 0xa6, 0xd3,
+	0x6a, 0x9b, 0xc1, 0x31, 0xcb, 0xd0, 0x12, 0x16, 0x4d, 0xe8, 0x2a, 0x14, 0x59, 0x9c, 0xb2, 0x79,
+	0x9e, 0x98, 0x0b, 0x03, 0x42, 0x90, 0x7f, 0x44, 0xc6, 0x36, 0xcb, 0xb7, 0x12, 0x66, 0xff, 0xa3,
+	0x8f, 0xa1, 0x38, 0xaf, 0x6f, 0xf3, 0x44, 0xe2, 0x15, 0x70, 0x7b, 0x56, 0x01, 0xb7, 0x87, 0x33,
+	0x04, 0x5e, 0x80, 0x91, 0x06, 0xcb, 0x96, 0x4f, 0x6c, 0x27, 0x0c, 0x58, 0x02, 0x95, 0xf1, 0x6c,
+	0x88, 0x76, 0x60, 0x9d, 0x67, 0x1b, 0x1b, 0xef, 0x4f, 0x47, 0x63, 0xc7, 0xba, 0x4b, 0x9e, 0xb0,
+	0x3c, 0x29, 0xe1, 0xd4, 0x39, 0xba, 0xf3, 0xc0, 0x39, 0x72, 0xcd, 0x70, 0xea, 0x13, 0x96, 0x07,
+	0x25, 0xbc, 0x30, 0xd0, 0xb5, 0x4e, 0x89, 0x1f, 0xd0, 0x0b, 0x2e, 0xf2, 0xb5, 0xa2, 0xa1, 0xfe,
+	0x4b, 0x16, 0x54, 0x21, 0xd3, 0x2f, 0xa8, 0x70, 0x4c, 0xbf, 0xac, 0xac, 0x5f, 0x4c, 0xab, 0xdc,
+	0x2b, 0x6a, 0x95, 0x3f, 0x9f, 0x56, 0x85, 0xf3, 0x6a, 0xb5, 0xf4, 0x1c, 0xad, 0x96, 0xe3, 0x5a,
+	0xfd, 0xa1, 0x44, 0x5a, 0x45, 0x65, 0xec, 0x62, 0x5a, 0x7d, 0x08, 0x05, 0xb6, 0x3b, 0xa6, 0x93,
+	0xba, 0x53, 0x4d, 0x2b, 0x9f, 0x2c, 0x5f, 0xf9, 0x92, 0x1c, 0xfc, 0xea, 0x1a, 0xea, 0xdf, 0x29,
+	0xa0, 0x0a, 0x35, 0x15, 0x55, 0x01, 0xf8, 0x76, 0xd8, 0x65, 0x29, 0x4c, 0x06, 0xc1, 0x22, 0x9f,
+	0x2e, 0xfb, 0xd2, 0xb9, 0x36, 0xa2, 0x9b, 0x6f, 0x13, 0xe7, 0xe8, 0x38, 0x64, 0x3b, 0x2d, 0x63,
+	0xd1, 0xa4, 0xff, 0x96, 0x83, 0xf5, 0xb4, 0xd2, 0x8c, 0x0c, 0x58, 0x8d, 0x17, 0x2c, 0xb6, 0x39,
+	0x75, 0xe7, 0xcd, 0xe7, 0x56, 0x3b, 0x2c, 0x91, 0xd0, 0x27, 0x00, 0x8b, 0xa7, 0x45, 0x24, 0xf2,
+	0xeb, 0x92, 0x8b, 0xfa, 0x1c, 0x80, 0x05, 0x30, 0xfa, 0x1c, 0x4a, 0xe2, 0x8b, 0x21, 0xd2, 0xf9,
+	0x8d, 0xf4, 0xa2, 0xc8, 0xe9, 0x31, 0x02, 0xba, 0x0b, 0x15, 0x21, 0xf2, 0xb8, 0x93, 0x7c, 0x6a,
+	0x17, 0x31, 0x24, 0x18, 0x4e, 0x10, 0xd1, 0xa7, 0x51, 0xb7, 0x65, 0xa3, 0x40, 0x2b, 0xd4, 0x72,
+	0x29, 0x27, 0x59, 0x84, 0x0b, 0x16, 0xd1, 0xa8, 0x0b, 0x97, 0x48, 0x2c, 0x92, 0x1c, 0x42, 0xeb,
+	0x4d, 0xee, 0x1c, 0x11, 0x97, 0x24, 0xea, 0xdf, 0x2b, 0x80, 0x92, 0xbd, 0xe0, 0x82, 0x89, 0x70,
+	0x13, 0xd4, 0x50, 0xe8, 0x40, 0xd9, 0xd4, 0x1e, 0x29, 0x2c, 0x87, 0x45, 0xb8, 0xfe, 0xab, 0x02,
+	0xe5, 0x58, 0x3b, 0xa1, 0x09, 0x6c, 0xda, 0xb6, 0x4f, 0x82, 0x20, 0x8a, 0xea, 0xd9, 0x90, 0x06,
+	0xa5, 0xa0, 0x2e, 0x5b, 0x69, 0x05, 0x8b, 0x26, 0x74, 0x05, 0x96, 0x2c, 0xde, 0xb8, 0xe8, 0x9d,
+	0xe7, 0x70, 0x34, 0x42, 0xd7, 0xa1, 0x2c, 0x2c, 0xda, 0x69, 0x45, 0x3d, 0x20, 0x6e, 0x94, 0x83,
+	0xbe, 0x90, 0x0c, 0xfa, 0xa7, 0x0a, 0x54, 0xe4, 0x2b, 0x47, 0x9f, 0xc1, 0xd2, 0x31, 0x31, 0x6d,
+	0xe2, 0x47, 0x81, 0xfe, 0xce, 0x0b, 0x62, 0xa4, 0xcd, 0xc0, 0x38, 0x22, 0xa1, 0x5b, 0xb0, 0x4c,
+	0xa2, 0x8b, 0xcd, 0xb2, 0x8b, 0xbd, 0xfe, 0x02, 0x3e, 0xbf, 0xde, 0x19, 0x49, 0xff, 0x5b, 0x81,
+	0x2b, 0xe9, 0x4b, 0xd0, 0x47, 0xe8, 0xc8, 0xb3, 0xc5, 0x0a, 0x31, 0x1f, 0xa3, 0x6d, 0x40, 0x27,
+	0x3e, 0x39, 0x75, 0xbc, 0x69, 0xc0, 0xd1, 0x42, 0xd1, 0x4f, 0x99, 0x41, 0x5b, 0x50, 0x99, 0x59,
+	0x77, 0xa7, 0xe3, 0xb1, 0xd0, 0x62, 0x13, 0x76, 0x59, 0xc8, 0x7c, 0x42, 0x48, 0x8a, 0xf0, 0x46,
+	0x5f, 0x13, 0x2b, 0x6c, 0x7a, 0x53, 0x97, 0x4b, 0x9d, 0xc7, 0xa2, 0x49, 0x7f, 0x9a, 0x83, 0xcb,
+	0xa9, 0x27, 0x97, 0x9f, 0xcf, 0xca, 0x05, 0x9f, 0xcf, 0xd9, 0x97, 0x7d, 0x3e, 0xdf, 0x81, 0x35,
+	0xc7, 0xb5, 0x7c, 0x62, 0x06, 0x24, 0x8a, 0xdc, 0xa8, 0xc2, 0xc8, 0x19, 0xd9, 0x89, 0xa3, 0xda,
+	0x19, 0x2c, 0x13, 0x51, 0x1d, 0x4a, 0x13, 0xc7, 0x9d, 0x86, 0xa4, 0x37, 0x9d, 0x8c, 0x88, 0xaf,
+	0xe5, 0x53, 0x4b, 0xd5, 0x9e, 0x00, 0x69, 0x67, 0x70, 0x8c, 0x82, 0xf6, 0xe1, 0x52, 0x40, 0xfc,
+	0x53, 0xe2, 0x77, 0x5c, 0x9b, 0x3c, 0x8e, 0xfc, 0xf0, 0xa7, 0x4c, 0x4d, 0x7e, 0x93, 0xcb, 0xb8,
+	0x76, 0x06, 0x27, 0xc9, 0x8d, 0xd7, 0xe0, 0x32, 0x49, 0x53, 0x5e, 0xff, 0x51, 0x81, 0x35, 0xe9,
+	0x50, 0x67, 0x76, 0x70, 0xe5, 0x39, 0x1d, 0x3c, 0x91, 0x8e, 0xd9, 0xb4, 0x74, 0x5c, 0x87, 0x82,
+	0x43, 0x77, 0xc5, 0xd4, 0xcd, 0x63, 0x3e, 0xa0, 0x29, 0x6e, 0x4e, 0x58, 0xd0, 0xe4, 0x99, 0x39,
+	0x1a, 0xe9, 0x3b, 0x50, 0x12, 0x65, 0x42, 0xba, 0xa4, 0xac, 0xc2, 0x82, 0x30, 0x66, 0xd3, 0xeb,
+	0x70, 0x29, 0x21, 0x09, 0x7a, 0x3f, 0x4d, 0x4f, 0xce, 0x4e, 0x4e, 0xe8, 0x3f, 0x29, 0xa0, 0x0a,
+	0x1f, 0x00, 0xe8, 0x0b, 0x50, 0x23, 0xb9, 0x9b, 0x9e, 0x3d, 0xab, 0xa5, 0xd5, 0xb3, 0xbf, 0x18,
+	0x28, 0x0a, 0x8b, 0x14, 0xb4, 0x05, 0x85, 0x31, 0x39, 0x25, 0xe3, 0xa8, 0x65, 0xaf, 0x4b, 0xdc,
+	0x2e, 0x9d, 0xc3, 0x1c, 0x42, 0xd3, 0x28, 0x9a, 0x18, 0x92, 0xc7, 0xbc, 0x4d, 0x17, 0xb1, 0x68,
+	0xd2, 0x7f, 0x57, 0xa0, 0x22, 0x7f, 0xea, 0xd0, 0x67, 0xbe, 0x4b, 0x1e, 0xf1, 0x8b, 0xa5, 0x06,
+	0x4d, 0x49, 0x7d, 0xe6, 0xf7, 0x44, 0x0c, 0x7d, 0xe6, 0xc7, 0x48, 0xe8, 0x36, 0xac, 0xba, 0xe4,
+	0x11, 0x17, 0x9d, 0xbb, 0xc9, 0xa6, 0x36, 0xfa, 0x5e, 0x0c, 0xd4, 0xce, 0x60, 0x89, 0xd6, 0x40,
+	0xc9, 0x8f, 0x36, 0xfd, 0x23, 0x28, 0xc7, 0x96, 0xa7, 0x9f, 0xe8, 0xb3, 0xe5, 0xa3, 0xb2, 0xc2,
+	0xef, 0x44, 0xb2, 0xea, 0xfb, 0xb0, 0x1a, 0x5f, 0x90, 0xbe, 0x17, 0xe7, 0x0b, 0x46, 0xa4, 0x85,
+	0x41, 0xae, 0x55, 0xd9, 0x44, 0xad, 0xda, 0xba, 0x09, 0xaa, 0xf0, 0xc3, 0x01, 0x5a, 0x81, 0x7c,
+	0xb7, 0xf3, 0x95, 0x51, 0xc9, 0xa0, 0x35, 0x50, 0xb1, 0xb1, 0xdf, 0xad, 0x1f, 0x1c, 0x36, 0xfa,
+	0xfd, 0x61, 0x45, 0x41, 0x08, 0x56, 0x23, 0x43, 0xbb, 0x33, 0x18, 0xf6, 0xf1, 0x41, 0x25, 0xbb,
+	0xf5, 0x80, 0x3d, 0x3a, 0xe7, 0xdd, 0xb2, 0x0c, 0x45, 0x6c, 0x7c, 0x79, 0xcf, 0x18, 0x0c, 0x8d,
+	0x56, 0x25, 0x83, 0x4a, 0xb0, 0x52, 0x6f, 0x36, 0x8d, 0x7d, 0x3a, 0x52, 0xe8, 0x08, 0x1b, 0x77,
+	0x8c, 0x26, 0x1d, 0x65, 0x51, 0x0d, 0xae, 0x36, 0xfb, 0x7b, 0x7b, 0x9d, 0xe1, 0xd0, 0x68, 0x1d,
+	0x0e, 0xfb, 0x87, 0xad, 0x0e, 0x36, 0x9a, 0xd4, 0xeb, 0x61, 0xa3, 0xdb, 0x6f, 0xde, 0xad, 0xe4,
+	0xb6, 0xde, 0x83, 0x02, 0x0b, 0x07, 0xba, 0xa7, 0x4e, 0x6f, 0xb7, 0x5f, 0xc9, 0x20, 0x15, 0x96,
+	0xef, 0xd7, 0x71, 0xaf, 0xd3, 0xbb, 0x5d, 0x51, 0x50, 0x11, 0x0a, 0x06, 0xc6, 0x7d, 0x5c, 0xc9,
+	0x6e, 0x19, 0xb0, 0x26, 0x45, 0x1d, 0x85, 0xde, 0x36, 0x7a, 0x06, 0xae, 0x77, 0x39, 0x6f, 0x30,
+	0xac, 0x63, 0xbe, 0x0f, 0x80, 0xa5, 0xc1, 0x41, 0xaf, 0xc9, 0x76, 0x51, 0x82, 0x95, 0x41, 0xfb,
+	0xde, 0xb0, 0xd5, 0xbf, 0xdf, 0xab, 0xe4, 0x1a, 0xf5, 0x3f, 0x9f, 0x55, 0x95, 0xbf, 0x9e, 0x55,
+	0x95, 0x7f, 0x9f, 0x55, 0x95, 0x1f, 0xfe, 0xab, 0x66, 0xa0, 0x66, 0x79, 0x93, 0x6d, 0xfe, 0x33,
+	0x49, 0xf4, 0xc7, 0x8e, 0xdf, 0xff, 0x83, 0xf8, 0x0f, 0x4c, 0xa3, 0x25, 0xf6, 0xce, 0xfd, 0xe0,
+	0xff, 0x01, 0x00, 0x5b, 0x50, 0xe4, 0x7f, 0x9a, 0x12, 0x00, 0x00,
 }
 
 func (m *FactomEvent) Marshal() (dAtA []byte, err error) {
@@ -1582,6 +1766,11 @@ func (m *FactomEvent) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 			}
 		}
 	}
+	if m.Sequence != 0 {
+		i = encodeVarintFactomEvents(dAtA, i, uint64(m.Sequence))
+		i--
+		dAtA[i] = 0x60
+	}
 	if len(m.IdentityChainID) > 0 {
 		i -= len(m.IdentityChainID)
 		copy(dAtA[i:], m.IdentityChainID)
@@ -1772,6 +1961,48 @@ func (m *FactomEvent_DirectoryBlockAnchor) MarshalToSizedBuffer(dAtA []byte) (in
 	}
 	return len(dAtA) - i, nil
 }
+func (m *FactomEvent_FactoidTransaction) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *FactomEvent_FactoidTransaction) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.FactoidTransaction != nil {
+		{
+			size, err := m.FactoidTransaction.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintFactomEvents(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x6a
+	}
+	return len(dAtA) - i, nil
+}
+func (m *FactomEvent_BalanceChange) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *FactomEvent_BalanceChange) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.BalanceChange != nil {
+		{
+			size, err := m.BalanceChange.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintFactomEvents(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x72
+	}
+	return len(dAtA) - i, nil
+}
 func (m *ChainCommit) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -2139,6 +2370,111 @@ func (m *DirectoryBlockCommit) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *FactoidTransaction) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *FactoidTransaction) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *FactoidTransaction) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.Transaction != nil {
+		{
+			size, err := m.Transaction.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintFactomEvents(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.EntityState != 0 {
+		i = encodeVarintFactomEvents(dAtA, i, uint64(m.EntityState))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *BalanceChange) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *BalanceChange) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *BalanceChange) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.BlockHeight != 0 {
+		i = encodeVarintFactomEvents(dAtA, i, uint64(m.BlockHeight))
+		i--
+		dAtA[i] = 0x28
+	}
+	if len(m.TransactionID) > 0 {
+		i -= len(m.TransactionID)
+		copy(dAtA[i:], m.TransactionID)
+		i = encodeVarintFactomEvents(dAtA, i, uint64(len(m.TransactionID)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if m.Change != 0 {
+		i = encodeVarintFactomEvents(dAtA, i, uint64(m.Change))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.EntryCredit {
+		i--
+		if m.EntryCredit {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Address) > 0 {
+		i -= len(m.Address)
+		copy(dAtA[i:], m.Address)
+		i = encodeVarintFactomEvents(dAtA, i, uint64(len(m.Address)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func (m *EntryCreditBlock) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -2728,6 +3064,9 @@ func (m *FactomEvent) Size() (n int) {
 	if m.Event != nil {
 		n += m.Event.Size()
 	}
+	if m.Sequence != 0 {
+		n += 1 + sovFactomEvents(uint64(m.Sequence))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -2830,6 +3169,30 @@ func (m *FactomEvent_DirectoryBlockAnchor) Size() (n int) {
 	}
 	return n
 }
+func (m *FactomEvent_FactoidTransaction) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.FactoidTransaction != nil {
+		l = m.FactoidTransaction.Size()
+		n += 1 + l + sovFactomEvents(uint64(l))
+	}
+	return n
+}
+func (m *FactomEvent_BalanceChange) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.BalanceChange != nil {
+		l = m.BalanceChange.Size()
+		n += 1 + l + sovFactomEvents(uint64(l))
+	}
+	return n
+}
 func (m *ChainCommit) Size() (n int) {
 	if m == nil {
 		return 0
@@ -2997,6 +3360,54 @@ func (m *DirectoryBlockCommit) Size() (n int) {
 	return n
 }
 
+func (m *FactoidTransaction) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.EntityState != 0 {
+		n += 1 + sovFactomEvents(uint64(m.EntityState))
+	}
+	if m.Transaction != nil {
+		l = m.Transaction.Size()
+		n += 1 + l + sovFactomEvents(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *BalanceChange) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Address)
+	if l > 0 {
+		n += 1 + l + sovFactomEvents(uint64(l))
+	}
+	if m.EntryCredit {
+		n += 2
+	}
+	if m.Change != 0 {
+		n += 1 + sovFactomEvents(uint64(m.Change))
+	}
+	l = len(m.TransactionID)
+	if l > 0 {
+		n += 1 + l + sovFactomEvents(uint64(l))
+	}
+	if m.BlockHeight != 0 {
+		n += 1 + sovFactomEvents(uint64(m.BlockHeight))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
 func (m *EntryCreditBlock) Size() (n int) {
 	if m == nil {
 		return 0
@@ -3674,6 +4085,95 @@ func (m *FactomEvent) Unmarshal(dAtA []byte) error {
 			}
 			m.Event = &FactomEvent_DirectoryBlockAnchor{v}
 			iNdEx = postIndex
+		case 12:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sequence", wireType)
+			}
+			m.Sequence = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFactomEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Sequence |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FactoidTransaction", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFactomEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &FactoidTransaction{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Event = &FactomEvent_FactoidTransaction{v}
+			iNdEx = postIndex
+		case 14:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BalanceChange", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFactomEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &BalanceChange{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Event = &FactomEvent_BalanceChange{v}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipFactomEvents(dAtA[iNdEx:])
@@ -4802,6 +5302,295 @@ func (m *DirectoryBlockCommit) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *FactoidTransaction) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowFactomEvents
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: FactoidTransaction: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: FactoidTransaction: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EntityState", wireType)
+			}
+			m.EntityState = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFactomEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.EntityState |= EntityState(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Transaction", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFactomEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Transaction == nil {
+				m.Transaction = &Transaction{}
+			}
+			if err := m.Transaction.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipFactomEvents(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *BalanceChange) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowFactomEvents
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: BalanceChange: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: BalanceChange: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Address", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFactomEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Address = append(m.Address[:0], dAtA[iNdEx:postIndex]...)
+			if m.Address == nil {
+				m.Address = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EntryCredit", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFactomEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.EntryCredit = bool(v != 0)
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Change", wireType)
+			}
+			m.Change = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFactomEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Change |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TransactionID", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFactomEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TransactionID = append(m.TransactionID[:0], dAtA[iNdEx:postIndex]...)
+			if m.TransactionID == nil {
+				m.TransactionID = []byte{}
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BlockHeight", wireType)
+			}
+			m.BlockHeight = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFactomEvents
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.BlockHeight |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipFactomEvents(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthFactomEvents
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func (m *EntryCreditBlock) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
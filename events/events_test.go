@@ -10,12 +10,14 @@ import (
 	"github.com/FactomProject/factomd/common/directoryBlock/dbInfo"
 	"github.com/FactomProject/factomd/common/entryBlock"
 	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/factoid"
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/messages"
 	"github.com/FactomProject/factomd/common/primitives"
 	"github.com/FactomProject/factomd/database/databaseOverlay"
 	"github.com/FactomProject/factomd/events/eventconfig"
 	"github.com/FactomProject/factomd/events/eventmessages/generated/eventmessages"
+	"github.com/FactomProject/factomd/events/eventservices"
 	"github.com/FactomProject/factomd/testHelper"
 	"github.com/stretchr/testify/assert"
 
@@ -50,7 +52,7 @@ func TestUpdateState(t *testing.T) {
 	progress := s.DBStates.UpdateState()
 
 	assert.True(t, progress)
-	if assert.Equal(t, 1, len(eventQueue)) {
+	if assert.Equal(t, 2, len(eventQueue)) {
 		event := <-eventQueue
 		assert.Equal(t, eventmessages.EventSource_REPLAY_BOOT, event.GetEventSource())
 		assert.Equal(t, s.IdentityChainID.Bytes(), event.IdentityChainID)
@@ -62,6 +64,21 @@ func TestUpdateState(t *testing.T) {
 			assert.NotNil(t, stateChangeEvent.DirectoryBlock)
 			assert.NotNil(t, stateChangeEvent.FactoidBlock)
 			assert.NotNil(t, stateChangeEvent.EntryCreditBlock)
+			assertFactoidTransactionEvents(t, eventQueue, stateChangeEvent)
+		}
+	}
+}
+
+// assertFactoidTransactionEvents checks that the directory block commit is followed by an event
+// for each transaction of its factoid block
+func assertFactoidTransactionEvents(t *testing.T, eventQueue chan *eventmessages.FactomEvent, directoryBlockCommit *eventmessages.DirectoryBlockCommit) {
+	factoidBlock := directoryBlockCommit.GetFactoidBlock()
+	for i := uint32(0); i < factoidBlock.GetTransactionCount(); i++ {
+		event := <-eventQueue
+		factoidTransaction := event.GetFactoidTransaction()
+		if assert.NotNil(t, factoidTransaction, "event received has wrong type: %s event: %+v", reflect.TypeOf(event.GetEvent()), event) {
+			assert.Equal(t, eventmessages.EntityState_COMMITTED_TO_DIRECTORY_BLOCK, factoidTransaction.GetEntityState())
+			assert.Equal(t, factoidBlock.GetBlockHeight(), factoidTransaction.GetTransaction().GetBlockHeight())
 		}
 	}
 }
@@ -150,6 +167,50 @@ func TestAddToProcessList(t *testing.T) {
 	}
 }
 
+func TestAddFactoidTransactionToProcessList(t *testing.T) {
+	eventQueue := make(chan *eventmessages.FactomEvent, 5000)
+	mockSender := &mockEventSender{
+		eventsOutQueue:      eventQueue,
+		replayDuringStartup: true,
+		sendStateChange:     true,
+	}
+
+	s := testHelper.CreateAndPopulateTestState()
+	s.EventService.ConfigSender(s, mockSender)
+	s.SetLeaderTimestamp(primitives.NewTimestampNow())
+
+	transaction := new(factoid.Transaction)
+	transaction.AddInput(factoid.NewAddress(primitives.Sha([]byte("from")).Bytes()), 2000)
+	transaction.AddOutput(factoid.NewAddress(primitives.Sha([]byte("to")).Bytes()), 1000)
+	transaction.SetTimestamp(primitives.NewTimestampNow())
+	msg := &messages.FactoidTransaction{Transaction: transaction}
+
+	ack := new(messages.Ack)
+	ack.Timestamp = msg.GetTimestamp()
+	ack.LeaderChainID = msg.GetLeaderChainID()
+	ack.MessageHash = msg.GetMsgHash()
+	ack.SerialHash = primitives.RandomHash()
+	ack.Minute = 2
+
+	processList := s.ProcessLists.Get(s.LLeaderHeight)
+	ack.DBHeight = processList.DBHeight
+	processList.AddToProcessList(s, ack, msg)
+
+	// assertions
+	if assert.Equal(t, 1, len(eventQueue)) {
+		event := <-eventQueue
+		assert.Equal(t, eventmessages.EventSource_REPLAY_BOOT, event.GetEventSource())
+
+		factoidTransaction := event.GetFactoidTransaction()
+		if assert.NotNil(t, factoidTransaction, "event received has wrong type: %s event: %+v", reflect.TypeOf(event.GetEvent()), event) {
+			assert.Equal(t, eventmessages.EntityState_ACCEPTED, factoidTransaction.GetEntityState())
+			assert.Equal(t, transaction.GetSigHash().Bytes(), factoidTransaction.GetTransaction().GetTransactionID())
+			assert.Equal(t, processList.DBHeight, factoidTransaction.GetTransaction().GetBlockHeight())
+			assert.Equal(t, uint32(3), factoidTransaction.GetTransaction().GetMinuteNumber())
+		}
+	}
+}
+
 func TestEmitDirectoryBlockEventsFromHeightRange(t *testing.T) {
 	eventQueue := make(chan *eventmessages.FactomEvent, 5000)
 	mockSender := &mockEventSender{
@@ -164,7 +225,7 @@ func TestEmitDirectoryBlockEventsFromHeightRange(t *testing.T) {
 
 	s.EmitDirectoryBlockEventsFromHeight(0, 7)
 
-	if assert.Equal(t, 8, len(eventQueue)) {
+	if assert.Equal(t, 24, len(eventQueue)) {
 		for i := 0; i < 8; i++ {
 			event := <-eventQueue
 			assert.Equal(t, eventmessages.EventSource_REPLAY_BOOT, event.GetEventSource())
@@ -178,6 +239,7 @@ func TestEmitDirectoryBlockEventsFromHeightRange(t *testing.T) {
 				assert.NotNil(t, directoryBlockCommit.GetFactoidBlock())
 				assert.NotNil(t, directoryBlockCommit.GetEntryBlocks())
 				assert.NotNil(t, directoryBlockCommit.GetEntryBlockEntries())
+				assertFactoidTransactionEvents(t, eventQueue, directoryBlockCommit)
 			}
 		}
 	}
@@ -197,7 +259,7 @@ func TestEmitDirectoryBlockStateEventsFromHeight(t *testing.T) {
 
 	s.EmitDirectoryBlockEventsFromHeight(3, 100)
 
-	if assert.Equal(t, 7, len(eventQueue)) {
+	if assert.Equal(t, 21, len(eventQueue)) {
 		for i := 0; i < 7; i++ {
 			event := <-eventQueue
 			assert.Equal(t, eventmessages.EventSource_REPLAY_BOOT, event.GetEventSource())
@@ -211,11 +273,75 @@ func TestEmitDirectoryBlockStateEventsFromHeight(t *testing.T) {
 				assert.NotNil(t, directoryBlockCommit.GetFactoidBlock())
 				assert.NotNil(t, directoryBlockCommit.GetEntryBlocks())
 				assert.NotNil(t, directoryBlockCommit.GetEntryBlockEntries())
+				assertFactoidTransactionEvents(t, eventQueue, directoryBlockCommit)
 			}
 		}
 	}
 }
 
+func TestEmitBalanceChanges(t *testing.T) {
+	eventQueue := make(chan *eventmessages.FactomEvent, 5000)
+	mockSender := &mockEventSender{
+		eventsOutQueue:      eventQueue,
+		replayDuringStartup: true,
+		sendBalanceChanges:  true,
+	}
+
+	s := testHelper.CreateAndPopulateTestState()
+	s.EventService.ConfigSender(s, mockSender)
+	s.RunLeader = true
+
+	s.EmitDirectoryBlockEventsFromHeight(2, 2)
+
+	var transactions, balanceChanges int
+	for len(eventQueue) > 0 {
+		event := <-eventQueue
+		switch value := event.Event.(type) {
+		case *eventmessages.FactomEvent_FactoidTransaction:
+			transactions++
+		case *eventmessages.FactomEvent_BalanceChange:
+			balanceChanges++
+			assert.Equal(t, uint32(2), value.BalanceChange.GetBlockHeight())
+			assert.NotEqual(t, int64(0), value.BalanceChange.GetChange())
+		}
+	}
+	assert.Equal(t, 2, transactions)
+	assert.True(t, balanceChanges >= transactions, "%d balance changes", balanceChanges)
+}
+
+func TestStartLiveFeedReplay(t *testing.T) {
+	liveQueue := make(chan *eventmessages.FactomEvent, 5000)
+	liveSender := &mockEventSender{eventsOutQueue: liveQueue}
+	// the replay waits for room in the queue, so a small one loses no events
+	historyQueue := make(chan *eventmessages.FactomEvent, 2)
+	historySender := &mockEventSender{name: "history", eventsOutQueue: historyQueue}
+
+	s := testHelper.CreateAndPopulateTestState()
+	s.EventService.ConfigSender(s, liveSender, historySender)
+	s.DBFinished = true
+
+	assert.Error(t, s.StartLiveFeedReplay("other", 2, 4, 0))
+	assert.Error(t, s.StartLiveFeedReplay("history", 4, 2, 0))
+	if !assert.NoError(t, s.StartLiveFeedReplay("history", 2, 4, 1000)) {
+		return
+	}
+
+	for height := uint32(2); height <= 4; height++ {
+		select {
+		case event := <-historyQueue:
+			assert.Equal(t, eventmessages.EventSource_REPLAY_HISTORY, event.GetEventSource())
+			directoryBlockCommit := event.GetDirectoryBlockCommit()
+			if assert.NotNil(t, directoryBlockCommit, "event received has wrong type: %s event: %+v", reflect.TypeOf(event.GetEvent()), event) {
+				assert.Equal(t, height, directoryBlockCommit.GetDirectoryBlock().GetHeader().GetBlockHeight())
+				assertFactoidTransactionEvents(t, historyQueue, directoryBlockCommit)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no directory block commit for height %d", height)
+		}
+	}
+	assert.Empty(t, liveQueue)
+}
+
 func TestEmitDirectoryBlockAnchorEvent(t *testing.T) {
 	eventQueue := make(chan *eventmessages.FactomEvent, 5000)
 	mockSender := &mockEventSender{
@@ -347,24 +473,38 @@ func TestEmitProcessListEvent(t *testing.T) {
 }
 
 type mockEventSender struct {
+	name                string
 	eventsOutQueue      chan *eventmessages.FactomEvent
 	broadcastContent    eventconfig.BroadcastContent
 	sendStateChange     bool
+	sendBalanceChanges  bool
 	replayDuringStartup bool
 }
 
+func (m *mockEventSender) GetName() string {
+	return m.name
+}
 func (m *mockEventSender) GetBroadcastContent() eventconfig.BroadcastContent {
 	return m.broadcastContent
 }
 func (m *mockEventSender) IsSendStateChangeEvents() bool {
 	return m.sendStateChange
 }
+func (m *mockEventSender) IsSendBalanceChanges() bool {
+	return m.sendBalanceChanges
+}
 func (m *mockEventSender) ReplayDuringStartup() bool {
 	return m.replayDuringStartup
 }
 func (m *mockEventSender) GetEventQueue() chan *eventmessages.FactomEvent {
 	return m.eventsOutQueue
 }
+func (m *mockEventSender) Stamp(event *eventmessages.FactomEvent) *eventmessages.FactomEvent {
+	return event
+}
+func (m *mockEventSender) GetFilter() *eventservices.EventFilter {
+	return nil
+}
 func (m *mockEventSender) Shutdown() {}
 
 func (m *mockEventSender) IncreaseDroppedFromQueueCounter() {}
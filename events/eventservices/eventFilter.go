@@ -0,0 +1,160 @@
+package eventservices
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/events/eventmessages/generated/eventmessages"
+)
+
+// eventTypes are the names of the events in the FactomEvent oneof
+var eventTypes = []string{
+	"chainCommit",
+	"entryCommit",
+	"entryReveal",
+	"stateChange",
+	"directoryBlockCommit",
+	"processListEvent",
+	"nodeMessage",
+	"directoryBlockAnchor",
+	"factoidTransaction",
+	"balanceChange",
+}
+
+// EventFilter selects the events sent to a receiver. A filter without event types lets all the
+// types through, and one without chain IDs all the chains
+type EventFilter struct {
+	EventTypes map[string]bool
+	ChainIDs   [][]byte
+
+	// chainIDHashes are the double sha of the chain IDs, that chain commits carry
+	chainIDHashes [][]byte
+}
+
+// ParseEventFilter parses the comma separated event types and hex chain IDs of a receiver
+func ParseEventFilter(types string, chainIDs string) (*EventFilter, error) {
+	filter := new(EventFilter)
+	for _, name := range strings.Split(types, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		eventType := EventType(name)
+		if eventType == "" {
+			return nil, errors.New(fmt.Sprintf("unknown event type %s, expected one of %s", name, strings.Join(eventTypes, ", ")))
+		}
+		if filter.EventTypes == nil {
+			filter.EventTypes = make(map[string]bool)
+		}
+		filter.EventTypes[eventType] = true
+	}
+	for _, id := range strings.Split(chainIDs, ",") {
+		if id = strings.TrimSpace(id); id == "" {
+			continue
+		}
+		chainID, err := hex.DecodeString(id)
+		if err != nil || len(chainID) != 32 {
+			return nil, errors.New(fmt.Sprintf("could not parse %s to a chain ID", id))
+		}
+		filter.ChainIDs = append(filter.ChainIDs, chainID)
+		filter.chainIDHashes = append(filter.chainIDHashes, primitives.DoubleSha(chainID))
+	}
+	return filter, nil
+}
+
+// EventType returns the name of the event type, in the case of the FactomEvent oneof, or "" if
+// there is no such type
+func EventType(name string) string {
+	for _, eventType := range eventTypes {
+		if strings.EqualFold(name, eventType) {
+			return eventType
+		}
+	}
+	return ""
+}
+
+// eventTypeOf returns the name of the type of the event
+func eventTypeOf(event *eventmessages.FactomEvent) string {
+	switch event.Event.(type) {
+	case *eventmessages.FactomEvent_ChainCommit:
+		return "chainCommit"
+	case *eventmessages.FactomEvent_EntryCommit:
+		return "entryCommit"
+	case *eventmessages.FactomEvent_EntryReveal:
+		return "entryReveal"
+	case *eventmessages.FactomEvent_StateChange:
+		return "stateChange"
+	case *eventmessages.FactomEvent_DirectoryBlockCommit:
+		return "directoryBlockCommit"
+	case *eventmessages.FactomEvent_ProcessListEvent:
+		return "processListEvent"
+	case *eventmessages.FactomEvent_NodeMessage:
+		return "nodeMessage"
+	case *eventmessages.FactomEvent_DirectoryBlockAnchor:
+		return "directoryBlockAnchor"
+	case *eventmessages.FactomEvent_FactoidTransaction:
+		return "factoidTransaction"
+	case *eventmessages.FactomEvent_BalanceChange:
+		return "balanceChange"
+	}
+	return ""
+}
+
+// Apply returns the event to send to the receiver, or nil if the filter drops it. With chain IDs,
+// chain commits and entry reveals are only sent for the chains, and entry commits and state
+// changes, that do not carry a chain, are dropped. Directory block commits are sent with only the
+// entry blocks and entries of the chains, in a copy as the event is shared with other receivers
+func (filter *EventFilter) Apply(event *eventmessages.FactomEvent) *eventmessages.FactomEvent {
+	if filter == nil || event == nil {
+		return event
+	}
+	if filter.EventTypes != nil && !filter.EventTypes[eventTypeOf(event)] {
+		return nil
+	}
+	if len(filter.ChainIDs) == 0 {
+		return event
+	}
+
+	switch value := event.Event.(type) {
+	case *eventmessages.FactomEvent_ChainCommit:
+		if !containsBytes(filter.chainIDHashes, value.ChainCommit.GetChainIDHash()) {
+			return nil
+		}
+	case *eventmessages.FactomEvent_EntryReveal:
+		if !containsBytes(filter.ChainIDs, value.EntryReveal.GetEntry().GetChainID()) {
+			return nil
+		}
+	case *eventmessages.FactomEvent_EntryCommit, *eventmessages.FactomEvent_StateChange:
+		return nil
+	case *eventmessages.FactomEvent_DirectoryBlockCommit:
+		commit := *value.DirectoryBlockCommit
+		commit.EntryBlocks = nil
+		for _, entryBlock := range value.DirectoryBlockCommit.GetEntryBlocks() {
+			if containsBytes(filter.ChainIDs, entryBlock.GetHeader().GetChainID()) {
+				commit.EntryBlocks = append(commit.EntryBlocks, entryBlock)
+			}
+		}
+		commit.EntryBlockEntries = nil
+		for _, entry := range value.DirectoryBlockCommit.GetEntryBlockEntries() {
+			if containsBytes(filter.ChainIDs, entry.GetChainID()) {
+				commit.EntryBlockEntries = append(commit.EntryBlockEntries, entry)
+			}
+		}
+		filtered := *event
+		filtered.Event = &eventmessages.FactomEvent_DirectoryBlockCommit{DirectoryBlockCommit: &commit}
+		return &filtered
+	}
+	return event
+}
+
+func containsBytes(list [][]byte, value []byte) bool {
+	for _, item := range list {
+		if bytes.Equal(item, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package eventservices
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/events/eventmessages/generated/eventmessages"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseEventFilter(t *testing.T) {
+	filter, err := ParseEventFilter("", "")
+	assert.NoError(t, err)
+	assert.Nil(t, filter.EventTypes)
+	assert.Nil(t, filter.ChainIDs)
+
+	filter, err = ParseEventFilter(" EntryReveal,nodemessage ,", "0102030405060708091011121314151617181920212223242526272829303132")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"entryReveal": true, "nodeMessage": true}, filter.EventTypes)
+	assert.Len(t, filter.ChainIDs, 1)
+
+	filter, err = ParseEventFilter("factoidTransaction,balancechange", "")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"factoidTransaction": true, "balanceChange": true}, filter.EventTypes)
+
+	_, err = ParseEventFilter("entries", "")
+	assert.Error(t, err)
+	_, err = ParseEventFilter("", "0102")
+	assert.Error(t, err)
+	_, err = ParseEventFilter("", "not hex")
+	assert.Error(t, err)
+}
+
+func TestEventFilter_Apply(t *testing.T) {
+	chainID := bytes.Repeat([]byte{1}, 32)
+	otherChainID := bytes.Repeat([]byte{2}, 32)
+
+	reveal := func(chainID []byte) *eventmessages.FactomEvent {
+		return &eventmessages.FactomEvent{Event: &eventmessages.FactomEvent_EntryReveal{EntryReveal: &eventmessages.EntryReveal{
+			Entry: &eventmessages.EntryBlockEntry{ChainID: chainID},
+		}}}
+	}
+	chainCommit := func(chainID []byte) *eventmessages.FactomEvent {
+		return &eventmessages.FactomEvent{Event: &eventmessages.FactomEvent_ChainCommit{ChainCommit: &eventmessages.ChainCommit{
+			ChainIDHash: primitives.DoubleSha(chainID),
+		}}}
+	}
+	nodeMessage := &eventmessages.FactomEvent{Event: &eventmessages.FactomEvent_NodeMessage{NodeMessage: &eventmessages.NodeMessage{}}}
+	entryCommit := &eventmessages.FactomEvent{Event: &eventmessages.FactomEvent_EntryCommit{EntryCommit: &eventmessages.EntryCommit{}}}
+
+	var none *EventFilter
+	assert.Equal(t, nodeMessage, none.Apply(nodeMessage))
+
+	filter := &EventFilter{EventTypes: map[string]bool{"entryReveal": true}}
+	assert.Nil(t, filter.Apply(nodeMessage))
+	assert.NotNil(t, filter.Apply(reveal(otherChainID)))
+
+	filter, _ = ParseEventFilter("", "0101010101010101010101010101010101010101010101010101010101010101")
+	assert.NotNil(t, filter.Apply(reveal(chainID)))
+	assert.Nil(t, filter.Apply(reveal(otherChainID)))
+	assert.NotNil(t, filter.Apply(chainCommit(chainID)))
+	assert.Nil(t, filter.Apply(chainCommit(otherChainID)))
+	assert.Nil(t, filter.Apply(entryCommit))
+	assert.Equal(t, nodeMessage, filter.Apply(nodeMessage))
+
+	commit := &eventmessages.DirectoryBlockCommit{
+		EntryBlocks: []*eventmessages.EntryBlock{
+			{Header: &eventmessages.EntryBlockHeader{ChainID: chainID}},
+			{Header: &eventmessages.EntryBlockHeader{ChainID: otherChainID}},
+		},
+		EntryBlockEntries: []*eventmessages.EntryBlockEntry{
+			{ChainID: otherChainID},
+			{ChainID: chainID},
+			{ChainID: chainID},
+		},
+	}
+	event := &eventmessages.FactomEvent{FactomNodeName: "node", Event: &eventmessages.FactomEvent_DirectoryBlockCommit{DirectoryBlockCommit: commit}}
+	filtered := filter.Apply(event)
+	if assert.NotNil(t, filtered) {
+		assert.Equal(t, "node", filtered.FactomNodeName)
+		assert.Len(t, filtered.GetDirectoryBlockCommit().EntryBlocks, 1)
+		assert.Len(t, filtered.GetDirectoryBlockCommit().EntryBlockEntries, 2)
+	}
+
+	// The shared event is left as it is
+	assert.Len(t, commit.EntryBlocks, 2)
+	assert.Len(t, commit.EntryBlockEntries, 3)
+}
@@ -38,6 +38,12 @@ func MapToFactomEvent(eventInput eventinput.EventInput, broadcastContent eventco
 	case *eventinput.NodeMessageEvent:
 		nodeMessageEvent := eventInput.(*eventinput.NodeMessageEvent)
 		return mapNodeMessageEvent(nodeMessageEvent)
+	case *eventinput.FactoidTransactionEvent:
+		factoidTransactionEvent := eventInput.(*eventinput.FactoidTransactionEvent)
+		return mapFactoidTransactionEvent(factoidTransactionEvent)
+	case *eventinput.BalanceChangeEvent:
+		balanceChangeEvent := eventInput.(*eventinput.BalanceChangeEvent)
+		return mapBalanceChangeEvent(balanceChangeEvent)
 	default:
 		return nil, errors.New("no payload found in source event")
 	}
@@ -163,6 +169,26 @@ func mapNodeMessageEvent(nodeMessageEvent *eventinput.NodeMessageEvent) (*eventm
 	return event, nil
 }
 
+func mapFactoidTransactionEvent(factoidTransactionEvent *eventinput.FactoidTransactionEvent) (*eventmessages.FactomEvent, error) {
+	transaction := factoidTransactionEvent.GetPayload()
+	if transaction == nil {
+		return nil, errors.New("no transaction found in factoid transaction event")
+	}
+	event := &eventmessages.FactomEvent{
+		EventSource: factoidTransactionEvent.GetStreamSource(),
+		Event:       mapFactoidTransaction(factoidTransactionEvent.GetEntityState(), transaction, factoidTransactionEvent.BlockHeight, factoidTransactionEvent.MinuteNumber),
+	}
+	return event, nil
+}
+
+func mapBalanceChangeEvent(balanceChangeEvent *eventinput.BalanceChangeEvent) (*eventmessages.FactomEvent, error) {
+	event := &eventmessages.FactomEvent{
+		EventSource: balanceChangeEvent.GetStreamSource(),
+		Event:       mapBalanceChange(balanceChangeEvent.Address, balanceChangeEvent.EntryCredit, balanceChangeEvent.Change, balanceChangeEvent.TransactionID, balanceChangeEvent.BlockHeight),
+	}
+	return event, nil
+}
+
 func mapDBStateFromMsg(dbStateMessage *messages.DBStateMsg, shouldIncludeContent bool) *eventmessages.FactomEvent_DirectoryBlockCommit {
 	event := &eventmessages.FactomEvent_DirectoryBlockCommit{DirectoryBlockCommit: &eventmessages.DirectoryBlockCommit{
 		DirectoryBlock:    mapDirectoryBlock(dbStateMessage.DirectoryBlock),
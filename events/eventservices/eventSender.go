@@ -6,8 +6,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"reflect"
+	"sync"
 	"time"
 
 	"github.com/FactomProject/factomd/common/globals"
@@ -19,29 +21,39 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
-var eventSenderInstance *eventSender
+// eventSenderInstances are the senders by receiver name, shared by the nodes of a simulation
+var (
+	eventSenderInstances     = make(map[string]*eventSender)
+	eventSenderInstancesLock sync.Mutex
+)
 
 const (
 	defaultProtocol       = "tcp"
 	defaultConnectionHost = "127.0.0.1"
 	defaultConnectionPort = 8040
 	defaultOutputFormat   = eventconfig.Protobuf
+	defaultSpoolSize      = 100000
 	protocolVersion       = byte(1)
 )
 
 var (
 	dialRetryPostponeDuration = 5 * time.Minute
 	redialSleepDuration       = 10 * time.Second
+	resumeRequestTimeout      = 30 * time.Second
 	sendRetries               = 3
 )
 
 type EventSender interface {
 	// Send(event eventinput.EventInput) error
+	GetName() string
 	GetBroadcastContent() eventconfig.BroadcastContent
 	Shutdown()
 	IsSendStateChangeEvents() bool
+	IsSendBalanceChanges() bool
 	ReplayDuringStartup() bool
 	GetEventQueue() chan *eventmessages.FactomEvent
+	GetFilter() *EventFilter
+	Stamp(event *eventmessages.FactomEvent) *eventmessages.FactomEvent
 	IncreaseDroppedFromQueueCounter()
 }
 
@@ -52,31 +64,71 @@ type eventSender struct {
 	connection              net.Conn
 	droppedFromQueueCounter prometheus.Counter
 	notSentCounter          prometheus.Counter
+	eventSequencer
+	lastSent uint64 // sequence number of the last event the receiver got
+}
+
+// eventSequencer numbers the events of a receiver, and keeps them in its spool if it has one
+type eventSequencer struct {
+	sequenceLock sync.Mutex
+	sequence     uint64
+	spool        *eventSpool
 }
 
-func NewEventSender(config *util.FactomdConfig, factomParams *globals.FactomParams) EventSender {
-	return NewEventSenderTo(selectParameters(factomParams, config))
+// NewEventSender returns a sender for the [LiveFeedAPI] receiver, or an error if its filter can
+// not be parsed
+func NewEventSender(config *util.FactomdConfig, factomParams *globals.FactomParams) (EventSender, error) {
+	params, err := selectParameters(factomParams, config)
+	if err != nil {
+		return nil, err
+	}
+	return NewEventSenderTo(params), nil
+}
+
+// NewEventSenders returns a sender for the [LiveFeedAPI] receiver and one for each of the
+// [LiveFeedReceiver "name"] sections, each with its own queue, so that a slow receiver does not
+// make the others drop events
+func NewEventSenders(config *util.FactomdConfig, factomParams *globals.FactomParams) []EventSender {
+	var senders []EventSender
+	for _, params := range selectAllParameters(factomParams, config) {
+		senders = append(senders, NewEventSenderTo(params))
+	}
+	return senders
 }
 
 func NewEventSenderTo(params *EventServiceParams) EventSender {
-	if eventSenderInstance == nil {
-		eventSenderInstance = &eventSender{
+	if params.Protocol == streamProtocol {
+		return newEventStream(params)
+	}
+
+	eventSenderInstancesLock.Lock()
+	defer eventSenderInstancesLock.Unlock()
+
+	instance := eventSenderInstances[params.Name]
+	if instance == nil {
+		instance = &eventSender{
 			eventsOutQueue: make(chan *eventmessages.FactomEvent, 5000),
 			params:         params,
 		}
 
-		eventSenderInstance.droppedFromQueueCounter = prometheus.NewCounter(prometheus.CounterOpts{
-			Name: "factomd_livefeed_dropped_from_queue_counter",
-			Help: "Number of times we dropped events due of a full the event queue",
+		labels := prometheus.Labels{"receiver": params.Name}
+		instance.droppedFromQueueCounter = prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        "factomd_livefeed_dropped_from_queue_counter",
+			Help:        "Number of times we dropped events due of a full the event queue",
+			ConstLabels: labels,
 		})
-		eventSenderInstance.notSentCounter = prometheus.NewCounter(prometheus.CounterOpts{
-			Name: "factomd_livefeed_not_send_counter",
-			Help: "Number of times we couldn't send out an event",
+		instance.notSentCounter = prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        "factomd_livefeed_not_send_counter",
+			Help:        "Number of times we couldn't send out an event",
+			ConstLabels: labels,
 		})
 
-		go eventSenderInstance.processEventsChannel()
+		instance.openSpool(params)
+
+		eventSenderInstances[params.Name] = instance
+		go instance.processEventsChannel()
 	}
-	return eventSenderInstance
+	return instance
 }
 
 // TODO describe choice of dropping events.
@@ -109,9 +161,19 @@ func (eventSender *eventSender) sendEvent(event *eventmessages.FactomEvent) {
 			continue
 		}
 
-		// send the factom event to the live api
-		if err = eventSender.writeEvent(data); err == nil {
+		if eventSender.spool != nil && event.Sequence <= eventSender.lastSent {
+			// the receiver resumed after this event
 			sendSuccessful = true
+			break
+		}
+
+		// send the factom event to the live api, after the spooled events the receiver missed
+		if err = eventSender.sendSpooled(event.Sequence); err == nil {
+			err = eventSender.writeEvent(data)
+		}
+		if err == nil {
+			sendSuccessful = true
+			eventSender.lastSent = event.Sequence
 		} else {
 			log.Errorf("An error occurred while sending a message to receiver %s: %v, retry %d", eventSender.params.Address, err, retry)
 
@@ -128,16 +190,40 @@ func (eventSender *eventSender) sendEvent(event *eventmessages.FactomEvent) {
 	}
 }
 
+// sendSpooled sends the spooled events between the last one the receiver got and the one with
+// the given sequence number
+func (eventSender *eventSender) sendSpooled(before uint64) error {
+	if eventSender.spool == nil || before <= eventSender.lastSent+1 {
+		return nil
+	}
+	return eventSender.spool.Read(eventSender.lastSent, before, func(event *eventmessages.FactomEvent) error {
+		data, err := eventSender.marshallMessage(event)
+		if err != nil {
+			log.Errorf("An error occurred while serializing spooled factom event %d: %v", event.Sequence, err)
+			eventSender.notSentCounter.Inc()
+		} else if err := eventSender.writeEvent(data); err != nil {
+			return err
+		}
+		eventSender.lastSent = event.Sequence
+		return nil
+	})
+}
+
 func (eventSender *eventSender) marshallMessage(event *eventmessages.FactomEvent) ([]byte, error) {
+	return MarshalEvent(event, eventSender.params.OutputFormat)
+}
+
+// MarshalEvent serializes the event in the output format
+func MarshalEvent(event *eventmessages.FactomEvent, outputFormat eventconfig.EventFormat) ([]byte, error) {
 	var data []byte
 	var err error
-	switch eventSender.params.OutputFormat {
+	switch outputFormat {
 	case eventconfig.Protobuf:
-		data, err = eventSender.marshallEvent(event)
+		data, err = marshallProtobuf(event)
 	case eventconfig.Json:
 		data, err = json.Marshal(event)
 	default:
-		return nil, errors.New("unsupported event format: " + eventSender.params.OutputFormat.String())
+		return nil, errors.New("unsupported event format: " + outputFormat.String())
 	}
 	return data, err
 }
@@ -160,12 +246,44 @@ func (eventSender *eventSender) connect() error {
 		if err != nil {
 			return fmt.Errorf("failed to connect: %v", err)
 		}
+		if eventSender.spool != nil {
+			if err = eventSender.readResumeRequest(conn); err != nil {
+				conn.Close()
+				return fmt.Errorf("failed to read resume request: %v", err)
+			}
+		}
 		eventSender.connection = conn
 		eventSender.postponeSendingUntil = time.Time{}
 	}
 	return nil
 }
 
+// readResumeRequest reads the request a receiver with a spool opens the connection with: the
+// protocol version and the sequence number of the last event it got, as a little endian uint64.
+// The events after it are sent again from the spool
+func (eventSender *eventSender) readResumeRequest(conn net.Conn) error {
+	request := make([]byte, 9)
+	conn.SetReadDeadline(time.Now().Add(resumeRequestTimeout))
+	_, err := io.ReadFull(conn, request)
+	conn.SetReadDeadline(time.Time{})
+	if err != nil {
+		return err
+	}
+	if request[0] != protocolVersion {
+		return fmt.Errorf("unsupported protocol version %d", request[0])
+	}
+
+	lastSent := binary.LittleEndian.Uint64(request[1:])
+	sequence := eventSender.lastSequence()
+	if lastSent > sequence {
+		log.Warnf("Receiver %s resumes after event %d, but the last event is %d", eventSender.params.Address, lastSent, sequence)
+		lastSent = sequence
+	}
+	log.Infof("Receiver %s resumes after event %d", eventSender.params.Address, lastSent)
+	eventSender.lastSent = lastSent
+	return nil
+}
+
 func catchConnectPanics() error {
 	if r := recover(); r != nil {
 		return errors.New(fmt.Sprintf("failed to connect to receiver: %v", r))
@@ -184,6 +302,10 @@ func (eventSender *eventSender) disconnect() {
 }
 
 func (eventSender *eventSender) marshallEvent(event *eventmessages.FactomEvent) (data []byte, err error) {
+	return marshallProtobuf(event)
+}
+
+func marshallProtobuf(event *eventmessages.FactomEvent) (data []byte, err error) {
 	data, err = proto.Marshal(event)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshell event: %v", err)
@@ -222,6 +344,10 @@ func catchSendPanics() error {
 	return nil
 }
 
+func (eventSender *eventSender) GetName() string {
+	return eventSender.params.Name
+}
+
 func (eventSender *eventSender) GetEventQueue() chan *eventmessages.FactomEvent {
 	return eventSender.eventsOutQueue
 }
@@ -234,10 +360,60 @@ func (eventSender *eventSender) IsSendStateChangeEvents() bool {
 	return eventSender.params.SendStateChangeEvents
 }
 
+func (eventSender *eventSender) IsSendBalanceChanges() bool {
+	return eventSender.params.SendBalanceChanges
+}
+
 func (eventSender *eventSender) ReplayDuringStartup() bool {
 	return eventSender.params.ReplayDuringStartup
 }
 
+func (eventSender *eventSender) GetFilter() *EventFilter {
+	return eventSender.params.Filter
+}
+
+// openSpool opens the spool of the receiver if it has one, and continues its sequence
+func (sequencer *eventSequencer) openSpool(params *EventServiceParams) {
+	if len(params.SpoolPath) == 0 {
+		return
+	}
+	spool, err := openEventSpool(params.SpoolPath, params.SpoolSize)
+	if err != nil {
+		log.Errorf("Failed to open the event spool %s, the events of receiver %s are not spooled: %v", params.SpoolPath, params.Address, err)
+		return
+	}
+	sequencer.spool = spool
+	sequencer.sequence = spool.LastSequence()
+}
+
+// Stamp returns a copy of the event with the next sequence number of the receiver, written to the
+// spool of the receiver if it has one. Events that are spooled are sent even if they are dropped
+// from the queue, which is why the spool is written here rather than by the sender. The write is
+// made on the emitting path of the state: it goes to the operating system unsynced, a few
+// microseconds an event in BenchmarkEventSpoolAppend, but a disk that blocks writes holds up the
+// emitter with it
+func (sequencer *eventSequencer) Stamp(event *eventmessages.FactomEvent) *eventmessages.FactomEvent {
+	sequencer.sequenceLock.Lock()
+	defer sequencer.sequenceLock.Unlock()
+
+	stamped := *event
+	sequencer.sequence++
+	stamped.Sequence = sequencer.sequence
+	if sequencer.spool != nil {
+		if err := sequencer.spool.Append(&stamped); err != nil {
+			log.Errorf("An error occurred while spooling factom event %d: %v", stamped.Sequence, err)
+		}
+	}
+	return &stamped
+}
+
+// lastSequence returns the sequence number of the last event that was stamped
+func (sequencer *eventSequencer) lastSequence() uint64 {
+	sequencer.sequenceLock.Lock()
+	defer sequencer.sequenceLock.Unlock()
+	return sequencer.sequence
+}
+
 func (eventSender *eventSender) IncreaseDroppedFromQueueCounter() {
 	eventSender.droppedFromQueueCounter.Inc()
 }
@@ -249,5 +425,13 @@ func (eventSender *eventSender) Shutdown() {
 	}
 	close(eventSender.eventsOutQueue)
 	eventSender.disconnect()
-	eventSenderInstance = nil
+	if eventSender.spool != nil {
+		eventSender.spool.Close()
+	}
+
+	eventSenderInstancesLock.Lock()
+	defer eventSenderInstancesLock.Unlock()
+	if eventSenderInstances[eventSender.params.Name] == eventSender {
+		delete(eventSenderInstances, eventSender.params.Name)
+	}
 }
@@ -64,6 +64,7 @@ func TestEventsService_Send(t *testing.T) {
 		OutputFormat: eventconfig.Json,
 	}
 	NewEventSenderTo(params)
+	eventSenderInstance := eventSenderInstances[""]
 
 	// set connection
 	eventSenderInstance.connection = client
@@ -2,6 +2,7 @@ package eventservices
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/FactomProject/factomd/common/globals"
 	"github.com/FactomProject/factomd/events/eventconfig"
@@ -10,6 +11,7 @@ import (
 )
 
 type EventServiceParams struct {
+	Name                  string
 	EnableLiveFeedAPI     bool
 	Protocol              string
 	Address               string
@@ -17,11 +19,48 @@ type EventServiceParams struct {
 	OutputFormat          eventconfig.EventFormat
 	ReplayDuringStartup   bool
 	SendStateChangeEvents bool
+	SendBalanceChanges    bool
 	BroadcastContent      eventconfig.BroadcastContent
 	PersistentReconnect   bool
+	Filter                *EventFilter
+	SpoolPath             string
+	SpoolSize             uint64
 }
 
-func selectParameters(factomParams *globals.FactomParams, config *util.FactomdConfig) *EventServiceParams {
+// selectAllParameters returns the parameters of the [LiveFeedAPI] receiver, if EnableLiveFeedAPI
+// is set, followed by the ones of the [LiveFeedReceiver "name"] sections, in the order of their
+// names. A node that only sends to named receivers does not dial the default address. A receiver
+// whose filter can not be parsed is left out, rather than sent every event
+func selectAllParameters(factomParams *globals.FactomParams, config *util.FactomdConfig) []*EventServiceParams {
+	var all []*EventServiceParams
+	params, err := selectParameters(factomParams, config)
+	if err != nil {
+		log.LogPrintf("livefeed", "The events are not sent to the LiveFeedAPI receiver: %v", err)
+	} else if params.EnableLiveFeedAPI {
+		all = append(all, params)
+	}
+	if config == nil {
+		return all
+	}
+	var names []string
+	for name := range config.LiveFeedReceiver {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		params, err := selectReceiverParameters(name, config.LiveFeedReceiver[name])
+		if err != nil {
+			log.LogPrintf("livefeed", "The events are not sent to LiveFeedReceiver %s: %v", name, err)
+			continue
+		}
+		all = append(all, params)
+	}
+	return all
+}
+
+// selectParameters returns the parameters of the [LiveFeedAPI] receiver, or an error if its filter
+// can not be parsed
+func selectParameters(factomParams *globals.FactomParams, config *util.FactomdConfig) (*EventServiceParams, error) {
 	params := new(EventServiceParams)
 	if factomParams != nil && len(factomParams.EventReceiverProtocol) > 0 {
 		params.Protocol = factomParams.EventReceiverProtocol
@@ -53,6 +92,7 @@ func selectParameters(factomParams *globals.FactomParams, config *util.FactomdCo
 	params.EnableLiveFeedAPI = (factomParams != nil && factomParams.EnableLiveFeedAPI) || (config != nil && config.LiveFeedAPI.EnableLiveFeedAPI)
 	params.ReplayDuringStartup = (factomParams != nil && factomParams.EventReplayDuringStartup) || (config != nil && config.LiveFeedAPI.EventReplayDuringStartup)
 	params.SendStateChangeEvents = (factomParams != nil && factomParams.EventSendStateChange) || (config != nil && config.LiveFeedAPI.EventSendStateChange)
+	params.SendBalanceChanges = (factomParams != nil && factomParams.EventSendBalanceChanges) || (config != nil && config.LiveFeedAPI.EventSendBalanceChanges)
 	params.PersistentReconnect = (factomParams != nil && factomParams.PersistentReconnect) || (config != nil && config.LiveFeedAPI.PersistentReconnect)
 
 	var err error
@@ -72,5 +112,79 @@ func selectParameters(factomParams *globals.FactomParams, config *util.FactomdCo
 		params.BroadcastContent = eventconfig.BroadcastOnce
 	}
 
-	return params
+	params.Filter = new(EventFilter)
+	if config != nil {
+		params.Filter, err = ParseEventFilter(config.LiveFeedAPI.EventTypes, config.LiveFeedAPI.EventChainIDs)
+		if err != nil {
+			return nil, fmt.Errorf("Configuration properties LiveFeedAPI.EventTypes and EventChainIDs could not be parsed: %v", err)
+		}
+		selectSpool(params, config.LiveFeedAPI.EventSpoolPath, config.LiveFeedAPI.EventSpoolSize)
+	}
+
+	return params, nil
+}
+
+// selectReceiverParameters returns the parameters of a [LiveFeedReceiver "name"] section, that
+// takes the defaults for the values it leaves out, or an error if its filter can not be parsed
+func selectReceiverParameters(name string, receiver *util.LiveFeedReceiverConfig) (*EventServiceParams, error) {
+	params := &EventServiceParams{
+		Name:                  name,
+		EnableLiveFeedAPI:     true,
+		Protocol:              defaultProtocol,
+		OutputFormat:          defaultOutputFormat,
+		ReplayDuringStartup:   receiver.EventReplayDuringStartup,
+		SendStateChangeEvents: receiver.EventSendStateChange,
+		SendBalanceChanges:    receiver.EventSendBalanceChanges,
+		BroadcastContent:      eventconfig.BroadcastOnce,
+		PersistentReconnect:   receiver.PersistentReconnect,
+	}
+	if len(receiver.EventReceiverProtocol) > 0 {
+		params.Protocol = receiver.EventReceiverProtocol
+	}
+	host, port := defaultConnectionHost, defaultConnectionPort
+	if len(receiver.EventReceiverHost) > 0 {
+		host = receiver.EventReceiverHost
+	}
+	if receiver.EventReceiverPort > 0 {
+		port = receiver.EventReceiverPort
+	}
+	params.Address = fmt.Sprintf("%s:%d", host, port)
+	if receiver.EventSenderPort > 0 {
+		params.ClientPort = fmt.Sprintf(":%d", receiver.EventSenderPort)
+	}
+	if len(receiver.EventFormat) > 0 {
+		params.OutputFormat = eventconfig.EventFormatFrom(receiver.EventFormat, defaultOutputFormat)
+	}
+
+	var err error
+	if len(receiver.EventBroadcastContent) > 0 {
+		params.BroadcastContent, err = eventconfig.ParseBroadcastContent(receiver.EventBroadcastContent)
+		if err != nil {
+			log.LogPrintf("livefeed", "Configuration property LiveFeedReceiver %s EventBroadcastContent could not be parsed: %v", name, err)
+			params.BroadcastContent = eventconfig.BroadcastOnce
+		}
+	}
+	params.Filter, err = ParseEventFilter(receiver.EventTypes, receiver.EventChainIDs)
+	if err != nil {
+		return nil, fmt.Errorf("Configuration properties LiveFeedReceiver %s EventTypes and EventChainIDs could not be parsed: %v", name, err)
+	}
+	selectSpool(params, receiver.EventSpoolPath, receiver.EventSpoolSize)
+	return params, nil
+}
+
+// selectSpool sets the spool of a receiver, which takes a connection to resume over, or the http
+// protocol where the subscribers resume with the sequence number of the last event they got
+func selectSpool(params *EventServiceParams, path string, size int) {
+	if len(path) == 0 {
+		return
+	}
+	if params.Protocol != "tcp" && params.Protocol != streamProtocol {
+		log.LogPrintf("livefeed", "The events of receiver %s are not spooled, as it does not connect over tcp or http", params.Address)
+		return
+	}
+	params.SpoolPath = path
+	params.SpoolSize = defaultSpoolSize
+	if size > 0 {
+		params.SpoolSize = uint64(size)
+	}
 }
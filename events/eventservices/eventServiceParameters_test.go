@@ -8,13 +8,15 @@ import (
 	"github.com/FactomProject/factomd/events/eventconfig"
 	"github.com/FactomProject/factomd/util"
 	"github.com/stretchr/testify/assert"
+	"gopkg.in/gcfg.v1"
 )
 
 func TestEventServiceParameters_DefaultParameters(t *testing.T) {
 	config := &util.FactomdConfig{}
 	factomParams := globals.Params
 
-	params := selectParameters(factomParams, config)
+	params, err := selectParameters(factomParams, config)
+	assert.NoError(t, err)
 
 	assert.Equal(t, defaultProtocol, params.Protocol)
 	assert.Equal(t, fmt.Sprintf("%s:%d", defaultConnectionHost, defaultConnectionPort), params.Address)
@@ -47,11 +49,13 @@ func TestEventServiceParameters_OverrideParameters(t *testing.T) {
 		EventFormat:              "json",
 		EventReplayDuringStartup: true,
 		EventSendStateChange:     true,
+		EventSendBalanceChanges:  true,
 		EventBroadcastContent:    "always",
 		PersistentReconnect:      true,
 	}
 
-	testParams := selectParameters(factomParams, config)
+	testParams, err := selectParameters(factomParams, config)
+	assert.NoError(t, err)
 
 	assert.True(t, testParams.EnableLiveFeedAPI)
 	assert.Equal(t, "udp", testParams.Protocol)
@@ -59,6 +63,7 @@ func TestEventServiceParameters_OverrideParameters(t *testing.T) {
 	assert.Equal(t, eventconfig.Json, testParams.OutputFormat)
 	assert.True(t, testParams.ReplayDuringStartup)
 	assert.True(t, testParams.SendStateChangeEvents)
+	assert.True(t, testParams.SendBalanceChanges)
 	assert.Equal(t, eventconfig.BroadcastAlways, testParams.BroadcastContent)
 	assert.True(t, testParams.PersistentReconnect)
 }
@@ -77,7 +82,8 @@ func TestEventServiceParameters_ConfigParameters(t *testing.T) {
 	)
 	factomParams := globals.Params
 
-	testParams := selectParameters(factomParams, config)
+	testParams, err := selectParameters(factomParams, config)
+	assert.NoError(t, err)
 
 	assert.True(t, testParams.EnableLiveFeedAPI)
 	assert.Equal(t, "tcp", testParams.Protocol)
@@ -85,6 +91,7 @@ func TestEventServiceParameters_ConfigParameters(t *testing.T) {
 	assert.Equal(t, eventconfig.Protobuf, testParams.OutputFormat)
 	assert.True(t, testParams.ReplayDuringStartup)
 	assert.True(t, testParams.SendStateChangeEvents)
+	assert.False(t, testParams.SendBalanceChanges)
 	assert.Equal(t, eventconfig.BroadcastNever, testParams.BroadcastContent)
 	assert.True(t, testParams.PersistentReconnect)
 }
@@ -102,7 +109,8 @@ func TestEventServiceParameters_ParseBroadcastError(t *testing.T) {
 		EventBroadcastContent:    "alwayss",
 		PersistentReconnect:      false,
 	}
-	params := selectParameters(factomParams, config)
+	params, err := selectParameters(factomParams, config)
+	assert.NoError(t, err)
 	assert.Equal(t, eventconfig.BroadcastOnce, params.BroadcastContent)
 }
 
@@ -119,34 +127,114 @@ func TestEventServiceParameters_ParseBroadcastErrorOverride(t *testing.T) {
 		false,
 	)
 	factomParams := globals.Params
-	params := selectParameters(factomParams, config)
+	params, err := selectParameters(factomParams, config)
+	assert.NoError(t, err)
 	assert.Equal(t, eventconfig.BroadcastOnce, params.BroadcastContent)
 }
 
 func buildBaseConfig(enable bool, protocol string, address string, port int, format string, replay bool, stateChange bool, broadcast string, persistentReconnect bool) *util.FactomdConfig {
-	return &util.FactomdConfig{
-		LiveFeedAPI: struct {
-			EnableLiveFeedAPI        bool
-			EventReceiverProtocol    string
-			EventReceiverHost        string
-			EventReceiverPort        int
-			EventSenderPort          int
-			EventFormat              string
-			EventReplayDuringStartup bool
-			EventSendStateChange     bool
-			EventBroadcastContent    string
-			PersistentReconnect      bool
-		}{
-			EnableLiveFeedAPI:        enable,
-			EventReceiverProtocol:    protocol,
-			EventReceiverHost:        address,
-			EventReceiverPort:        port,
-			EventSenderPort:          port,
-			EventFormat:              format,
-			EventReplayDuringStartup: replay,
-			EventSendStateChange:     stateChange,
-			EventBroadcastContent:    broadcast,
-			PersistentReconnect:      persistentReconnect,
-		},
+	config := new(util.FactomdConfig)
+	config.LiveFeedAPI.EnableLiveFeedAPI = enable
+	config.LiveFeedAPI.EventReceiverProtocol = protocol
+	config.LiveFeedAPI.EventReceiverHost = address
+	config.LiveFeedAPI.EventReceiverPort = port
+	config.LiveFeedAPI.EventSenderPort = port
+	config.LiveFeedAPI.EventFormat = format
+	config.LiveFeedAPI.EventReplayDuringStartup = replay
+	config.LiveFeedAPI.EventSendStateChange = stateChange
+	config.LiveFeedAPI.EventBroadcastContent = broadcast
+	config.LiveFeedAPI.PersistentReconnect = persistentReconnect
+	return config
+}
+
+func TestEventServiceParameters_Receivers(t *testing.T) {
+	config := new(util.FactomdConfig)
+	err := gcfg.ReadStringInto(config, `
+[LiveFeedAPI]
+EnableLiveFeedAPI = true
+EventReceiverPort = 8040
+EventTypes = directoryBlockCommit
+
+[LiveFeedReceiver "indexer"]
+EventReceiverHost = 10.0.0.2
+EventReceiverPort = 8041
+EventFormat = json
+EventBroadcastContent = always
+EventChainIDs = 888888001750ede0eff4b05f0c3f557890b256450cabbb84cada937f9c258327
+
+[LiveFeedReceiver "alerts"]
+EventReceiverPort = 8042
+EventTypes = nodeMessage, entryreveal
+`)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	all := selectAllParameters(nil, config)
+	if !assert.Len(t, all, 3) {
+		return
+	}
+
+	assert.Equal(t, "", all[0].Name)
+	assert.Equal(t, map[string]bool{"directoryBlockCommit": true}, all[0].Filter.EventTypes)
+
+	alerts := all[1]
+	assert.Equal(t, "alerts", alerts.Name)
+	assert.Equal(t, fmt.Sprintf("%s:8042", defaultConnectionHost), alerts.Address)
+	assert.Equal(t, defaultOutputFormat, alerts.OutputFormat)
+	assert.Equal(t, eventconfig.BroadcastOnce, alerts.BroadcastContent)
+	assert.Equal(t, "", alerts.ClientPort)
+	assert.Equal(t, map[string]bool{"nodeMessage": true, "entryReveal": true}, alerts.Filter.EventTypes)
+
+	indexer := all[2]
+	assert.Equal(t, "indexer", indexer.Name)
+	assert.Equal(t, "10.0.0.2:8041", indexer.Address)
+	assert.Equal(t, eventconfig.Json, indexer.OutputFormat)
+	assert.Equal(t, eventconfig.BroadcastAlways, indexer.BroadcastContent)
+	assert.Nil(t, indexer.Filter.EventTypes)
+	assert.Len(t, indexer.Filter.ChainIDs, 1)
+
+	// only the named receivers are sent to without EnableLiveFeedAPI
+	config.LiveFeedAPI.EnableLiveFeedAPI = false
+	all = selectAllParameters(nil, config)
+	if assert.Len(t, all, 2) {
+		assert.Equal(t, "alerts", all[0].Name)
+		assert.Equal(t, "indexer", all[1].Name)
 	}
 }
+
+func TestEventServiceParameters_ReceiverFilterError(t *testing.T) {
+	// a receiver with a filter that can not be parsed is not sent any event
+	params, err := selectReceiverParameters("bad", &util.LiveFeedReceiverConfig{EventTypes: "blocks", EventChainIDs: "1234"})
+	assert.Error(t, err)
+	assert.Nil(t, params)
+
+	config := &util.FactomdConfig{}
+	config.LiveFeedAPI.EnableLiveFeedAPI = true
+	config.LiveFeedAPI.EventTypes = "blocks"
+	config.LiveFeedReceiver = map[string]*util.LiveFeedReceiverConfig{
+		"bad":  {EventChainIDs: "1234"},
+		"good": {EventTypes: "entryReveal"},
+	}
+	_, err = selectParameters(nil, config)
+	assert.Error(t, err)
+	all := selectAllParameters(nil, config)
+	if assert.Len(t, all, 1) {
+		assert.Equal(t, "good", all[0].Name)
+	}
+}
+
+func TestEventServiceParameters_Spool(t *testing.T) {
+	params, err := selectReceiverParameters("spooled", &util.LiveFeedReceiverConfig{EventSpoolPath: "spool"})
+	assert.NoError(t, err)
+	assert.Equal(t, "spool", params.SpoolPath)
+	assert.Equal(t, uint64(defaultSpoolSize), params.SpoolSize)
+
+	params, err = selectReceiverParameters("spooled", &util.LiveFeedReceiverConfig{EventSpoolPath: "spool", EventSpoolSize: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(10), params.SpoolSize)
+
+	params, err = selectReceiverParameters("udp", &util.LiveFeedReceiverConfig{EventReceiverProtocol: "udp", EventSpoolPath: "spool"})
+	assert.NoError(t, err)
+	assert.Equal(t, "", params.SpoolPath)
+}
@@ -0,0 +1,315 @@
+package eventservices
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/FactomProject/factomd/events/eventmessages/generated/eventmessages"
+	"github.com/gogo/protobuf/proto"
+)
+
+const (
+	spoolSegmentSuffix = ".spool"
+	// spoolRecordHeaderSize is the size of the sequence number and data size in front of each event
+	spoolRecordHeaderSize = 12
+	// maxSpoolRecordSize guards against reading a damaged size
+	maxSpoolRecordSize = 1 << 28
+)
+
+// eventSpool keeps the last events of a receiver on disk, so they can be sent again when the
+// receiver resumes after a restart of either side. The events are written to segment files named
+// after the sequence number of their first event, and the oldest segment is removed once the
+// others hold the number of events to keep. Each event is stored as its sequence number and size,
+// little endian, followed by the event in protobuf
+type eventSpool struct {
+	dir         string
+	size        uint64
+	segmentSize uint64
+
+	lock     sync.Mutex
+	segments []uint64 // first sequence number of each segment, oldest first
+	file     *os.File
+	writer   *bufio.Writer
+	offset   int64 // end of the last event written completely to the file
+	inFile   uint64
+	last     uint64
+}
+
+// openEventSpool opens the spool in the directory, creating it if needed. A partly written event
+// at the end of the spool, left by a crash, is dropped
+func openEventSpool(dir string, size uint64) (*eventSpool, error) {
+	if size == 0 {
+		return nil, errors.New("the spool must keep at least one event")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	spool := &eventSpool{dir: dir, size: size, segmentSize: size / 10}
+	if spool.segmentSize == 0 {
+		spool.segmentSize = 1
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		name := file.Name()
+		if !strings.HasSuffix(name, spoolSegmentSuffix) {
+			continue
+		}
+		first, err := strconv.ParseUint(strings.TrimSuffix(name, spoolSegmentSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		spool.segments = append(spool.segments, first)
+	}
+	sort.Slice(spool.segments, func(i, j int) bool { return spool.segments[i] < spool.segments[j] })
+
+	if len(spool.segments) > 0 {
+		if err := spool.recover(); err != nil {
+			return nil, err
+		}
+	}
+	return spool, nil
+}
+
+// recover finds the last event of the newest segment, and cuts the segment after it
+func (spool *eventSpool) recover() error {
+	first := spool.segments[len(spool.segments)-1]
+	file, err := os.OpenFile(spool.segmentName(first), os.O_RDWR, 0644)
+	if err != nil {
+		return err
+	}
+
+	var end int64
+	spool.last = first - 1
+	reader := bufio.NewReader(file)
+	for {
+		sequence, data, err := readSpoolRecord(reader)
+		if err != nil {
+			break
+		}
+		spool.last = sequence
+		spool.inFile++
+		end += int64(spoolRecordHeaderSize + len(data))
+	}
+	if err := file.Truncate(end); err != nil {
+		file.Close()
+		return err
+	}
+	if _, err := file.Seek(end, io.SeekStart); err != nil {
+		file.Close()
+		return err
+	}
+	spool.file = file
+	spool.writer = bufio.NewWriter(file)
+	spool.offset = end
+	return nil
+}
+
+// LastSequence returns the sequence number of the newest event in the spool, 0 if it is empty
+func (spool *eventSpool) LastSequence() uint64 {
+	spool.lock.Lock()
+	defer spool.lock.Unlock()
+	return spool.last
+}
+
+// Append writes the event to the spool. Its sequence number has to be above the one of the newest
+// event in the spool. An event that fails to be written, on a full disk say, is cut off again and
+// left out, the sequence in the spool skips it. The event is handed to the operating system but
+// not synced, the segments are synced when they are rotated and closed
+func (spool *eventSpool) Append(event *eventmessages.FactomEvent) error {
+	data, err := proto.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	spool.lock.Lock()
+	defer spool.lock.Unlock()
+
+	if event.Sequence <= spool.last {
+		return fmt.Errorf("event %d does not follow event %d in the spool", event.Sequence, spool.last)
+	}
+	if spool.file == nil || spool.inFile >= spool.segmentSize {
+		if err := spool.rotate(event.Sequence); err != nil {
+			return err
+		}
+	}
+
+	header := make([]byte, spoolRecordHeaderSize)
+	binary.LittleEndian.PutUint64(header, event.Sequence)
+	binary.LittleEndian.PutUint32(header[8:], uint32(len(data)))
+	_, err = spool.writer.Write(header)
+	if err == nil {
+		_, err = spool.writer.Write(data)
+	}
+	if err == nil {
+		err = spool.writer.Flush()
+	}
+	if err != nil {
+		spool.cutOff()
+		return err
+	}
+	spool.offset += int64(len(header) + len(data))
+	spool.inFile++
+	spool.last = event.Sequence
+	return nil
+}
+
+// cutOff drops what was written of an event that failed, so the next event follows the last one
+// written completely. The error of the writer is cleared with it. When the segment can not be cut
+// back, the next event starts a new segment
+func (spool *eventSpool) cutOff() {
+	if err := spool.file.Truncate(spool.offset); err == nil {
+		if _, err = spool.file.Seek(spool.offset, io.SeekStart); err == nil {
+			spool.writer.Reset(spool.file)
+			return
+		}
+	}
+	spool.file.Close()
+	spool.file = nil
+}
+
+// rotate starts a new segment with the event, and removes the oldest segments when the others
+// keep enough events without them
+func (spool *eventSpool) rotate(sequence uint64) error {
+	if spool.file != nil {
+		err := spool.file.Sync()
+		if closeErr := spool.file.Close(); err == nil {
+			err = closeErr
+		}
+		spool.file = nil
+		if err != nil {
+			return err
+		}
+	}
+	file, err := os.OpenFile(spool.segmentName(sequence), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	spool.file = file
+	spool.writer = bufio.NewWriter(file)
+	spool.offset = 0
+	spool.inFile = 0
+	spool.segments = append(spool.segments, sequence)
+
+	for len(spool.segments) > 1 && sequence-spool.segments[1] >= spool.size {
+		if err := os.Remove(spool.segmentName(spool.segments[0])); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		spool.segments = spool.segments[1:]
+	}
+	return nil
+}
+
+// Read calls the function with the spooled events whose sequence number is above after and below
+// before, oldest first. Events that are no longer in the spool are skipped
+func (spool *eventSpool) Read(after uint64, before uint64, fn func(event *eventmessages.FactomEvent) error) error {
+	spool.lock.Lock()
+	segments := append([]uint64{}, spool.segments...)
+	spool.lock.Unlock()
+
+	start := 0
+	for i, first := range segments {
+		if first <= after+1 {
+			start = i
+		}
+	}
+	for _, first := range segments[start:] {
+		if first >= before {
+			return nil
+		}
+		done, err := spool.readSegment(first, after, before, fn)
+		if err != nil || done {
+			return err
+		}
+	}
+	return nil
+}
+
+func (spool *eventSpool) readSegment(first uint64, after uint64, before uint64, fn func(event *eventmessages.FactomEvent) error) (bool, error) {
+	file, err := os.Open(spool.segmentName(first))
+	if os.IsNotExist(err) {
+		return false, nil // removed in the meantime
+	}
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	for {
+		sequence, data, err := readSpoolRecord(reader)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			// an event that failed to be written may be left at the end of a segment
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if sequence >= before {
+			return true, nil
+		}
+		if sequence <= after {
+			continue
+		}
+		event := new(eventmessages.FactomEvent)
+		if err := proto.Unmarshal(data, event); err != nil {
+			return false, err
+		}
+		if err := fn(event); err != nil {
+			return false, err
+		}
+	}
+}
+
+// Close syncs and closes the segment that is written to
+func (spool *eventSpool) Close() error {
+	spool.lock.Lock()
+	defer spool.lock.Unlock()
+	if spool.file == nil {
+		return nil
+	}
+	err := spool.file.Sync()
+	if closeErr := spool.file.Close(); err == nil {
+		err = closeErr
+	}
+	spool.file = nil
+	return err
+}
+
+func (spool *eventSpool) segmentName(first uint64) string {
+	return filepath.Join(spool.dir, fmt.Sprintf("%020d%s", first, spoolSegmentSuffix))
+}
+
+// readSpoolRecord reads an event of a segment. It returns io.EOF at the end of the segment, and
+// io.ErrUnexpectedEOF if the last event was not written completely
+func readSpoolRecord(reader io.Reader) (uint64, []byte, error) {
+	header := make([]byte, spoolRecordHeaderSize)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return 0, nil, err
+	}
+	size := binary.LittleEndian.Uint32(header[8:])
+	if size > maxSpoolRecordSize {
+		return 0, nil, fmt.Errorf("spooled event of %d bytes", size)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(reader, data); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return 0, nil, err
+	}
+	return binary.LittleEndian.Uint64(header), data, nil
+}
@@ -0,0 +1,246 @@
+package eventservices
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/FactomProject/factomd/events/eventconfig"
+	"github.com/FactomProject/factomd/events/eventmessages/generated/eventmessages"
+	"github.com/gogo/protobuf/proto"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func spoolTestEvent(sequence uint64) *eventmessages.FactomEvent {
+	return &eventmessages.FactomEvent{
+		Sequence:       sequence,
+		FactomNodeName: "test",
+		Event: &eventmessages.FactomEvent_ProcessListEvent{ProcessListEvent: &eventmessages.ProcessListEvent{
+			ProcessListEvent: &eventmessages.ProcessListEvent_NewBlockEvent{NewBlockEvent: &eventmessages.NewBlockEvent{NewBlockHeight: uint32(sequence)}},
+		}},
+	}
+}
+
+func readSpool(t *testing.T, spool *eventSpool, after uint64, before uint64) []uint64 {
+	var sequences []uint64
+	err := spool.Read(after, before, func(event *eventmessages.FactomEvent) error {
+		assert.Equal(t, uint32(event.Sequence), event.GetProcessListEvent().GetNewBlockEvent().GetNewBlockHeight())
+		sequences = append(sequences, event.Sequence)
+		return nil
+	})
+	assert.NoError(t, err)
+	return sequences
+}
+
+func TestEventSpool(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eventspool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	spool, err := openEventSpool(dir, 100)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, uint64(0), spool.LastSequence())
+	assert.Empty(t, readSpool(t, spool, 0, 10))
+
+	for i := uint64(1); i <= 250; i++ {
+		assert.NoError(t, spool.Append(spoolTestEvent(i)))
+	}
+	assert.Error(t, spool.Append(spoolTestEvent(250)))
+	assert.Equal(t, uint64(250), spool.LastSequence())
+
+	// At least the last 100 events are kept, in segments of 10
+	sequences := readSpool(t, spool, 0, 1000)
+	assert.True(t, len(sequences) >= 100 && len(sequences) <= 110, "%d events spooled", len(sequences))
+	assert.Equal(t, uint64(250), sequences[len(sequences)-1])
+	assert.Equal(t, []uint64{201, 202, 203}, readSpool(t, spool, 200, 204))
+	assert.Empty(t, readSpool(t, spool, 250, 1000))
+	assert.NoError(t, spool.Close())
+
+	// A partly written event is dropped when the spool is opened again
+	last := filepath.Join(dir, "00000000000000000241.spool")
+	f, err := os.OpenFile(last, os.O_WRONLY|os.O_APPEND, 0644)
+	if !assert.NoError(t, err) {
+		return
+	}
+	f.Write([]byte{251, 0, 0, 0, 0, 0, 0, 0, 100, 0, 0, 0, 1, 2})
+	f.Close()
+
+	spool, err = openEventSpool(dir, 100)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer spool.Close()
+	assert.Equal(t, uint64(250), spool.LastSequence())
+	assert.NoError(t, spool.Append(spoolTestEvent(251)))
+	assert.Equal(t, []uint64{249, 250, 251}, readSpool(t, spool, 248, 1000))
+}
+
+// failingWriter writes the first bytes it is given, then fails as a full disk would
+type failingWriter struct {
+	io.Writer
+	room int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if len(p) > w.room {
+		n, _ := w.Writer.Write(p[:w.room])
+		w.room = 0
+		return n, io.ErrShortWrite
+	}
+	w.room -= len(p)
+	return w.Writer.Write(p)
+}
+
+func TestEventSpool_FailedWrite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eventspool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	spool, err := openEventSpool(dir, 100)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer spool.Close()
+	for i := uint64(1); i <= 3; i++ {
+		assert.NoError(t, spool.Append(spoolTestEvent(i)))
+	}
+
+	// part of event 4 reaches the segment before the write fails, it is cut off again
+	spool.writer = bufio.NewWriterSize(&failingWriter{Writer: spool.file, room: 5}, 16)
+	assert.Error(t, spool.Append(spoolTestEvent(4)))
+	assert.Equal(t, uint64(3), spool.LastSequence())
+	assert.NoError(t, spool.Append(spoolTestEvent(5)))
+	assert.Equal(t, []uint64{1, 2, 3, 5}, readSpool(t, spool, 0, 1000))
+
+	// a segment that can not be cut back is left for a new one
+	spool.file.Close()
+	assert.Error(t, spool.Append(spoolTestEvent(6)))
+	assert.NoError(t, spool.Append(spoolTestEvent(7)))
+	assert.Equal(t, []uint64{1, 2, 3, 5, 7}, readSpool(t, spool, 0, 1000))
+	assert.NoError(t, spool.Close())
+
+	spool, err = openEventSpool(dir, 100)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, uint64(7), spool.LastSequence())
+}
+
+// BenchmarkEventSpoolAppend measures the write that Stamp makes for each event of a receiver with
+// a spool
+func BenchmarkEventSpoolAppend(b *testing.B) {
+	dir, err := ioutil.TempDir("", "eventspool")
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	spool, err := openEventSpool(dir, defaultSpoolSize)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer spool.Close()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := spool.Append(spoolTestEvent(uint64(i + 1))); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestEventsService_Resume(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eventspool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	// the receiver got the first 2 events before it went down
+	received := make(chan uint64, 10)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		request := make([]byte, 9)
+		request[0] = protocolVersion
+		binary.LittleEndian.PutUint64(request[1:], 2)
+		conn.Write(request)
+
+		reader := bufio.NewReader(conn)
+		for {
+			header := make([]byte, 5)
+			if _, err := io.ReadFull(reader, header); err != nil {
+				return
+			}
+			data := make([]byte, binary.LittleEndian.Uint32(header[1:]))
+			if _, err := io.ReadFull(reader, data); err != nil {
+				return
+			}
+			event := new(eventmessages.FactomEvent)
+			if err := proto.Unmarshal(data, event); err != nil {
+				return
+			}
+			received <- event.Sequence
+		}
+	}()
+
+	spool, err := openEventSpool(dir, 100)
+	if !assert.NoError(t, err) {
+		return
+	}
+	eventService := &eventSender{
+		params: &EventServiceParams{
+			Protocol:     "tcp",
+			Address:      listener.Addr().String(),
+			OutputFormat: eventconfig.Protobuf,
+		},
+		notSentCounter: prometheus.NewCounter(prometheus.CounterOpts{}),
+		eventSequencer: eventSequencer{spool: spool},
+	}
+	defer spool.Close()
+
+	var events []*eventmessages.FactomEvent
+	for i := 0; i < 5; i++ {
+		events = append(events, eventService.Stamp(spoolTestEvent(0)))
+	}
+	assert.Equal(t, uint64(5), events[4].Sequence)
+
+	// the events in between were dropped from the queue, they are sent from the spool
+	eventService.sendEvent(events[4])
+	eventService.sendEvent(events[3])
+
+	var sequences []uint64
+	for len(sequences) < 3 {
+		select {
+		case sequence := <-received:
+			sequences = append(sequences, sequence)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("received only %v", sequences)
+		}
+	}
+	assert.Equal(t, []uint64{3, 4, 5}, sequences)
+	assert.Equal(t, uint64(5), eventService.lastSent)
+	assert.Equal(t, float64(0), getCounterValue(t, eventService.notSentCounter))
+	eventService.disconnect()
+}
@@ -0,0 +1,258 @@
+package eventservices
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/FactomProject/factomd/events/eventconfig"
+	"github.com/FactomProject/factomd/events/eventmessages/generated/eventmessages"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+// streamProtocol is the protocol of a receiver whose events are served by the API server, over
+// server-sent events or a websocket, instead of being sent to a receiver that listens for them
+const streamProtocol = "http"
+
+// subscriptionQueueSize is the number of events a subscriber can fall behind before it is dropped
+var subscriptionQueueSize = 1000
+
+// ErrSubscriberTooSlow is returned when a subscriber is dropped because it could not keep up
+var ErrSubscriberTooSlow = errors.New("the subscriber could not keep up with the events")
+
+// eventStreamInstances are the streams by receiver name, shared by the nodes of a simulation
+var (
+	eventStreamInstances     = make(map[string]*EventStream)
+	eventStreamInstancesLock sync.Mutex
+)
+
+// EventStream is the sender of a receiver with the http protocol. It hands the events to the
+// clients that are subscribed to it through the API server, each with its own queue and filter
+type EventStream struct {
+	params                  *EventServiceParams
+	eventsOutQueue          chan *eventmessages.FactomEvent
+	droppedFromQueueCounter prometheus.Counter
+	eventSequencer
+
+	lock          sync.Mutex
+	subscriptions map[*EventSubscription]bool
+	closed        bool
+}
+
+// EventSubscription is a client of an event stream
+type EventSubscription struct {
+	stream *EventStream
+	filter *EventFilter
+	after  uint64
+	events chan *eventmessages.FactomEvent
+}
+
+// GetEventStream returns the stream of the receiver, nil if there is no receiver with that name or
+// it does not use the http protocol. The [LiveFeedAPI] receiver has no name
+func GetEventStream(name string) *EventStream {
+	eventStreamInstancesLock.Lock()
+	defer eventStreamInstancesLock.Unlock()
+	return eventStreamInstances[name]
+}
+
+func newEventStream(params *EventServiceParams) *EventStream {
+	eventStreamInstancesLock.Lock()
+	defer eventStreamInstancesLock.Unlock()
+
+	instance := eventStreamInstances[params.Name]
+	if instance == nil {
+		instance = &EventStream{
+			params:         params,
+			eventsOutQueue: make(chan *eventmessages.FactomEvent, 5000),
+			subscriptions:  make(map[*EventSubscription]bool),
+		}
+		instance.droppedFromQueueCounter = prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        "factomd_livefeed_dropped_from_queue_counter",
+			Help:        "Number of times we dropped events due of a full the event queue",
+			ConstLabels: prometheus.Labels{"receiver": params.Name},
+		})
+		instance.openSpool(params)
+
+		eventStreamInstances[params.Name] = instance
+		go instance.processEventsChannel()
+	}
+	return instance
+}
+
+// processEventsChannel hands the events to the subscribers. A subscriber that has a full queue is
+// dropped, so that it can not hold up the others
+func (stream *EventStream) processEventsChannel() {
+	for event := range stream.eventsOutQueue {
+		stream.lock.Lock()
+		for subscription := range stream.subscriptions {
+			select {
+			case subscription.events <- event:
+			default:
+				log.Warnf("Dropping a subscriber of receiver %s that could not keep up", stream.params.Name)
+				delete(stream.subscriptions, subscription)
+				close(subscription.events)
+			}
+		}
+		stream.lock.Unlock()
+	}
+
+	stream.lock.Lock()
+	defer stream.lock.Unlock()
+	stream.closed = true
+	for subscription := range stream.subscriptions {
+		delete(stream.subscriptions, subscription)
+		close(subscription.events)
+	}
+}
+
+// Subscribe returns a subscription to the events that pass the filter, which can be nil. A
+// subscriber that resumes passes the sequence number of the last event it got, the events after it
+// are sent again as far as they are still in the spool
+func (stream *EventStream) Subscribe(filter *EventFilter, after uint64) *EventSubscription {
+	return &EventSubscription{
+		stream: stream,
+		filter: filter,
+		after:  after,
+		events: make(chan *eventmessages.FactomEvent, subscriptionQueueSize),
+	}
+}
+
+// GetOutputFormat returns the format of the receiver, used when a subscriber does not ask for one
+func (stream *EventStream) GetOutputFormat() eventconfig.EventFormat {
+	return stream.params.OutputFormat
+}
+
+// Run sends the events to the subscriber until done is closed, the stream is shut down, the
+// subscriber falls behind or send fails
+func (subscription *EventSubscription) Run(done <-chan struct{}, send func(event *eventmessages.FactomEvent) error) error {
+	stream := subscription.stream
+	last := subscription.after
+
+	// most of the spool is sent before subscribing, so that the queue does not fill up meanwhile
+	if stream.spool != nil {
+		var err error
+		if last, err = subscription.replay(last, stream.lastSequence(), send); err != nil {
+			return err
+		}
+	}
+
+	stream.lock.Lock()
+	if stream.closed {
+		stream.lock.Unlock()
+		return nil
+	}
+	stream.subscriptions[subscription] = true
+	sequence := stream.lastSequence()
+	stream.lock.Unlock()
+	defer subscription.unsubscribe()
+
+	if stream.spool != nil {
+		var err error
+		if last, err = subscription.replay(last, sequence, send); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-done:
+			return nil
+		case event, ok := <-subscription.events:
+			if !ok {
+				if stream.isClosed() {
+					return nil
+				}
+				return ErrSubscriberTooSlow
+			}
+			if event.Sequence <= last {
+				continue
+			}
+			if err := subscription.send(event, send); err != nil {
+				return err
+			}
+			last = event.Sequence
+		}
+	}
+}
+
+// replay sends the spooled events after the first sequence number up to the last, and returns the
+// sequence number it got to
+func (subscription *EventSubscription) replay(first uint64, last uint64, send func(event *eventmessages.FactomEvent) error) (uint64, error) {
+	if last <= first {
+		return first, nil
+	}
+	err := subscription.stream.spool.Read(first, last+1, func(event *eventmessages.FactomEvent) error {
+		return subscription.send(event, send)
+	})
+	return last, err
+}
+
+func (subscription *EventSubscription) send(event *eventmessages.FactomEvent, send func(event *eventmessages.FactomEvent) error) error {
+	event = subscription.filter.Apply(event)
+	if event == nil {
+		return nil
+	}
+	return send(event)
+}
+
+func (stream *EventStream) isClosed() bool {
+	stream.lock.Lock()
+	defer stream.lock.Unlock()
+	return stream.closed
+}
+
+func (subscription *EventSubscription) unsubscribe() {
+	stream := subscription.stream
+	stream.lock.Lock()
+	defer stream.lock.Unlock()
+	if stream.subscriptions[subscription] {
+		delete(stream.subscriptions, subscription)
+		close(subscription.events)
+	}
+}
+
+func (stream *EventStream) GetName() string {
+	return stream.params.Name
+}
+
+func (stream *EventStream) GetEventQueue() chan *eventmessages.FactomEvent {
+	return stream.eventsOutQueue
+}
+
+func (stream *EventStream) GetBroadcastContent() eventconfig.BroadcastContent {
+	return stream.params.BroadcastContent
+}
+
+func (stream *EventStream) IsSendStateChangeEvents() bool {
+	return stream.params.SendStateChangeEvents
+}
+
+func (stream *EventStream) IsSendBalanceChanges() bool {
+	return stream.params.SendBalanceChanges
+}
+
+func (stream *EventStream) ReplayDuringStartup() bool {
+	return stream.params.ReplayDuringStartup
+}
+
+func (stream *EventStream) GetFilter() *EventFilter {
+	return stream.params.Filter
+}
+
+func (stream *EventStream) IncreaseDroppedFromQueueCounter() {
+	stream.droppedFromQueueCounter.Inc()
+}
+
+// Shutdown closes the queue, which ends the subscriptions once the queued events are handed out
+func (stream *EventStream) Shutdown() {
+	close(stream.eventsOutQueue)
+	if stream.spool != nil {
+		stream.spool.Close()
+	}
+
+	eventStreamInstancesLock.Lock()
+	defer eventStreamInstancesLock.Unlock()
+	if eventStreamInstances[stream.params.Name] == stream {
+		delete(eventStreamInstances, stream.params.Name)
+	}
+}
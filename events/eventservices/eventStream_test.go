@@ -0,0 +1,125 @@
+package eventservices
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/FactomProject/factomd/events/eventconfig"
+	"github.com/FactomProject/factomd/events/eventmessages/generated/eventmessages"
+	"github.com/stretchr/testify/assert"
+)
+
+// runSubscription runs the subscription until it ends, and hands the sequence numbers it gets to the channel
+func runSubscription(subscription *EventSubscription, done chan struct{}, received chan uint64, result chan error) {
+	result <- subscription.Run(done, func(event *eventmessages.FactomEvent) error {
+		received <- event.Sequence
+		return nil
+	})
+}
+
+func receiveSequences(t *testing.T, received chan uint64, n int) []uint64 {
+	var sequences []uint64
+	for len(sequences) < n {
+		select {
+		case sequence := <-received:
+			sequences = append(sequences, sequence)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("received only %v", sequences)
+		}
+	}
+	return sequences
+}
+
+func waitForSubscriptions(stream *EventStream, n int) {
+	for i := 0; i < 500; i++ {
+		stream.lock.Lock()
+		subscriptions := len(stream.subscriptions)
+		stream.lock.Unlock()
+		if subscriptions == n {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestEventStream(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eventstream")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	params := &EventServiceParams{Name: "stream", Protocol: streamProtocol, OutputFormat: eventconfig.Json, SpoolPath: dir, SpoolSize: 100}
+	stream, ok := NewEventSenderTo(params).(*EventStream)
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.Equal(t, stream, GetEventStream("stream"))
+	assert.Nil(t, GetEventStream("other"))
+	assert.Equal(t, eventconfig.Json, stream.GetOutputFormat())
+
+	for i := 0; i < 3; i++ {
+		stream.GetEventQueue() <- stream.Stamp(spoolTestEvent(0))
+	}
+
+	// a subscriber that resumes after the first event gets the others from the spool
+	done := make(chan struct{})
+	received := make(chan uint64, 10)
+	result := make(chan error, 1)
+	go runSubscription(stream.Subscribe(nil, 1), done, received, result)
+	assert.Equal(t, []uint64{2, 3}, receiveSequences(t, received, 2))
+
+	filter, _ := ParseEventFilter("nodeMessage", "")
+	filtered := make(chan uint64, 10)
+	filteredResult := make(chan error, 1)
+	go runSubscription(stream.Subscribe(filter, 0), done, filtered, filteredResult)
+	waitForSubscriptions(stream, 2)
+
+	for i := 0; i < 2; i++ {
+		stream.GetEventQueue() <- stream.Stamp(spoolTestEvent(0))
+	}
+	assert.Equal(t, []uint64{4, 5}, receiveSequences(t, received, 2))
+	assert.Empty(t, filtered)
+
+	close(done)
+	assert.NoError(t, <-result)
+	assert.NoError(t, <-filteredResult)
+
+	stream.Shutdown()
+	assert.Nil(t, GetEventStream("stream"))
+}
+
+func TestEventStream_SlowSubscriber(t *testing.T) {
+	defer func(size int) { subscriptionQueueSize = size }(subscriptionQueueSize)
+	subscriptionQueueSize = 1
+
+	stream := NewEventSenderTo(&EventServiceParams{Name: "slow", Protocol: streamProtocol}).(*EventStream)
+
+	// the first event holds up the subscriber until the others overflow its queue
+	release := make(chan struct{})
+	result := make(chan error, 1)
+	go func() {
+		result <- stream.Subscribe(nil, 0).Run(nil, func(event *eventmessages.FactomEvent) error {
+			<-release
+			return nil
+		})
+	}()
+	waitForSubscriptions(stream, 1)
+
+	for i := 0; i < 3; i++ {
+		stream.GetEventQueue() <- stream.Stamp(spoolTestEvent(0))
+	}
+	waitForSubscriptions(stream, 0)
+	close(release)
+	assert.Equal(t, ErrSubscriberTooSlow, <-result)
+
+	// the subscribers end when the stream shuts down
+	go func() {
+		result <- stream.Subscribe(nil, 0).Run(nil, func(event *eventmessages.FactomEvent) error { return nil })
+	}()
+	waitForSubscriptions(stream, 1)
+	stream.Shutdown()
+	assert.NoError(t, <-result)
+}
@@ -24,21 +24,51 @@ func mapTransactions(transactions []interfaces.ITransaction, endOfPeriod [10]int
 	for i, transaction := range transactions {
 		err := transaction.ValidateSignatures()
 		if err == nil {
-			// The endOfPeriod array contains the transaction count at the new minute transition time
-			// The minuteMark will contain the value of during which minute the transaction took place which is one higher than the position within the array
-			var minuteMark = 0
-			for ; minuteMark < len(endOfPeriod) && endOfPeriod[minuteMark] > 0; minuteMark++ {
-				if endOfPeriod[minuteMark] >= i {
-					minuteMark++
-					break
-				}
-			}
-			result = append(result, mapTransaction(transaction, minuteMark))
+			result = append(result, mapTransaction(transaction, TransactionMinute(endOfPeriod, i)))
 		}
 	}
 	return result
 }
 
+// TransactionMinute returns the minute of the transaction at the index of a factoid block
+func TransactionMinute(endOfPeriod [10]int, index int) int {
+	// The endOfPeriod array contains the transaction count at the new minute transition time
+	// The minuteMark will contain the value of during which minute the transaction took place which is one higher than the position within the array
+	var minuteMark = 0
+	for ; minuteMark < len(endOfPeriod) && endOfPeriod[minuteMark] > 0; minuteMark++ {
+		if endOfPeriod[minuteMark] >= index {
+			minuteMark++
+			break
+		}
+	}
+	return minuteMark
+}
+
+func mapFactoidTransaction(state eventmessages.EntityState, transaction interfaces.ITransaction, blockHeight uint32, minuteNumber int) *eventmessages.FactomEvent_FactoidTransaction {
+	mapped := mapTransaction(transaction, minuteNumber)
+	mapped.BlockHeight = blockHeight
+	result := &eventmessages.FactomEvent_FactoidTransaction{
+		FactoidTransaction: &eventmessages.FactoidTransaction{
+			EntityState: state,
+			Transaction: mapped,
+		},
+	}
+	return result
+}
+
+func mapBalanceChange(address interfaces.IAddress, entryCredit bool, change int64, transactionID interfaces.IHash, blockHeight uint32) *eventmessages.FactomEvent_BalanceChange {
+	result := &eventmessages.FactomEvent_BalanceChange{
+		BalanceChange: &eventmessages.BalanceChange{
+			Address:       address.Bytes(),
+			EntryCredit:   entryCredit,
+			Change:        change,
+			TransactionID: transactionID.Bytes(),
+			BlockHeight:   blockHeight,
+		},
+	}
+	return result
+}
+
 func mapTransaction(transaction interfaces.ITransaction, minuteNumber int) *eventmessages.Transaction {
 	result := &eventmessages.Transaction{
 		TransactionID:                 transaction.GetSigHash().Bytes(),
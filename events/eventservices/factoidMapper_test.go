@@ -7,6 +7,7 @@ import (
 	"github.com/FactomProject/factomd/common/factoid"
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/events/eventmessages/generated/eventmessages"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -147,6 +148,44 @@ func TestMapFactoidSignatureBlockSingatures(t *testing.T) {
 	}
 }
 
+func TestTransactionMinute(t *testing.T) {
+	endOfPeriod := [10]int{0, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+	assert.Equal(t, 0, TransactionMinute(endOfPeriod, 0))
+
+	endOfPeriod = [10]int{1, 1, 3, 3, 3, 3, 3, 3, 3, 3}
+	assert.Equal(t, 1, TransactionMinute(endOfPeriod, 1))
+	assert.Equal(t, 3, TransactionMinute(endOfPeriod, 2))
+}
+
+func TestMapFactoidTransaction(t *testing.T) {
+	factoidTransaction := newTestTransaction()
+	event := mapFactoidTransaction(eventmessages.EntityState_COMMITTED_TO_DIRECTORY_BLOCK, factoidTransaction, 12, 4)
+
+	if assert.NotNil(t, event.FactoidTransaction) {
+		assert.Equal(t, eventmessages.EntityState_COMMITTED_TO_DIRECTORY_BLOCK, event.FactoidTransaction.EntityState)
+		transaction := event.FactoidTransaction.Transaction
+		assert.Equal(t, factoidTransaction.GetSigHash().Bytes(), transaction.TransactionID)
+		assert.Equal(t, uint32(12), transaction.BlockHeight)
+		assert.Equal(t, uint32(4), transaction.MinuteNumber)
+		assert.Equal(t, 1, len(transaction.FactoidInputs))
+		assert.Equal(t, 2, len(transaction.FactoidOutputs))
+	}
+}
+
+func TestMapBalanceChange(t *testing.T) {
+	address := factoid.NewAddress(primitives.Sha([]byte("address")).Bytes())
+	transactionID := primitives.RandomHash()
+	event := mapBalanceChange(address, true, -20, transactionID, 12)
+
+	if assert.NotNil(t, event.BalanceChange) {
+		assert.Equal(t, address.Bytes(), event.BalanceChange.Address)
+		assert.True(t, event.BalanceChange.EntryCredit)
+		assert.Equal(t, int64(-20), event.BalanceChange.Change)
+		assert.Equal(t, transactionID.Bytes(), event.BalanceChange.TransactionID)
+		assert.Equal(t, uint32(12), event.BalanceChange.BlockHeight)
+	}
+}
+
 func newTestTransaction() *factoid.Transaction {
 	address := factoid.NewAddress([]byte(""))
 	tx := new(factoid.Transaction)
@@ -0,0 +1,72 @@
+package state
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/FactomProject/factomd/common/constants/runstate"
+)
+
+// AddressIndexInterval is how long the address indexer waits before retrying after a database error
+var AddressIndexInterval = time.Second
+
+// IndexAddressTransactions backfills the optional address transaction index. Blocks are indexed
+// as they are saved once the index has caught up, so this only indexes the factoid and entry
+// credit blocks of the heights saved before that, starting from the genesis block on databases
+// that existed before the index was enabled. It returns once the index reaches the saved blocks.
+func (s *State) IndexAddressTransactions() {
+	if !s.AddressIndex {
+		return
+	}
+
+	for s.GetRunState() <= runstate.Running {
+		done, err := s.indexNextAddressTransactions()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%20s Address index: %v\n", s.FactomNodeName, err)
+			time.Sleep(AddressIndexInterval)
+			continue
+		}
+		if done {
+			return
+		}
+	}
+}
+
+// indexNextAddressTransactions indexes the next height missing from the address index, and
+// returns true if there is none. The lock keeps a block save from reading the index height
+// while it is moved on.
+func (s *State) indexNextAddressTransactions() (bool, error) {
+	s.addressIndexLock.Lock()
+	defer s.addressIndexLock.Unlock()
+
+	next, err := s.DB.FetchAddressIndexHeight()
+	if err != nil {
+		return false, err
+	}
+	head, err := s.DB.FetchDBlockHead()
+	if err != nil {
+		return false, err
+	}
+	if head == nil || next > head.GetDatabaseHeight() {
+		return true, nil
+	}
+
+	fblock, err := s.DB.FetchFBlockByHeight(next)
+	if err != nil {
+		return false, err
+	}
+	ecblock, err := s.DB.FetchECBlockByHeight(next)
+	if err != nil {
+		return false, err
+	}
+	if fblock == nil || ecblock == nil {
+		return false, fmt.Errorf("the blocks at height %d are missing", next)
+	}
+	return false, s.DB.SaveAddressTransactions(next, fblock, ecblock)
+}
+
+// IsAddressIndexEnabled returns true if the node maintains the address transaction index
+func (s *State) IsAddressIndexEnabled() bool {
+	return s.AddressIndex
+}
@@ -0,0 +1,26 @@
+package state_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestIndexAddressTransactions(t *testing.T) {
+	s := testHelper.CreateAndPopulateTestState()
+	head, err := s.DB.FetchDBlockHead()
+	if err != nil || head == nil {
+		t.Fatal(head, err)
+	}
+
+	// returns once every saved block is indexed
+	s.AddressIndex = true
+	s.IndexAddressTransactions()
+	next, err := s.DB.FetchAddressIndexHeight()
+	if err != nil {
+		t.Error(err)
+	}
+	if next != head.GetDatabaseHeight()+1 {
+		t.Errorf("Expected the index to continue at %d, got %d", head.GetDatabaseHeight()+1, next)
+	}
+}
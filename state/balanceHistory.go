@@ -0,0 +1,189 @@
+package state
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// GetFactoidBalanceAtHeight returns the balance of a factoid address after the factoid block
+// at the given height was applied
+func (s *State) GetFactoidBalanceAtHeight(address [32]byte, height uint32) (int64, error) {
+	balance, heights, err := s.balanceReplay(height, func() ([]interfaces.AddressTransaction, error) {
+		return s.DB.FetchFactoidAddressTransactions(primitives.NewHash(address[:]))
+	}, func(ss *SaveState) int64 {
+		return ss.FactoidBalancesP[address]
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, h := range heights {
+		fblock, err := s.DB.FetchFBlockByHeight(h)
+		if err != nil {
+			return 0, err
+		}
+		if fblock == nil {
+			return 0, fmt.Errorf("factoid block %d not found", h)
+		}
+		balance += factoidDelta(address, fblock)
+	}
+	return balance, nil
+}
+
+// GetECBalanceAtHeight returns the balance of an entry credit address after the factoid and
+// entry credit blocks at the given height were applied
+func (s *State) GetECBalanceAtHeight(address [32]byte, height uint32) (int64, error) {
+	balance, heights, err := s.balanceReplay(height, func() ([]interfaces.AddressTransaction, error) {
+		return s.DB.FetchECAddressTransactions(primitives.NewHash(address[:]))
+	}, func(ss *SaveState) int64 {
+		return ss.ECBalancesP[address]
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, h := range heights {
+		fblock, err := s.DB.FetchFBlockByHeight(h)
+		if err != nil {
+			return 0, err
+		}
+		ecblock, err := s.DB.FetchECBlockByHeight(h)
+		if err != nil {
+			return 0, err
+		}
+		if fblock == nil || ecblock == nil {
+			return 0, fmt.Errorf("blocks at height %d not found", h)
+		}
+		balance += ecDelta(address, fblock, ecblock)
+	}
+	return balance, nil
+}
+
+// balanceReplay returns the balance to start from and the heights up to and including the given
+// height whose blocks have to be replayed on top of it. If the address index covers the height,
+// only the heights at which the address was touched are replayed, starting from nothing.
+// Otherwise the replay starts from the balances of the fastboot save state, which has to be at
+// or below the height, so that a call never replays the chain from the genesis block.
+func (s *State) balanceReplay(height uint32, fetch func() ([]interfaces.AddressTransaction, error), saved func(ss *SaveState) int64) (int64, []uint32, error) {
+	if height > s.GetHighestSavedBlk() {
+		return 0, nil, fmt.Errorf("height %d is above the highest saved block %d", height, s.GetHighestSavedBlk())
+	}
+
+	var heights []uint32
+	missing := &interfaces.BalanceHistoryError{Height: height}
+	if s.AddressIndex {
+		next, err := s.DB.FetchAddressIndexHeight()
+		if err != nil {
+			return 0, nil, err
+		}
+		missing.AddressIndex = true
+		missing.IndexHeight = next
+		if height < next {
+			txs, err := fetch()
+			if err != nil {
+				return 0, nil, err
+			}
+			for _, tx := range txs {
+				if tx.Height > height {
+					break
+				}
+				if len(heights) == 0 || heights[len(heights)-1] != tx.Height {
+					heights = append(heights, tx.Height)
+				}
+			}
+			return 0, heights, nil
+		}
+	}
+
+	ss := s.readBalanceSaveState()
+	if ss != nil {
+		missing.SaveState = true
+		missing.SaveStateHeight = ss.DBHeight
+	}
+	if ss == nil || ss.DBHeight > height {
+		return 0, nil, missing
+	}
+	for h := ss.DBHeight + 1; h <= height; h++ {
+		heights = append(heights, h)
+	}
+	return saved(ss), heights, nil
+}
+
+// readBalanceSaveState returns the save state of the fastboot file, nil if there is none. The
+// file is only parsed again once it has been rewritten
+func (s *State) readBalanceSaveState() *SaveState {
+	if !s.StateSaverStruct.FastBoot {
+		return nil
+	}
+	filename := NetworkIDToFilename(s.Network, s.StateSaverStruct.FastBootLocation)
+	info, err := os.Stat(filename)
+	if err != nil {
+		return nil
+	}
+
+	s.balanceSaveStateLock.Lock()
+	defer s.balanceSaveStateLock.Unlock()
+	if s.balanceSaveState == nil || !info.ModTime().Equal(s.balanceSaveStateTime) {
+		ss, err := ReadSaveState(filename)
+		if err != nil {
+			return nil
+		}
+		s.balanceSaveState = ss
+		s.balanceSaveStateTime = info.ModTime()
+	}
+	return s.balanceSaveState
+}
+
+// factoidDelta is the change the transactions of a factoid block make to the balance of an address
+func factoidDelta(address [32]byte, fblock interfaces.IFBlock) int64 {
+	var delta int64
+	for _, tx := range fblock.GetTransactions() {
+		for _, in := range tx.GetInputs() {
+			if in.GetAddress().Fixed() == address {
+				delta -= int64(in.GetAmount())
+			}
+		}
+		for _, out := range tx.GetOutputs() {
+			if out.GetAddress().Fixed() == address {
+				delta += int64(out.GetAmount())
+			}
+		}
+	}
+	return delta
+}
+
+// ecDelta is the change the purchases of a factoid block and the commits of an entry credit
+// block make to the balance of an entry credit address. Purchases are converted at the
+// exchange rate of the factoid block, the same way the factoid state processes them.
+func ecDelta(address [32]byte, fblock interfaces.IFBlock, ecblock interfaces.IEntryCreditBlock) int64 {
+	var delta int64
+	if rate := int64(fblock.GetExchRate()); rate > 0 {
+		for _, tx := range fblock.GetTransactions() {
+			for _, out := range tx.GetECOutputs() {
+				if out.GetAddress().Fixed() == address {
+					delta += int64(out.GetAmount()) / rate
+				}
+			}
+		}
+	}
+	for _, entry := range ecblock.GetEntries() {
+		switch entry.ECID() {
+		case constants.ECIDChainCommit:
+			t := entry.(*entryCreditBlock.CommitChain)
+			if t.ECPubKey.Fixed() == address {
+				delta -= int64(t.Credits)
+			}
+		case constants.ECIDEntryCommit:
+			t := entry.(*entryCreditBlock.CommitEntry)
+			if t.ECPubKey.Fixed() == address {
+				delta -= int64(t.Credits)
+			}
+		}
+	}
+	return delta
+}
@@ -0,0 +1,75 @@
+package state_test
+
+import (
+	"testing"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/testHelper"
+)
+
+func TestBalanceAtHeight(t *testing.T) {
+	s := testHelper.CreateAndPopulateTestState()
+	top := s.GetHighestSavedBlk()
+
+	// collect every address touched by the test chain
+	fct := map[[32]byte]bool{}
+	ec := map[[32]byte]bool{}
+	for h := uint32(0); h <= top; h++ {
+		fblock, err := s.DB.FetchFBlockByHeight(h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ecblock, err := s.DB.FetchECBlockByHeight(h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := s.DB.SaveAddressTransactions(h, fblock, ecblock); err != nil {
+			t.Fatal(err)
+		}
+		for _, tx := range fblock.GetTransactions() {
+			for _, out := range tx.GetOutputs() {
+				fct[out.GetAddress().Fixed()] = true
+			}
+			for _, out := range tx.GetECOutputs() {
+				ec[out.GetAddress().Fixed()] = true
+			}
+		}
+	}
+	if len(fct) == 0 || len(ec) == 0 {
+		t.Fatal("test chain has no transactions")
+	}
+
+	// without the address index or a fastboot save state the chain is not replayed from the genesis block
+	for adr := range fct {
+		expected := &interfaces.BalanceHistoryError{Height: top}
+		if _, err := s.GetFactoidBalanceAtHeight(adr, top); err == nil || err.Error() != expected.Error() {
+			t.Errorf("Expected %v without the address index, got %v", expected, err)
+		}
+	}
+	s.AddressIndex = true
+
+	for adr := range fct {
+		// the replayed balance at the top matches the balance of the factoid state
+		bal, err := s.GetFactoidBalanceAtHeight(adr, top)
+		if err != nil {
+			t.Error(err)
+		}
+		if expected := s.GetF(false, adr); bal != expected {
+			t.Errorf("Factoid balance at %d is %d, expected %d", top, bal, expected)
+		}
+	}
+
+	for adr := range ec {
+		bal, err := s.GetECBalanceAtHeight(adr, top)
+		if err != nil {
+			t.Error(err)
+		}
+		if expected := s.GetE(false, adr); bal != expected {
+			t.Errorf("EC balance at %d is %d, expected %d", top, bal, expected)
+		}
+	}
+
+	if _, err := s.GetFactoidBalanceAtHeight([32]byte{}, top+1); err == nil {
+		t.Error("Expected an error for a height above the highest saved block")
+	}
+}
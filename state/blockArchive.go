@@ -0,0 +1,355 @@
+package state
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+)
+
+// A block archive holds the whole blocks of a range of heights, each with the signatures
+// the authority set gave its directory block. It starts with ArchiveMagic and the network
+// ID of its blocks, followed by the blocks in height order, each prefixed by its length.
+
+// ArchiveMagic starts every block archive, the last byte is the version of the format
+var ArchiveMagic = []byte{'F', 'C', 'T', 'A', 'R', 'C', 'H', 1}
+
+// MaxArchiveBlockSize is the largest block an archive is trusted to hold
+const MaxArchiveBlockSize = 1 << 30
+
+var (
+	// ArchiveImportWindow is how many heights the import runs ahead of the highest saved block
+	ArchiveImportWindow uint32 = 500
+	// ArchiveImportTimeout is how long the import waits for the node to save the next block
+	// before it gives up on the archive
+	ArchiveImportTimeout = 5 * time.Minute
+)
+
+// WholeBlockFromDatabase returns the blocks, the entries and the signatures of a height. The
+// signatures are held by the admin block of the next height, so the head cannot be read.
+// Returns nil if there is no block at that height
+func WholeBlockFromDatabase(db interfaces.DBOverlaySimple, dbheight uint32) (*WholeBlock, error) {
+	dblk, err := db.FetchDBlockByHeight(dbheight)
+	if err != nil {
+		return nil, err
+	}
+	if dblk == nil {
+		return nil, nil
+	}
+	nextABlock, err := db.FetchABlockByHeight(dbheight + 1)
+	if err != nil {
+		return nil, err
+	}
+	if nextABlock == nil {
+		return nil, fmt.Errorf("Do not have signatures at height %d, the next admin block is missing", dbheight)
+	}
+
+	wb := NewWholeBlock()
+	wb.DBlock = dblk
+	if wb.ABlock, err = db.FetchABlock(dblk.GetDBEntries()[0].GetKeyMR()); err != nil {
+		return nil, err
+	}
+	if wb.ECBlock, err = db.FetchECBlock(dblk.GetDBEntries()[1].GetKeyMR()); err != nil {
+		return nil, err
+	}
+	if wb.FBlock, err = db.FetchFBlock(dblk.GetDBEntries()[2].GetKeyMR()); err != nil {
+		return nil, err
+	}
+	if wb.ABlock == nil || wb.ECBlock == nil || wb.FBlock == nil {
+		return nil, fmt.Errorf("Missing blocks at height %d", dbheight)
+	}
+
+	for _, v := range dblk.GetEBlockDBEntries() {
+		eblock, err := db.FetchEBlock(v.GetKeyMR())
+		if err != nil {
+			return nil, err
+		}
+		if eblock == nil {
+			return nil, fmt.Errorf("Missing entry block %x at height %d", v.GetKeyMR().Bytes()[:5], dbheight)
+		}
+		wb.AddEblock(eblock)
+		for _, hash := range eblock.GetEntryHashes() {
+			if hash.IsMinuteMarker() {
+				continue
+			}
+			// Pruned entries are left out, the node importing the archive asks its peers for them
+			entry, err := db.FetchEntry(hash)
+			if err != nil {
+				return nil, err
+			}
+			if entry != nil {
+				wb.AddIEBEntry(entry)
+			}
+		}
+	}
+
+	wb.SigList, err = dbSignatures(nextABlock)
+	if err != nil {
+		return nil, err
+	}
+	return wb, nil
+}
+
+// ExportArchive writes the heights from..to of the database as a block archive. Progress, if
+// set, is called after each height
+func ExportArchive(db interfaces.DBOverlaySimple, w io.Writer, from uint32, to uint32, progress func(uint32)) error {
+	if to < from {
+		return fmt.Errorf("Nothing to export from %d to %d", from, to)
+	}
+	var aw *ArchiveWriter
+	for h := from; h <= to; h++ {
+		wb, err := WholeBlockFromDatabase(db, h)
+		if err != nil {
+			return err
+		}
+		if wb == nil {
+			return fmt.Errorf("No directory block at height %d", h)
+		}
+		if aw == nil {
+			aw, err = NewArchiveWriter(w, wb.DBlock.GetHeader().GetNetworkID())
+			if err != nil {
+				return err
+			}
+		}
+		if err := aw.Write(wb); err != nil {
+			return err
+		}
+		if progress != nil {
+			progress(h)
+		}
+	}
+	return nil
+}
+
+// ArchiveWriter writes the blocks of a block archive
+type ArchiveWriter struct {
+	w io.Writer
+}
+
+// NewArchiveWriter writes the header of a block archive for the network
+func NewArchiveWriter(w io.Writer, networkID uint32) (*ArchiveWriter, error) {
+	header := make([]byte, len(ArchiveMagic)+4)
+	copy(header, ArchiveMagic)
+	binary.BigEndian.PutUint32(header[len(ArchiveMagic):], networkID)
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+	return &ArchiveWriter{w: w}, nil
+}
+
+// Write appends the block to the archive
+func (aw *ArchiveWriter) Write(wb *WholeBlock) error {
+	data, err := wb.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	var size [4]byte
+	binary.BigEndian.PutUint32(size[:], uint32(len(data)))
+	if _, err := aw.w.Write(size[:]); err != nil {
+		return err
+	}
+	_, err = aw.w.Write(data)
+	return err
+}
+
+// ArchiveReader reads the blocks of a block archive. Each block is checked to be whole, to follow
+// the one read before it, and to only hold signatures of its directory block. Whether the
+// signatures are the ones of the authority set is for the node to decide, as it processes the blocks
+type ArchiveReader struct {
+	NetworkID uint32
+
+	r    io.Reader
+	prev interfaces.IDirectoryBlock
+}
+
+// NewArchiveReader reads the header of a block archive
+func NewArchiveReader(r io.Reader) (*ArchiveReader, error) {
+	header := make([]byte, len(ArchiveMagic)+4)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("Not a block archive: %v", err)
+	}
+	if !bytes.Equal(header[:len(ArchiveMagic)], ArchiveMagic) {
+		return nil, fmt.Errorf("Not a block archive, or an unsupported version of the format")
+	}
+	return &ArchiveReader{NetworkID: binary.BigEndian.Uint32(header[len(ArchiveMagic):]), r: r}, nil
+}
+
+// Next returns the next block of the archive, or io.EOF once all of them were read
+func (ar *ArchiveReader) Next() (*WholeBlock, error) {
+	var size [4]byte
+	if _, err := io.ReadFull(ar.r, size[:]); err != nil {
+		if err == io.EOF {
+			return nil, err
+		}
+		return nil, fmt.Errorf("Truncated block archive: %v", err)
+	}
+	n := binary.BigEndian.Uint32(size[:])
+	if n > MaxArchiveBlockSize {
+		return nil, fmt.Errorf("Block of %d bytes is too large", n)
+	}
+	data := make([]byte, n)
+	if _, err := io.ReadFull(ar.r, data); err != nil {
+		return nil, fmt.Errorf("Truncated block archive: %v", err)
+	}
+
+	wb := NewWholeBlock()
+	rest, err := wb.UnmarshalBinaryData(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, fmt.Errorf("%d bytes left over after a block", len(rest))
+	}
+	if err := ar.check(wb); err != nil {
+		return nil, err
+	}
+	ar.prev = wb.DBlock
+	return wb, nil
+}
+
+// check returns an error if the block is not whole, holds a signature of something else, or does
+// not follow the previous one
+func (ar *ArchiveReader) check(wb *WholeBlock) error {
+	header := wb.DBlock.GetHeader()
+	height := header.GetDBHeight()
+	if header.GetNetworkID() != ar.NetworkID {
+		return fmt.Errorf("Height %d is for the network %x, not %x", height, header.GetNetworkID(), ar.NetworkID)
+	}
+	if ar.prev != nil {
+		if height != ar.prev.GetDatabaseHeight()+1 {
+			return fmt.Errorf("Height %d follows height %d", height, ar.prev.GetDatabaseHeight())
+		}
+		if !header.GetPrevKeyMR().IsSameAs(ar.prev.GetKeyMR()) {
+			return fmt.Errorf("Height %d does not link to the previous directory block", height)
+		}
+	}
+
+	dbEntries := wb.DBlock.GetDBEntries()
+	if len(dbEntries) < 3 {
+		return fmt.Errorf("Height %d is missing the admin, entry credit or factoid block", height)
+	}
+	if !dbEntries[0].GetKeyMR().IsSameAs(wb.ABlock.DatabasePrimaryIndex()) ||
+		!dbEntries[1].GetKeyMR().IsSameAs(wb.ECBlock.DatabasePrimaryIndex()) ||
+		!dbEntries[2].GetKeyMR().IsSameAs(wb.FBlock.DatabasePrimaryIndex()) {
+		return fmt.Errorf("Height %d holds an admin, entry credit or factoid block of another height", height)
+	}
+	keyMRs := make(map[[32]byte]bool)
+	for _, v := range wb.DBlock.GetEBlockDBEntries() {
+		keyMRs[v.GetKeyMR().Fixed()] = true
+	}
+	for _, eblock := range wb.EBlocks {
+		keyMR, err := eblock.KeyMR()
+		if err != nil {
+			return err
+		}
+		if !keyMRs[keyMR.Fixed()] {
+			return fmt.Errorf("Height %d holds the entry block %x of another height", height, keyMR.Bytes()[:5])
+		}
+	}
+
+	data, err := header.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	for _, sig := range wb.SigList {
+		if !sig.Verify(data) {
+			return fmt.Errorf("Height %d holds a signature that does not match its directory block", height)
+		}
+	}
+	return nil
+}
+
+// ImportArchive feeds the blocks of the archive file to the node once it has loaded its
+// database, as if a peer had sent them. The node checks the signatures of each block against
+// the authority set before saving it. The import stops at the first block it refuses
+func (s *State) ImportArchive(filename string) {
+	// Wait for loading from disk to finish
+	for !s.DBFinished {
+		time.Sleep(1 * time.Second)
+	}
+
+	f, err := os.Open(filename)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%20s Archive import failed: %v\n", s.FactomNodeName, err)
+		return
+	}
+	defer f.Close()
+
+	fmt.Fprintf(os.Stderr, "%20s Importing blocks from %s\n", s.FactomNodeName, filename)
+	if err := s.ImportArchiveFromReader(bufio.NewReaderSize(f, 1<<20)); err != nil {
+		fmt.Fprintf(os.Stderr, "%20s Archive import stopped at height %d: %v\n", s.FactomNodeName, s.GetHighestSavedBlk(), err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "%20s Archive import complete %d.\n", s.FactomNodeName, s.GetHighestSavedBlk())
+}
+
+// ImportArchiveFromReader feeds the blocks of the archive above the highest saved block to the
+// node, and returns once the node has saved all of them
+func (s *State) ImportArchiveFromReader(r io.Reader) error {
+	ar, err := NewArchiveReader(r)
+	if err != nil {
+		return err
+	}
+	if ar.NetworkID != s.GetNetworkID() {
+		return fmt.Errorf("The archive is for the network %x, the node is on %x", ar.NetworkID, s.GetNetworkID())
+	}
+
+	saved := s.GetHighestSavedBlk()
+	progress := time.Now()
+	// wait returns an error if the node has not saved a block in ArchiveImportTimeout
+	wait := func() error {
+		time.Sleep(10 * time.Millisecond)
+		if h := s.GetHighestSavedBlk(); h > saved {
+			saved = h
+			progress = time.Now()
+		} else if time.Since(progress) > ArchiveImportTimeout {
+			return fmt.Errorf("The node did not accept height %d, its signatures may not match the authority set", saved+1)
+		}
+		return nil
+	}
+
+	var sent uint32
+	for {
+		wb, err := ar.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		height := wb.DBlock.GetDatabaseHeight()
+		if height <= s.GetHighestSavedBlk() {
+			continue
+		}
+		if sent == 0 && height > s.GetHighestSavedBlk()+1 {
+			return fmt.Errorf("The archive starts at height %d, the node needs height %d first", height, s.GetHighestSavedBlk()+1)
+		}
+		for height > s.GetHighestSavedBlk()+ArchiveImportWindow || s.InMsgQueue().Length() > constants.INMSGQUEUE_MED {
+			if err := wait(); err != nil {
+				return err
+			}
+		}
+
+		msg := wb.BlockToDBStateMsg()
+		s.LogMessage("InMsgQueue", "enqueue_ImportArchive", msg)
+		s.InMsgQueue().Enqueue(msg)
+		sent = height
+		if height%1000 == 0 {
+			fmt.Fprintf(os.Stderr, "%20s Importing Block %7d from the archive, saved %d\n", s.FactomNodeName, height, s.GetHighestSavedBlk())
+		}
+	}
+
+	for s.GetHighestSavedBlk() < sent {
+		if err := wait(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package state_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/adminBlock"
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+	. "github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/testHelper"
+	"github.com/stretchr/testify/assert"
+)
+
+// createSignedBlocks returns the test block sets with each directory block signed by the key,
+// in the admin block of the next height
+func createSignedBlocks(key *primitives.PrivateKey) []*testHelper.BlockSet {
+	blocks := testHelper.CreateFullTestBlockSet()
+	for i := 1; i < len(blocks); i++ {
+		prev, set := blocks[i-1], blocks[i]
+		data, err := prev.DBlock.GetHeader().MarshalBinary()
+		if err != nil {
+			panic(err)
+		}
+		entry, err := adminBlock.NewDBSignatureEntry(primitives.NewZeroHash(), key.Sign(data))
+		if err != nil {
+			panic(err)
+		}
+		set.ABlock.AddABEntry(entry)
+		set.DBlock.SetABlockHash(set.ABlock)
+		set.DBlock.GetHeader().SetPrevKeyMR(prev.DBlock.GetKeyMR())
+		set.DBlock.GetHeader().SetPrevFullHash(prev.DBlock.GetFullHash())
+		set.DBlock.GetKeyMR()
+	}
+	return blocks
+}
+
+// createArchiveDatabase saves the block sets the way the node does
+func createArchiveDatabase(t *testing.T, blocks []*testHelper.BlockSet) *databaseOverlay.Overlay {
+	dbo := testHelper.CreateEmptyTestDatabaseOverlay()
+	assert.Nil(t, testHelper.SaveBlockSets(dbo, blocks))
+	return dbo
+}
+
+func TestBlockArchive(t *testing.T) {
+	key := primitives.RandomPrivateKey()
+	blocks := createSignedBlocks(key)
+	dbo := createArchiveDatabase(t, blocks)
+	defer dbo.Close()
+
+	head := uint32(len(blocks) - 1)
+	_, err := WholeBlockFromDatabase(dbo, head)
+	assert.NotNil(t, err, "the head has no signatures yet")
+
+	buf := new(bytes.Buffer)
+	var exported []uint32
+	err = ExportArchive(dbo, buf, 1, head-1, func(h uint32) { exported = append(exported, h) })
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Len(t, exported, int(head-1))
+
+	ar, err := NewArchiveReader(bytes.NewReader(buf.Bytes()))
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, blocks[0].DBlock.GetHeader().GetNetworkID(), ar.NetworkID)
+	for h := uint32(1); h < head; h++ {
+		wb, err := ar.Next()
+		if !assert.Nil(t, err, "height %d", h) {
+			return
+		}
+		set := blocks[h]
+		assert.Equal(t, h, wb.DBlock.GetDatabaseHeight())
+		assert.True(t, set.DBlock.GetKeyMR().IsSameAs(wb.DBlock.GetKeyMR()))
+		assert.True(t, set.ABlock.DatabasePrimaryIndex().IsSameAs(wb.ABlock.DatabasePrimaryIndex()))
+		assert.Len(t, wb.EBlocks, 2)
+		assert.Len(t, wb.Entries, len(set.Entries))
+		if assert.Len(t, wb.SigList, 1) {
+			assert.Equal(t, key.Pub[:], wb.SigList[0].GetKey())
+		}
+	}
+	_, err = ar.Next()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestBlockArchiveRefused(t *testing.T) {
+	key := primitives.RandomPrivateKey()
+	blocks := createSignedBlocks(key)
+	dbo := createArchiveDatabase(t, blocks)
+	defer dbo.Close()
+
+	read := func(data []byte) error {
+		ar, err := NewArchiveReader(bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		for {
+			if _, err := ar.Next(); err != nil {
+				if err == io.EOF {
+					return nil
+				}
+				return err
+			}
+		}
+	}
+	archive := func(wbs ...*WholeBlock) []byte {
+		buf := new(bytes.Buffer)
+		aw, err := NewArchiveWriter(buf, blocks[0].DBlock.GetHeader().GetNetworkID())
+		assert.Nil(t, err)
+		for _, wb := range wbs {
+			assert.Nil(t, aw.Write(wb))
+		}
+		return buf.Bytes()
+	}
+
+	var wbs []*WholeBlock
+	for h := uint32(1); h < 5; h++ {
+		wb, err := WholeBlockFromDatabase(dbo, h)
+		assert.Nil(t, err)
+		wbs = append(wbs, wb)
+	}
+	good := archive(wbs...)
+	assert.Nil(t, read(good))
+
+	assert.NotNil(t, read([]byte("not an archive")), "not an archive")
+	assert.NotNil(t, read(good[:len(good)-10]), "truncated")
+	assert.NotNil(t, read(archive(wbs[0], wbs[2])), "a height is missing")
+
+	// a signature of another directory block
+	forged := *wbs[1]
+	forged.SigList = wbs[2].SigList
+	assert.NotNil(t, read(archive(wbs[0], &forged)), "forged signature")
+
+	// the blocks of another height
+	mixed := *wbs[1]
+	mixed.FBlock = wbs[2].FBlock
+	assert.NotNil(t, read(archive(wbs[0], &mixed)), "mixed blocks")
+}
+
+func TestImportArchiveFromReader(t *testing.T) {
+	s := testHelper.CreateAndPopulateTestState()
+
+	// an archive of another network is refused
+	buf := new(bytes.Buffer)
+	_, err := NewArchiveWriter(buf, s.GetNetworkID()+1)
+	assert.Nil(t, err)
+	assert.NotNil(t, s.ImportArchiveFromReader(bytes.NewReader(buf.Bytes())))
+}
@@ -0,0 +1,30 @@
+package state
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/FactomProject/factomd/database/compact"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+)
+
+// CompactDatabase compacts the database while the node keeps running. It returns the space of
+// each bucket before and after. Bolt databases are refused, as compacting one blocks every read
+// and write of the node until it is done, they are compacted offline by Utilities/DatabaseCompact.
+func (s *State) CompactDatabase() (interface{}, error) {
+	if s.DBType == "Bolt" {
+		return nil, fmt.Errorf("Bolt databases can only be compacted with the node stopped, by Utilities/DatabaseCompact")
+	}
+	dbo, ok := s.DB.(*databaseOverlay.Overlay)
+	if !ok {
+		return nil, fmt.Errorf("The database does not support compaction")
+	}
+	r, err := compact.Run(dbo, nil, func(msg string) {
+		fmt.Fprintf(os.Stderr, "%20s Database compaction: %s\n", s.FactomNodeName, msg)
+	})
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(os.Stderr, "%20s Database compacted from %d to %d bytes in %s\n", s.FactomNodeName, r.Before, r.After, r.Duration)
+	return r, nil
+}
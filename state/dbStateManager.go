@@ -1515,7 +1515,26 @@ func (list *DBStateList) SaveDBStateToDB(d *DBState) (progress bool) {
 		panic(err.Error())
 	}
 
-	if err := list.State.DB.ExecuteMultiBatch(); err != nil {
+	// The address index of the block is saved with it once the backfill has caught up, before
+	// that the backfill indexes the block after it is saved
+	if list.State.AddressIndex {
+		list.State.addressIndexLock.Lock()
+		next, err := list.State.DB.FetchAddressIndexHeight()
+		if err != nil {
+			panic(err.Error())
+		}
+		if next == uint32(dbheight) {
+			if err := list.State.DB.ProcessAddressTransactionsMultiBatch(next, d.FactoidBlock, d.EntryCreditBlock); err != nil {
+				panic(err.Error())
+			}
+		}
+	}
+
+	err := list.State.DB.ExecuteMultiBatch()
+	if list.State.AddressIndex {
+		list.State.addressIndexLock.Unlock()
+	}
+	if err != nil {
 		panic(err.Error())
 	}
 
@@ -0,0 +1,64 @@
+package state
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+	"github.com/FactomProject/factomd/database/securedb"
+)
+
+// EncryptedBuckets parses a DBEncryption setting. It returns whether the database is encrypted,
+// and the buckets that are, nil for all of them
+func EncryptedBuckets(setting string) (bool, [][]byte) {
+	switch strings.ToLower(strings.TrimSpace(setting)) {
+	case "", "none":
+		return false, nil
+	case "all":
+		return true, nil
+	}
+	var buckets [][]byte
+	for _, name := range strings.Split(setting, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			buckets = append(buckets, []byte(name))
+		}
+	}
+	return len(buckets) > 0, buckets
+}
+
+// encryptDatabase wraps the database in the encryption DBEncryption asks for. An encrypted
+// database is refused when the encryption is off, and an existing plain one when it is on, as
+// Utilities/DatabaseEncrypt has to convert them first
+func (s *State) encryptDatabase(dbase interfaces.IDatabase) (interfaces.IDatabase, error) {
+	on, buckets := EncryptedBuckets(s.DBEncryption)
+	encrypted, err := securedb.IsEncrypted(dbase)
+	if err != nil {
+		return nil, err
+	}
+	if !on {
+		if encrypted {
+			return nil, fmt.Errorf("The database is encrypted, set DBEncryption to open it")
+		}
+		return dbase, nil
+	}
+	if !encrypted {
+		it, err := dbase.Iterate(databaseOverlay.DIRECTORYBLOCK, interfaces.IterateOptions{Limit: 1})
+		if err != nil {
+			return nil, err
+		}
+		saved := it.Next()
+		it.Release()
+		if saved {
+			return nil, fmt.Errorf("The database is not encrypted, convert it with Utilities/DatabaseEncrypt first")
+		}
+	}
+
+	passphrase, err := securedb.LoadPassphrase(s.DBEncryptionKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(os.Stderr, "%20s Database encryption: %s\n", s.FactomNodeName, s.DBEncryption)
+	return securedb.NewEncryptedDBFromDatabase(dbase, passphrase, buckets)
+}
@@ -0,0 +1,27 @@
+package state_test
+
+import (
+	"testing"
+
+	. "github.com/FactomProject/factomd/state"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncryptedBuckets(t *testing.T) {
+	for _, setting := range []string{"", "none", " None "} {
+		on, buckets := EncryptedBuckets(setting)
+		assert.False(t, on, setting)
+		assert.Nil(t, buckets, setting)
+	}
+
+	on, buckets := EncryptedBuckets("all")
+	assert.True(t, on)
+	assert.Nil(t, buckets)
+
+	on, buckets = EncryptedBuckets("KeyValueStore, DBlock,")
+	assert.True(t, on)
+	assert.Equal(t, [][]byte{[]byte("KeyValueStore"), []byte("DBlock")}, buckets)
+
+	on, _ = EncryptedBuckets(" , ")
+	assert.False(t, on)
+}
@@ -19,6 +19,11 @@ func (s *State) WriteEntries() {
 			if err = s.DB.InsertEntry(entry); err != nil {
 				panic(err)
 			}
+			if s.ExtIDIndex {
+				if err = s.DB.SaveEntryExtIDs(entry); err != nil {
+					panic(err)
+				}
+			}
 		}
 	}
 }
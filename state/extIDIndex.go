@@ -0,0 +1,72 @@
+package state
+
+import (
+	"time"
+)
+
+// ExtIDIndexInterval is how long the ExtID indexer waits for new blocks once it has caught up
+var ExtIDIndexInterval = time.Second
+
+// IndexEntryExtIDs backfills the optional ExtID index. New entries are indexed by the entry
+// writer as they are saved, this follows the heights whose entries are all in the database and
+// indexes the entries of the ones that are not in the index yet, starting from the genesis block
+// for databases that existed before the index was enabled. Entries that were pruned before they
+// were indexed have no ExtIDs left to index, the backfill passes over them and they can not be
+// found by ExtID.
+// Panics on database errors
+func (s *State) IndexEntryExtIDs() {
+	if !s.ExtIDIndex {
+		return
+	}
+
+	next, err := s.DB.FetchExtIDIndexHeight()
+	if err != nil {
+		panic(err)
+	}
+
+	for {
+		for next <= s.GetEntryBlockDBHeightComplete() {
+			dblock, err := s.DB.FetchDBlockByHeight(next)
+			if err != nil {
+				panic(err)
+			}
+			if dblock == nil {
+				break
+			}
+
+			for _, dbEntry := range dblock.GetEBlockDBEntries() {
+				eblock, err := s.DB.FetchEBlock(dbEntry.GetKeyMR())
+				if err != nil {
+					panic(err)
+				}
+				if eblock == nil {
+					continue
+				}
+				for _, hash := range eblock.GetEntryHashes() {
+					if hash.IsMinuteMarker() {
+						continue
+					}
+					entry, err := s.DB.FetchEntry(hash)
+					if err != nil {
+						panic(err)
+					}
+					// nil for a pruned entry, which is skipped
+					if err := s.DB.SaveEntryExtIDs(entry); err != nil {
+						panic(err)
+					}
+				}
+			}
+
+			next++
+			if err := s.DB.SaveExtIDIndexHeight(next); err != nil {
+				panic(err)
+			}
+		}
+		time.Sleep(ExtIDIndexInterval)
+	}
+}
+
+// IsExtIDIndexEnabled returns true if the node maintains the ExtID index
+func (s *State) IsExtIDIndexEnabled() bool {
+	return s.ExtIDIndex
+}
@@ -0,0 +1,69 @@
+package state
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/FactomProject/factomd/common/constants/runstate"
+)
+
+const (
+	// defaultLiveFeedReplayRate is the number of directory blocks replayed per second when no rate is given
+	defaultLiveFeedReplayRate = 10
+	maxLiveFeedReplayRate     = 1000
+)
+
+// StartLiveFeedReplay sends the directory blocks from start through end out of the database to
+// the live feed receiver, each followed by the events derived from it, so that a new receiver can
+// catch up on the history. The replay runs in the background, alongside the live events of the
+// receiver, at no more than blocksPerSecond blocks a second. It ends at the highest block in the
+// database when it starts, or at the first block the database does not hold
+func (s *State) StartLiveFeedReplay(receiver string, start uint32, end uint32, blocksPerSecond int) error {
+	if s.EventService == nil || !s.EventService.HasReceiver(receiver) {
+		return fmt.Errorf("There is no live feed receiver named %q", receiver)
+	}
+	if end < start {
+		return fmt.Errorf("The end height %d is below the start height %d", end, start)
+	}
+	if blocksPerSecond > maxLiveFeedReplayRate {
+		return fmt.Errorf("The replay rate can be at most %d blocks per second", maxLiveFeedReplayRate)
+	}
+	if blocksPerSecond <= 0 {
+		blocksPerSecond = defaultLiveFeedReplayRate
+	}
+
+	go s.replayLiveFeed(receiver, start, end, blocksPerSecond)
+	return nil
+}
+
+func (s *State) replayLiveFeed(receiver string, start uint32, end uint32, blocksPerSecond int) {
+	// Wait for loading from disk to finish
+	for !s.DBFinished {
+		time.Sleep(1 * time.Second)
+	}
+	// Blocks saved from here on are sent live
+	if head, err := s.DB.FetchDBlockHead(); err == nil && head != nil && end > head.GetDatabaseHeight() {
+		end = head.GetDatabaseHeight()
+	}
+	fmt.Fprintf(os.Stderr, "%20s Replaying blocks %d to %d to live feed receiver %q\n", s.FactomNodeName, start, end, receiver)
+
+	ticker := time.NewTicker(time.Second / time.Duration(blocksPerSecond))
+	defer ticker.Stop()
+	for height := start; height <= end; height++ {
+		if s.GetRunState() > runstate.Running {
+			return
+		}
+		msg := s.loadDBStateMsg(height)
+		if msg == nil {
+			fmt.Fprintf(os.Stderr, "%20s Live feed replay to %q stopped, block %d is not in the database\n", s.FactomNodeName, receiver, height)
+			return
+		}
+		if err := s.EventService.EmitHistoryDirectoryBlockCommit(receiver, msg); err != nil {
+			fmt.Fprintf(os.Stderr, "%20s Live feed replay to %q stopped at block %d: %v\n", s.FactomNodeName, receiver, height, err)
+			return
+		}
+		<-ticker.C
+	}
+	fmt.Fprintf(os.Stderr, "%20s Live feed replay to %q complete.\n", s.FactomNodeName, receiver)
+}
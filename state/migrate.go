@@ -0,0 +1,42 @@
+package state
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+)
+
+// MigrateDatabase brings the database up to the current schema version before the node loads
+// it. Long migrations report their progress every few seconds, and resume after a restart.
+func (s *State) MigrateDatabase() error {
+	dbo, ok := s.DB.(*databaseOverlay.Overlay)
+	if !ok {
+		return nil
+	}
+
+	var last time.Time
+	err := dbo.Migrate(func(p databaseOverlay.MigrationProgress) {
+		if p.Step < p.Steps && time.Since(last) < 5*time.Second {
+			return
+		}
+		last = time.Now()
+		if p.Steps == 0 {
+			fmt.Fprintf(os.Stderr, "%20s Database migrated to schema version %d: %s\n", s.FactomNodeName, p.Version, p.Description)
+			return
+		}
+		fmt.Fprintf(os.Stderr, "%20s Database migration to schema version %d: %s, %d of %d (%.1f%%)\n",
+			s.FactomNodeName, p.Version, p.Description, p.Step, p.Steps, 100*float64(p.Step)/float64(p.Steps))
+	})
+	if err != nil {
+		return err
+	}
+
+	version, _, err := dbo.FetchSchemaVersion()
+	if err != nil {
+		return err
+	}
+	s.Println("Database schema version:", version)
+	return nil
+}
@@ -40,12 +40,14 @@ func PrintState(state *State) {
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "LogPath", state.LogPath)
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "LdbPath", state.LdbPath)
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "BoltDBPath", state.BoltDBPath)
+	str = fmt.Sprintf("%s %35s = %+v\n", str, "BadgerDBPath", state.BadgerDBPath)
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "LogLevel", state.LogLevel)
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "ConsoleLogLevel", state.ConsoleLogLevel)
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "NodeMode", state.NodeMode)
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "DBType", state.DBType)
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "CloneDBType", state.CloneDBType)
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "ExportData", state.ExportData)
+	str = fmt.Sprintf("%s %35s = %+v\n", str, "DBEncryption", state.DBEncryption)
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "ExportDataSubpath", state.ExportDataSubpath)
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "LocalServerPrivKey", state.LocalServerPrivKey)
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "DirectoryBlockInSeconds", state.DirectoryBlockInSeconds)
@@ -117,6 +119,18 @@ func PrintState(state *State) {
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "FactomdTLSCertFile", state.FactomdTLSCertFile)
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "FactomdLocations", state.FactomdLocations)
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "CorsDomains", state.CorsDomains)
+	str = fmt.Sprintf("%s %35s = %+v\n", str, "ApiBatchLimit", state.ApiBatchLimit)
+	str = fmt.Sprintf("%s %35s = %+v\n", str, "ApiBatchWorkers", state.ApiBatchWorkers)
+	str = fmt.Sprintf("%s %35s = %+v\n", str, "AddressIndex", state.AddressIndex)
+	str = fmt.Sprintf("%s %35s = %+v\n", str, "ExtIDIndex", state.ExtIDIndex)
+	str = fmt.Sprintf("%s %35s = %+v\n", str, "PruneEntriesAfter", state.PruneEntriesAfter)
+	str = fmt.Sprintf("%s %35s = %+v\n", str, "ApiReadRateLimit", state.ApiReadRateLimit)
+	str = fmt.Sprintf("%s %35s = %+v\n", str, "ApiReadRateBurst", state.ApiReadRateBurst)
+	str = fmt.Sprintf("%s %35s = %+v\n", str, "ApiWriteRateLimit", state.ApiWriteRateLimit)
+	str = fmt.Sprintf("%s %35s = %+v\n", str, "ApiWriteRateBurst", state.ApiWriteRateBurst)
+	str = fmt.Sprintf("%s %35s = %+v\n", str, "ApiRateLimitKey", state.ApiRateLimitKey)
+	str = fmt.Sprintf("%s %35s = %+v\n", str, "ApiReadOnly", state.ApiReadOnly)
+	str = fmt.Sprintf("%s %35s = %+v\n", str, "DebugApiPort", state.DebugApiPort)
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "StartDelay", state.StartDelay)
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "StartDelayLimit", state.StartDelayLimit)
 	str = fmt.Sprintf("%s %35s = %+v\n", str, "RunLeader", state.RunLeader)
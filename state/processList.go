@@ -1169,6 +1169,9 @@ func (p *ProcessList) AddToProcessList(s *State, ack *messages.Ack, m interfaces
 	s.MissingMessageResponseHandler.NotifyNewMsgPair(ack, m)
 
 	s.EventService.EmitStateChangeEvent(m, eventmessages.EntityState_ACCEPTED)
+	if transaction, ok := m.(*messages.FactoidTransaction); ok {
+		s.EventService.EmitFactoidTransactionEvent(transaction.GetTransaction(), eventmessages.EntityState_ACCEPTED, p.DBHeight, int(ack.Minute)+1)
+	}
 }
 
 func (p *ProcessList) ContainsDBSig(serverID interfaces.IHash) bool {
@@ -0,0 +1,77 @@
+package state
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/database/databaseOverlay"
+)
+
+// EntryPruneInterval is how long the pruner waits for new blocks once it has caught up
+var EntryPruneInterval = time.Second
+
+// keepsEntryContent returns true for the entry blocks whose entries the node reads back to
+// rebuild its state: the genesis blocks, the identity and FER chains, and the anchor chains
+func keepsEntryContent(eb interfaces.IEntryBlock) bool {
+	if eb.GetDatabaseHeight() < 2 {
+		return true
+	}
+	cid := eb.GetChainID().Bytes()
+	if bytes.HasPrefix(cid, []byte{0x88, 0x88, 0x88}) || bytes.HasPrefix(cid, []byte{0x11, 0x11, 0x11}) {
+		return true
+	}
+	return databaseOverlay.ValidAnchorChains[eb.GetChainID().String()]
+}
+
+// PruneEntries drops the content of the entries once they are PruneEntriesAfter directory
+// blocks old. All blocks are kept, as are the hashes of the pruned entries, so the node still
+// follows and validates the chain. Heights are pruned once all their entries are in the
+// database, the progress is saved so a restart picks up where the pruner stopped.
+// Panics on database errors
+func (s *State) PruneEntries() {
+	if s.PruneEntriesAfter <= 0 {
+		return
+	}
+
+	next, err := s.DB.FetchEntryPruneHeight()
+	if err != nil {
+		panic(err)
+	}
+
+	for {
+		for int64(next)+int64(s.PruneEntriesAfter) <= int64(s.GetEntryBlockDBHeightComplete()) {
+			dblock, err := s.DB.FetchDBlockByHeight(next)
+			if err != nil {
+				panic(err)
+			}
+			if dblock == nil {
+				break
+			}
+
+			for _, dbEntry := range dblock.GetEBlockDBEntries() {
+				eblock, err := s.DB.FetchEBlock(dbEntry.GetKeyMR())
+				if err != nil {
+					panic(err)
+				}
+				if eblock == nil || keepsEntryContent(eblock) {
+					continue
+				}
+				for _, hash := range eblock.GetEntryHashes() {
+					if hash.IsMinuteMarker() {
+						continue
+					}
+					if err := s.DB.PruneEntry(hash); err != nil {
+						panic(err)
+					}
+				}
+			}
+
+			next++
+			if err := s.DB.SaveEntryPruneHeight(next); err != nil {
+				panic(err)
+			}
+		}
+		time.Sleep(EntryPruneInterval)
+	}
+}
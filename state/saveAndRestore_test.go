@@ -5,12 +5,16 @@
 package state_test
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/FactomProject/factomd/common/identity"
 	"github.com/FactomProject/factomd/common/primitives"
 	"github.com/FactomProject/factomd/common/primitives/random"
 	. "github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/testHelper"
 )
 
 func TestPushPopBalanceMap(t *testing.T) {
@@ -383,3 +387,48 @@ func TestSaveRestore(t *testing.T) {
 	}
 
 }
+
+func TestReadSaveState(t *testing.T) {
+	s := testHelper.CreateAndPopulateTestState()
+	d := s.DBStates.Get(int(s.GetLLeaderHeight()))
+	d.SaveStruct = SaveFactomdState(s, d)
+	if d.SaveStruct == nil {
+		t.Fatal("Expected a SaveState at the leader height")
+	}
+	// only saved states are written to the fastboot file
+	d.Saved = true
+	d.Locked = true
+	list := new(DBStateList)
+	list.State = s
+	list.DBStates = []*DBState{d}
+	b, err := list.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b = append(primitives.Sha(b).Bytes(), b...)
+	dir, err := ioutil.TempDir("", "fastboot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "FastBoot_LOCAL.db")
+	if err := ioutil.WriteFile(filename, b, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ss, err := ReadSaveState(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ss.DBHeight != d.SaveStruct.DBHeight || len(ss.FactoidBalancesP) != len(s.FactoidBalancesP) {
+		t.Errorf("Expected the SaveState at %v with %v balances, got %v with %v", d.SaveStruct.DBHeight, len(s.FactoidBalancesP), ss.DBHeight, len(ss.FactoidBalancesP))
+	}
+
+	b[len(b)-1]++
+	if err := ioutil.WriteFile(filename, b, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadSaveState(filename); err == nil {
+		t.Errorf("Expected a corrupted fastboot file to be refused")
+	}
+}
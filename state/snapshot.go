@@ -0,0 +1,155 @@
+package state
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/database/snapshot"
+	"github.com/FactomProject/factomd/util"
+)
+
+// snapshotDatabasePath returns the path of the database relative to the database path of the node
+func (s *State) snapshotDatabasePath() (string, error) {
+	switch s.DBType {
+	case "LDB":
+		return filepath.Join(s.Network, "factoid_level.db"), nil
+	case "Bolt":
+		return filepath.Join(s.Network, "FactomBolt.db"), nil
+	case "Badger":
+		return filepath.Join(s.Network, "factoid_badger.db"), nil
+	}
+	return "", fmt.Errorf("Snapshots of %v databases are not supported", s.DBType)
+}
+
+// SnapshotPath resolves the path of a snapshot or of its tar file. Relative paths are taken from
+// the home directory of the node, and the path has to lie below it, following symbolic links.
+func (s *State) SnapshotPath(path string) (string, error) {
+	cfg, ok := s.GetCfg().(*util.FactomdConfig)
+	if !ok || cfg == nil || cfg.App.HomeDir == "" {
+		return "", fmt.Errorf("The home directory of the node is not known")
+	}
+	home, err := filepath.Abs(cfg.App.HomeDir)
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(home, path)
+	}
+	path = filepath.Clean(path)
+
+	realHome, err := resolveExistingPath(home)
+	if err != nil {
+		return "", err
+	}
+	realPath, err := resolveExistingPath(path)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(realHome, realPath)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%v is not below the home directory %v of the node", path, home)
+	}
+	return path, nil
+}
+
+// resolveExistingPath follows the symbolic links of the part of the path that exists
+func resolveExistingPath(path string) (string, error) {
+	rest := ""
+	for {
+		real, err := filepath.EvalSymlinks(path)
+		if err == nil {
+			return filepath.Join(real, rest), nil
+		}
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return "", err
+		}
+		rest = filepath.Join(filepath.Base(path), rest)
+		path = parent
+	}
+}
+
+// SnapshotDatabase writes a point-in-time copy of the database into the directory, which has to
+// be new or empty and below the home directory of the node, along with the fastboot file, then
+// verifies the copy. The node keeps running while the copy is written. It returns the manifest
+// of the snapshot.
+func (s *State) SnapshotDatabase(dir string) (interface{}, error) {
+	snapshotter, ok := s.DB.(interfaces.ISnapshotDatabase)
+	if !ok {
+		return nil, fmt.Errorf("The database does not support snapshots")
+	}
+	dir, err := s.SnapshotPath(dir)
+	if err != nil {
+		return nil, err
+	}
+	if entries, err := ioutil.ReadDir(dir); err == nil && len(entries) > 0 {
+		return nil, fmt.Errorf("%v is not empty", dir)
+	}
+
+	m := new(snapshot.Manifest)
+	m.DBType = s.DBType
+	m.Network = s.Network
+	path, err := s.snapshotDatabasePath()
+	if err != nil {
+		return nil, err
+	}
+	m.Database = path
+
+	// The state saver is held while the view is taken, so the fastboot file is not rewritten
+	// halfway through. The database only grows, so the file is at or below the view.
+	sss := &s.StateSaverStruct
+	var fastboot []byte
+	sss.Mutex.Lock()
+	fastbootFile := NetworkIDToFilename(s.Network, sss.FastBootLocation)
+	if sss.FastBoot {
+		fastboot, _ = ioutil.ReadFile(fastbootFile)
+		m.FastBootHeight = sss.FileDBHt
+	}
+	snap, err := snapshotter.Snapshot()
+	sss.Mutex.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	m.Created = time.Now()
+	m.Records, err = snap.CopyTo(filepath.Join(dir, m.Database))
+	snap.Release()
+	if err != nil {
+		return nil, err
+	}
+
+	head, err := snapshot.Head(m.DBType, filepath.Join(dir, m.Database), s.encryptDatabase)
+	if err != nil {
+		return nil, err
+	}
+	m.DBHeight = head.GetDatabaseHeight()
+	m.KeyMR = head.GetKeyMR().String()
+
+	// A fastboot file ahead of the saved blocks would not match the database, the node rebuilds
+	// its state from the blocks instead
+	if len(fastboot) > 0 && m.FastBootHeight <= m.DBHeight {
+		m.FastBoot = filepath.Base(fastbootFile)
+		err = ioutil.WriteFile(filepath.Join(dir, m.FastBoot), fastboot, 0644)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		m.FastBootHeight = 0
+	}
+
+	err = snapshot.WriteManifest(dir, m)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(os.Stderr, "%20s Database snapshot of %d records at height %d written to %s\n", s.FactomNodeName, m.Records, m.DBHeight, dir)
+	return snapshot.Verify(dir, s.encryptDatabase)
+}
@@ -0,0 +1,57 @@
+package state_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/FactomProject/factomd/state"
+	"github.com/FactomProject/factomd/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotPath(t *testing.T) {
+	home, err := ioutil.TempDir("", "factomd-home")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+	outside, err := ioutil.TempDir("", "factomd-outside")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(outside)
+	if err := os.Symlink(outside, filepath.Join(home, "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	s := new(State)
+	s.Cfg = util.ReadConfig("")
+	s.Cfg.(*util.FactomdConfig).App.HomeDir = home + "/"
+
+	path, err := s.SnapshotPath("snapshots/one")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, "snapshots", "one"), path)
+
+	path, err = s.SnapshotPath(filepath.Join(home, "one.tar"))
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, "one.tar"), path)
+
+	for _, bad := range []string{
+		home,
+		".",
+		"..",
+		"snapshots/../../one",
+		filepath.Join(outside, "one"),
+		"/tmp/one",
+		"link/one",
+	} {
+		_, err = s.SnapshotPath(bad)
+		assert.Error(t, err, bad)
+	}
+
+	s.Cfg = nil
+	_, err = s.SnapshotPath("snapshots/one")
+	assert.Error(t, err)
+}
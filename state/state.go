@@ -29,6 +29,7 @@ import (
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/messages"
 	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/badgerdb"
 	"github.com/FactomProject/factomd/database/boltdb"
 	"github.com/FactomProject/factomd/database/databaseOverlay"
 	"github.com/FactomProject/factomd/database/leveldb"
@@ -64,6 +65,7 @@ type State struct {
 	LogPath         string
 	LdbPath         string
 	BoltDBPath      string
+	BadgerDBPath    string
 	LogLevel        string
 	ConsoleLogLevel string
 	NodeMode        string
@@ -76,6 +78,10 @@ type State struct {
 	ExportData        bool
 	ExportDataSubpath string
 
+	// DBEncryption is none, all, or the comma separated buckets encrypted in the database
+	DBEncryption        string
+	DBEncryptionKeyFile string
+
 	LogBits int64 // Bit zero is for logging the Directory Block on DBSig [5]
 
 	DBStatesSent            []*interfaces.DBStateSent
@@ -206,6 +212,38 @@ type State struct {
 	FactomdLocations   string
 
 	CorsDomains []string
+
+	// Maximum size of a v2 API batch call and the number of workers executing it
+	ApiBatchLimit   int
+	ApiBatchWorkers int
+
+	// Maintain the optional index of the transactions touching each address
+	AddressIndex bool
+	// Held while the index of a block is written, by the block save or by the backfill
+	addressIndexLock sync.Mutex
+	// The save state of the fastboot file that balances at a height are replayed from, and the
+	// modification time of the file it was read from
+	balanceSaveState     *SaveState
+	balanceSaveStateTime time.Time
+	balanceSaveStateLock sync.Mutex
+
+	// Maintain the optional index of the entries of each chain by their ExtIDs
+	ExtIDIndex bool
+
+	// Drop the content of entries this many directory blocks old, 0 keeps everything
+	PruneEntriesAfter int
+
+	// Per client rate limits of the API, in requests per second, and how clients are identified
+	ApiReadRateLimit  int
+	ApiReadRateBurst  int
+	ApiWriteRateLimit int
+	ApiWriteRateBurst int
+	ApiRateLimitKey   string
+
+	// Reject write and debug calls, and the port of the debug API, 0 serves it on the API port
+	ApiReadOnly  bool
+	DebugApiPort int
+
 	// Server State
 	StartDelay      int64 // Time in Milliseconds since the last DBState was applied
 	StartDelayLimit int64
@@ -498,6 +536,7 @@ func (s *State) Clone(cloneNumber int) interfaces.IState {
 	newState.JournalFile = s.LogPath + "/journal" + number + ".log"
 	newState.Journaling = s.Journaling
 	newState.BoltDBPath = s.BoltDBPath + "/Sim" + number
+	newState.BadgerDBPath = s.BadgerDBPath + "/Sim" + number
 	newState.LogLevel = s.LogLevel
 	newState.ConsoleLogLevel = s.ConsoleLogLevel
 	newState.NodeMode = "FULL"
@@ -505,6 +544,8 @@ func (s *State) Clone(cloneNumber int) interfaces.IState {
 	newState.DBType = s.CloneDBType
 	newState.CheckChainHeads = s.CheckChainHeads
 	newState.ExportData = s.ExportData
+	newState.DBEncryption = s.DBEncryption
+	newState.DBEncryptionKeyFile = s.DBEncryptionKeyFile
 	newState.ExportDataSubpath = s.ExportDataSubpath + "sim-" + number
 	newState.Network = s.Network
 	newState.MainNetworkPort = s.MainNetworkPort
@@ -577,6 +618,18 @@ func (s *State) Clone(cloneNumber int) interfaces.IState {
 
 	newState.FastSaveRate = s.FastSaveRate
 	newState.CorsDomains = s.CorsDomains
+	newState.ApiBatchLimit = s.ApiBatchLimit
+	newState.ApiBatchWorkers = s.ApiBatchWorkers
+	newState.AddressIndex = s.AddressIndex
+	newState.ExtIDIndex = s.ExtIDIndex
+	newState.PruneEntriesAfter = s.PruneEntriesAfter
+	newState.ApiReadRateLimit = s.ApiReadRateLimit
+	newState.ApiReadRateBurst = s.ApiReadRateBurst
+	newState.ApiWriteRateLimit = s.ApiWriteRateLimit
+	newState.ApiWriteRateBurst = s.ApiWriteRateBurst
+	newState.ApiRateLimitKey = s.ApiRateLimitKey
+	newState.ApiReadOnly = s.ApiReadOnly
+	newState.DebugApiPort = s.DebugApiPort
 	switch newState.DBType {
 	case "LDB":
 		newState.StateSaverStruct.FastBoot = s.StateSaverStruct.FastBoot
@@ -586,6 +639,10 @@ func (s *State) Clone(cloneNumber int) interfaces.IState {
 		newState.StateSaverStruct.FastBoot = s.StateSaverStruct.FastBoot
 		newState.StateSaverStruct.FastBootLocation = newState.BoltDBPath
 		break
+	case "Badger":
+		newState.StateSaverStruct.FastBoot = s.StateSaverStruct.FastBoot
+		newState.StateSaverStruct.FastBootLocation = newState.BadgerDBPath
+		break
 	}
 	if globals.Params.WriteProcessedDBStates {
 		path := filepath.Join(newState.LdbPath, newState.Network, "dbstates")
@@ -599,50 +656,54 @@ func (s *State) GetEventService() events.EventService {
 }
 
 func (s *State) EmitDirectoryBlockEventsFromHeight(height uint32, end uint32) {
-	i := height
-	msgCount := 0
-	for i <= end {
-		d, err := s.DB.FetchDBlockByHeight(i)
-		if err != nil || d == nil {
+	for i := height; i <= end; i++ {
+		msg := s.loadDBStateMsg(i)
+		if msg == nil {
 			break
 		}
+		s.EventService.EmitReplayDirectoryBlockCommit(msg)
+	}
+}
 
-		a, err := s.DB.FetchABlockByHeight(i)
-		if err != nil || a == nil {
-			break
-		}
-		f, err := s.DB.FetchFBlockByHeight(i)
-		if err != nil || f == nil {
-			break
-		}
-		ec, err := s.DB.FetchECBlockByHeight(i)
-		if err != nil || ec == nil {
-			break
-		}
+// loadDBStateMsg returns the blocks, entry blocks and entries of the height from the database,
+// nil if a block is missing
+func (s *State) loadDBStateMsg(height uint32) interfaces.IMsg {
+	d, err := s.DB.FetchDBlockByHeight(height)
+	if err != nil || d == nil {
+		return nil
+	}
+
+	a, err := s.DB.FetchABlockByHeight(height)
+	if err != nil || a == nil {
+		return nil
+	}
+	f, err := s.DB.FetchFBlockByHeight(height)
+	if err != nil || f == nil {
+		return nil
+	}
+	ec, err := s.DB.FetchECBlockByHeight(height)
+	if err != nil || ec == nil {
+		return nil
+	}
 
-		var eblocks []interfaces.IEntryBlock
-		var entries []interfaces.IEBEntry
+	var eblocks []interfaces.IEntryBlock
+	var entries []interfaces.IEBEntry
 
-		ebs := d.GetEBlockDBEntries()
-		for _, eb := range ebs {
-			eblock, _ := s.DB.FetchEBlock(eb.GetKeyMR())
-			if eblock != nil {
-				eblocks = append(eblocks, eblock)
-				for _, e := range eblock.GetEntryHashes() {
-					ent, _ := s.DB.FetchEntry(e)
-					if ent != nil {
-						entries = append(entries, ent)
-					}
+	ebs := d.GetEBlockDBEntries()
+	for _, eb := range ebs {
+		eblock, _ := s.DB.FetchEBlock(eb.GetKeyMR())
+		if eblock != nil {
+			eblocks = append(eblocks, eblock)
+			for _, e := range eblock.GetEntryHashes() {
+				ent, _ := s.DB.FetchEntry(e)
+				if ent != nil {
+					entries = append(entries, ent)
 				}
 			}
 		}
-
-		msg := messages.NewDBStateMsg(d.GetTimestamp(), d, a, f, ec, eblocks, entries, nil)
-		i++
-		msgCount++
-
-		s.EventService.EmitReplayDirectoryBlockCommit(msg)
 	}
+
+	return messages.NewDBStateMsg(d.GetTimestamp(), d, a, f, ec, eblocks, entries, nil)
 }
 
 func (s *State) AddPrefix(prefix string) {
@@ -717,6 +778,33 @@ func (s *State) GetRpcUser() string {
 func (s *State) GetCorsDomains() []string {
 	return s.CorsDomains
 }
+
+// GetApiBatchSettings returns the maximum number of requests in a v2 batch call, and the
+// number of workers used to execute them
+func (s *State) GetApiBatchSettings() (int, int) {
+	return s.ApiBatchLimit, s.ApiBatchWorkers
+}
+
+// GetApiRateLimits returns the read rate, read burst, write rate and write burst of the per
+// client API rate limits
+func (s *State) GetApiRateLimits() (int, int, int, int) {
+	return s.ApiReadRateLimit, s.ApiReadRateBurst, s.ApiWriteRateLimit, s.ApiWriteRateBurst
+}
+
+// GetApiRateLimitKey returns how API clients are identified for rate limiting
+func (s *State) GetApiRateLimitKey() string {
+	return s.ApiRateLimitKey
+}
+
+// IsApiReadOnly returns true if the API rejects write and debug calls
+func (s *State) IsApiReadOnly() bool {
+	return s.ApiReadOnly
+}
+
+// GetDebugApiPort returns the port of the debug API, 0 if it is served on the API port
+func (s *State) GetDebugApiPort() int {
+	return s.DebugApiPort
+}
 func (s *State) GetRpcPass() string {
 	return s.RpcPass
 }
@@ -823,6 +911,7 @@ func (s *State) LoadConfig(filename string, networkFlag string) {
 		// TODO: improve the paths after milestone 1
 		cfg.App.LdbPath = cfg.App.HomeDir + networkName + cfg.App.LdbPath
 		cfg.App.BoltDBPath = cfg.App.HomeDir + networkName + cfg.App.BoltDBPath
+		cfg.App.BadgerDBPath = cfg.App.HomeDir + networkName + cfg.App.BadgerDBPath
 		cfg.App.DataStorePath = cfg.App.HomeDir + networkName + cfg.App.DataStorePath
 		cfg.Log.LogPath = cfg.App.HomeDir + networkName + cfg.Log.LogPath
 		cfg.App.ExportDataSubpath = cfg.App.HomeDir + networkName + cfg.App.ExportDataSubpath
@@ -832,11 +921,14 @@ func (s *State) LoadConfig(filename string, networkFlag string) {
 		s.LogPath = cfg.Log.LogPath + s.Prefix
 		s.LdbPath = cfg.App.LdbPath + s.Prefix
 		s.BoltDBPath = cfg.App.BoltDBPath + s.Prefix
+		s.BadgerDBPath = cfg.App.BadgerDBPath + s.Prefix
 		s.LogLevel = cfg.Log.LogLevel
 		s.ConsoleLogLevel = cfg.Log.ConsoleLogLevel
 		s.NodeMode = cfg.App.NodeMode
 		s.DBType = cfg.App.DBType
 		s.ExportData = cfg.App.ExportData // bool
+		s.DBEncryption = cfg.App.DBEncryption
+		s.DBEncryptionKeyFile = cfg.App.DBEncryptionKeyFile
 		s.ExportDataSubpath = cfg.App.ExportDataSubpath
 		s.MainNetworkPort = cfg.App.MainNetworkPort
 		s.PeersFile = cfg.App.PeersFile
@@ -883,6 +975,18 @@ func (s *State) LoadConfig(filename string, networkFlag string) {
 				s.CorsDomains = append(s.CorsDomains, strings.Trim(domain, " "))
 			}
 		}
+		s.ApiBatchLimit = cfg.App.ApiBatchLimit
+		s.ApiBatchWorkers = cfg.App.ApiBatchWorkers
+		s.AddressIndex = cfg.App.EnableAddressIndex
+		s.ExtIDIndex = cfg.App.EnableExtIDIndex
+		s.PruneEntriesAfter = cfg.App.PruneEntriesAfter
+		s.ApiReadRateLimit = cfg.App.ApiReadRateLimit
+		s.ApiReadRateBurst = cfg.App.ApiReadRateBurst
+		s.ApiWriteRateLimit = cfg.App.ApiWriteRateLimit
+		s.ApiWriteRateBurst = cfg.App.ApiWriteRateBurst
+		s.ApiRateLimitKey = cfg.App.ApiRateLimitKey
+		s.ApiReadOnly = cfg.App.ApiReadOnly
+		s.DebugApiPort = cfg.App.DebugApiPort
 		s.FactomdTLSEnable = cfg.App.FactomdTlsEnabled
 
 		FactomdTLSKeyFile := cfg.App.FactomdTlsPrivateKey
@@ -944,6 +1048,7 @@ func (s *State) LoadConfig(filename string, networkFlag string) {
 		s.LogPath = "database/"
 		s.LdbPath = "database/ldb"
 		s.BoltDBPath = "database/bolt"
+		s.BadgerDBPath = "database/badger"
 		s.LogLevel = "none"
 		s.ConsoleLogLevel = "standard"
 		s.NodeMode = "SERVER"
@@ -970,6 +1075,9 @@ func (s *State) LoadConfig(filename string, networkFlag string) {
 		s.PortNumber = 8088
 		s.ControlPanelPort = 8090
 		s.ControlPanelSetting = 1
+		s.ApiBatchLimit = 100
+		s.ApiBatchWorkers = 4
+		s.ApiRateLimitKey = "ip"
 
 		// TODO:  Actually load the IdentityChainID from the config file
 		s.IdentityChainID = primitives.Sha([]byte(s.FactomNodeName))
@@ -1126,6 +1234,10 @@ func (s *State) Init() {
 		if err := s.InitBoltDB(); err != nil {
 			panic(fmt.Sprintf("Error initializing the database: %v", err))
 		}
+	case "Badger":
+		if err := s.InitBadgerDB(); err != nil {
+			panic(fmt.Sprintf("Error initializing the database: %v", err))
+		}
 	case "Map":
 		if err := s.InitMapDB(); err != nil {
 			panic(fmt.Sprintf("Error initializing the database: %v", err))
@@ -1134,6 +1246,10 @@ func (s *State) Init() {
 		panic("No Database type specified")
 	}
 
+	if err := s.MigrateDatabase(); err != nil {
+		panic(fmt.Sprintf("Error migrating the database: %v", err))
+	}
+
 	if s.CheckChainHeads.CheckChainHeads {
 		if s.CheckChainHeads.Fix {
 			// Set dblock head to 184 if 184 is present and head is not 184
@@ -1489,25 +1605,9 @@ func (s *State) LoadDBState(dbheight uint32) (interfaces.IMsg, error) {
 			}
 		}
 	} else {
-		abEntries := nextABlock.GetABEntries()
-		for _, adminEntry := range abEntries {
-			data, err := adminEntry.MarshalBinary()
-			if err != nil {
-				return nil, err
-			}
-			switch adminEntry.Type() {
-			case constants.TYPE_DB_SIGNATURE:
-				r := new(adminBlock.DBSignatureEntry)
-				err := r.UnmarshalBinary(data)
-				if err != nil {
-					continue
-				}
-
-				blockSig := new(primitives.Signature)
-				blockSig.SetSignature(r.PrevDBSig.Bytes())
-				blockSig.SetPub(r.PrevDBSig.GetKey())
-				allSigs = append(allSigs, blockSig)
-			}
+		allSigs, err = dbSignatures(nextABlock)
+		if err != nil {
+			return nil, err
 		}
 	}
 	msg := messages.NewDBStateMsg(s.GetTimestamp(), dblk, ablk, fblk, ecblk, eBlocks, entries, allSigs)
@@ -1516,6 +1616,31 @@ func (s *State) LoadDBState(dbheight uint32) (interfaces.IMsg, error) {
 	return msg, nil
 }
 
+// dbSignatures returns the signatures of the previous directory block held by the admin block
+func dbSignatures(ablk interfaces.IAdminBlock) ([]interfaces.IFullSignature, error) {
+	var allSigs []interfaces.IFullSignature
+	for _, adminEntry := range ablk.GetABEntries() {
+		data, err := adminEntry.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		switch adminEntry.Type() {
+		case constants.TYPE_DB_SIGNATURE:
+			r := new(adminBlock.DBSignatureEntry)
+			err := r.UnmarshalBinary(data)
+			if err != nil {
+				continue
+			}
+
+			blockSig := new(primitives.Signature)
+			blockSig.SetSignature(r.PrevDBSig.Bytes())
+			blockSig.SetPub(r.PrevDBSig.GetKey())
+			allSigs = append(allSigs, blockSig)
+		}
+	}
+	return allSigs, nil
+}
+
 func (s *State) LoadDataByHash(requestedHash interfaces.IHash) (interfaces.BinaryMarshallable, int, error) {
 	if requestedHash == nil {
 		return nil, -1, fmt.Errorf("%s", "Requested hash must be non-empty")
@@ -1529,6 +1654,10 @@ func (s *State) LoadDataByHash(requestedHash interfaces.IHash) (interfaces.Binar
 	if result != nil && err == nil {
 		return result, 0, nil
 	}
+	// Pruned entries are not served to other nodes
+	if pruned, _ := s.DB.IsEntryPruned(requestedHash); pruned {
+		return nil, -1, fmt.Errorf("Entry %x was pruned", requestedHash.Bytes()[:5])
+	}
 
 	// Check for Entry Block
 	result, err = s.DB.FetchEBlock(requestedHash)
@@ -2609,7 +2738,12 @@ func (s *State) InitLevelDB() error {
 		}
 	}
 
-	s.DB = databaseOverlay.NewOverlayWithState(dbase, s)
+	db, err := s.encryptDatabase(dbase)
+	if err != nil {
+		dbase.Close()
+		return err
+	}
+	s.DB = databaseOverlay.NewOverlayWithState(db, s)
 	return nil
 }
 
@@ -2625,7 +2759,36 @@ func (s *State) InitBoltDB() error {
 
 	dbase := new(boltdb.BoltDB)
 	dbase.Init(nil, path+"FactomBolt.db")
-	s.DB = databaseOverlay.NewOverlayWithState(dbase, s)
+	db, err := s.encryptDatabase(dbase)
+	if err != nil {
+		dbase.Close()
+		return err
+	}
+	s.DB = databaseOverlay.NewOverlayWithState(db, s)
+	return nil
+}
+
+func (s *State) InitBadgerDB() error {
+	if s.DB != nil {
+		return nil
+	}
+
+	path := s.BadgerDBPath + "/" + s.Network + "/" + "factoid_badger.db"
+
+	s.Println("Database:", path)
+	fmt.Fprintln(os.Stderr, "Database:", path)
+
+	dbase, err := badgerdb.NewBadgerDB(path, true)
+	if err != nil {
+		return err
+	}
+
+	db, err := s.encryptDatabase(dbase)
+	if err != nil {
+		dbase.Close()
+		return err
+	}
+	s.DB = databaseOverlay.NewOverlayWithState(db, s)
 	return nil
 }
 
@@ -21,6 +21,8 @@ type StateSaverStruct struct {
 
 	TmpDBHt  uint32
 	TmpState []byte
+	// FileDBHt is the height of the states in the fastboot file
+	FileDBHt uint32
 	Mutex    sync.Mutex
 	Stop     bool
 }
@@ -54,6 +56,7 @@ func (sss *StateSaverStruct) SaveDBStateList(s *State, ss *DBStateList, networkN
 			fmt.Fprintln(os.Stderr, "SaveState SaveToFile Failed", err)
 			return err
 		}
+		sss.FileDBHt = sss.TmpDBHt
 	}
 
 	if sss.TmpDBHt != ss.State.LLeaderHeight {
@@ -109,10 +112,39 @@ func (sss *StateSaverStruct) LoadDBStateList(s *State, statelist *DBStateList, n
 		}
 	}
 	statelist.DBStates[i].SaveStruct.RestoreFactomdState(statelist.State)
+	sss.FileDBHt = statelist.DBStates[i].DirectoryBlock.GetHeader().GetDBHeight()
 
 	return nil
 }
 
+// ReadSaveState returns the most recent SaveState of a fastboot file, without restoring it
+func ReadSaveState(filename string) (*SaveState, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	h := primitives.NewZeroHash()
+	b, err = h.UnmarshalBinaryData(b)
+	if err != nil {
+		return nil, err
+	}
+	if h.IsSameAs(primitives.Sha(b)) == false {
+		return nil, errors.New("fastboot file does not match its hash")
+	}
+
+	statelist := new(DBStateList)
+	statelist.State = new(State)
+	if err := statelist.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	for i := len(statelist.DBStates) - 1; i >= 0; i-- {
+		if statelist.DBStates[i].SaveStruct != nil {
+			return statelist.DBStates[i].SaveStruct, nil
+		}
+	}
+	return nil, errors.New("fastboot file holds no SaveState")
+}
+
 func NetworkIDToFilename(networkName string, fileLocation string) string {
 	file := fmt.Sprintf("FastBoot_%s_v%v.db", networkName, constants.SaveStateVersion)
 	if fileLocation != "" {
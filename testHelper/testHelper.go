@@ -220,59 +220,73 @@ func CreateTestBlockCommitList() []interfaces.IMsg {
 
 func PopulateTestDatabaseOverlay(dbo *databaseOverlay.Overlay) {
 	var prev *BlockSet = nil
-	var err error
 
 	for i := 0; i < BlockCount; i++ {
-		dbo.StartMultiBatch()
 		prev = CreateTestBlockSet(prev)
-
-		err = dbo.ProcessABlockMultiBatch(prev.ABlock)
-		if err != nil {
+		if err := saveBlockSet(dbo, prev); err != nil {
 			panic(err)
 		}
-
-		err = dbo.ProcessEBlockMultiBatch(prev.EBlock, true)
+	}
+	/*
+		err = dbo.RebuildDirBlockInfo()
 		if err != nil {
 			panic(err)
 		}
+	*/
+}
 
-		err = dbo.ProcessEBlockMultiBatch(prev.AnchorEBlock, true)
-		if err != nil {
-			panic(err)
+// SaveBlockSets writes the block sets to the database the way the node does, each in its own
+// multi batch
+func SaveBlockSets(dbo *databaseOverlay.Overlay, blocks []*BlockSet) error {
+	for _, set := range blocks {
+		if err := saveBlockSet(dbo, set); err != nil {
+			return err
 		}
+	}
+	return nil
+}
 
-		err = dbo.ProcessECBlockMultiBatch(prev.ECBlock, false)
-		if err != nil {
-			panic(err)
-		}
+func saveBlockSet(dbo *databaseOverlay.Overlay, set *BlockSet) error {
+	dbo.StartMultiBatch()
 
-		err = dbo.ProcessFBlockMultiBatch(prev.FBlock)
-		if err != nil {
-			panic(err)
-		}
+	err := dbo.ProcessABlockMultiBatch(set.ABlock)
+	if err != nil {
+		return err
+	}
 
-		err = dbo.ProcessDBlockMultiBatch(prev.DBlock)
-		if err != nil {
-			panic(err)
-		}
+	err = dbo.ProcessEBlockMultiBatch(set.EBlock, true)
+	if err != nil {
+		return err
+	}
 
-		for _, entry := range prev.Entries {
-			err = dbo.InsertEntryMultiBatch(entry)
-			if err != nil {
-				panic(err)
-			}
-		}
+	err = dbo.ProcessEBlockMultiBatch(set.AnchorEBlock, true)
+	if err != nil {
+		return err
+	}
 
-		if err := dbo.ExecuteMultiBatch(); err != nil {
-			panic(err)
-		}
+	err = dbo.ProcessECBlockMultiBatch(set.ECBlock, false)
+	if err != nil {
+		return err
 	}
-	/*
-		err = dbo.RebuildDirBlockInfo()
+
+	err = dbo.ProcessFBlockMultiBatch(set.FBlock)
+	if err != nil {
+		return err
+	}
+
+	err = dbo.ProcessDBlockMultiBatch(set.DBlock)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range set.Entries {
+		err = dbo.InsertEntryMultiBatch(entry)
 		if err != nil {
-			panic(err)
+			return err
 		}
-	*/
+	}
+
+	return dbo.ExecuteMultiBatch()
 }
 
 func CreateAndPopulateTestDatabaseOverlay() *databaseOverlay.Overlay {
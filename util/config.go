@@ -25,6 +25,9 @@ type FactomdConfig struct {
 		DBType                                 string
 		LdbPath                                string
 		BoltDBPath                             string
+		BadgerDBPath                           string
+		DBEncryption                           string
+		DBEncryptionKeyFile                    string
 		DataStorePath                          string
 		DirectoryBlockInSeconds                int
 		ExportData                             bool
@@ -74,6 +77,30 @@ type FactomdConfig struct {
 
 		CorsDomains string
 
+		// Maximum number of requests in a batch call and the number of workers executing them
+		ApiBatchLimit   int
+		ApiBatchWorkers int
+
+		// Maintain the optional index of the transactions touching each address
+		EnableAddressIndex bool
+
+		// Maintain the optional index of the entries of each chain by their ExtIDs
+		EnableExtIDIndex bool
+
+		// Drop the content of entries this many directory blocks old, 0 keeps everything
+		PruneEntriesAfter int
+
+		// Per client token bucket rate limits of the API, in requests per second
+		ApiReadRateLimit  int
+		ApiReadRateBurst  int
+		ApiWriteRateLimit int
+		ApiWriteRateBurst int
+		ApiRateLimitKey   string
+
+		// Reject write and debug calls, and serve the debug API on its own port
+		ApiReadOnly  bool
+		DebugApiPort int
+
 		ChangeAcksHeight uint32
 	}
 	Peer struct {
@@ -118,9 +145,34 @@ type FactomdConfig struct {
 		EventFormat              string
 		EventReplayDuringStartup bool
 		EventSendStateChange     bool
+		EventSendBalanceChanges  bool
 		EventBroadcastContent    string
 		PersistentReconnect      bool
+		EventTypes               string
+		EventChainIDs            string
+		EventSpoolPath           string
+		EventSpoolSize           int
 	}
+	LiveFeedReceiver map[string]*LiveFeedReceiverConfig
+}
+
+// LiveFeedReceiverConfig is a [LiveFeedReceiver "name"] section, an additional receiver of the
+// live feed with its own queue. The keys are the ones of [LiveFeedAPI]
+type LiveFeedReceiverConfig struct {
+	EventReceiverProtocol    string
+	EventReceiverHost        string
+	EventReceiverPort        int
+	EventSenderPort          int
+	EventFormat              string
+	EventReplayDuringStartup bool
+	EventSendStateChange     bool
+	EventSendBalanceChanges  bool
+	EventBroadcastContent    string
+	PersistentReconnect      bool
+	EventTypes               string
+	EventChainIDs            string
+	EventSpoolPath           string
+	EventSpoolSize           int
 }
 
 // defaultConfig
@@ -134,10 +186,16 @@ HomeDir                               = ""
 ; --------------- ControlPanel disabled | readonly | readwrite
 ControlPanelSetting                   = readonly
 ControlPanelPort                      = 8090
-; --------------- DBType: LDB | Bolt | Map
+; --------------- DBType: LDB | Bolt | Badger | Map
 DBType                                = "LDB"
 LdbPath                               = "database/ldb"
 BoltDBPath                            = "database/bolt"
+BadgerDBPath                          = "database/badger"
+; --------------- DBEncryption: none | all | comma separated bucket names, like KeyValueStore
+; --------------- The node refuses to start if they are not the buckets the database was encrypted with
+; --------------- The passphrase is read from DBEncryptionKeyFile, or from $FACTOMD_DB_PASSPHRASE if no file is set
+DBEncryption                          = "none"
+DBEncryptionKeyFile                   = ""
 DataStorePath                         = "data/export"
 DirectoryBlockInSeconds               = 6
 ExportData                            = false
@@ -200,6 +258,47 @@ RequestLimit						= 200
 ; Example paramaters are "http://www.example.com, http://anotherexample.com, *"
 CorsDomains                           = ""
 
+; ApiBatchLimit is the maximum number of requests accepted in a single JSON-RPC batch call to the v2 API.
+; ApiBatchWorkers is the number of requests of a batch that are executed concurrently.
+ApiBatchLimit                         = 100
+ApiBatchWorkers                       = 4
+
+; EnableAddressIndex maintains an index of the factoid and entry credit transactions touching each address,
+; used by the "address-transactions" API call. Existing databases are indexed in the background.
+; The index is stored in the database, and grows with the number of transactions.
+EnableAddressIndex                    = false
+
+; EnableExtIDIndex maintains an index of the entries of each chain by their external IDs, used by the
+; "entries-by-extid" API call. Existing databases are indexed in the background. Entries that were
+; pruned before they were indexed can not be found by their external IDs.
+EnableExtIDIndex                      = false
+
+; PruneEntriesAfter drops the content of entries once they are this many directory blocks old, 0 keeps
+; every entry. All blocks and the hashes of the entries are kept, the "entry" and "raw-data" API calls
+; return an "Entry pruned" error for the dropped entries and they are not served to other nodes.
+; "chain-entries" lists them with the pruned flag set and without their content.
+; Entries of the identity, FER and anchor chains are kept.
+PruneEntriesAfter                     = 0
+
+; ApiReadRateLimit and ApiWriteRateLimit limit the number of API calls per second of a single client,
+; 0 disables the limit. Write calls are commit-chain, commit-entry, reveal-chain, reveal-entry,
+; factoid-submit and send-raw-message, all other calls are reads. The bursts are the number of calls a
+; client can make at once after being idle, 0 uses the rate. ApiRateLimitKey identifies a client by
+; its ip, its rpc user, or both: ip | user | ip+user
+ApiReadRateLimit                      = 0
+ApiReadRateBurst                      = 0
+ApiWriteRateLimit                     = 0
+ApiWriteRateBurst                     = 0
+ApiRateLimitKey                       = "ip"
+
+; ApiReadOnly rejects all calls that submit data to the network (commit-chain, commit-entry, reveal-chain,
+; reveal-entry, factoid-submit, send-raw-message), replay-from-height and all debug API calls. Meant for
+; public facing nodes.
+; DebugApiPort serves the debug API on its own port instead of the API port, 0 keeps it on the API port.
+; The debug API is only available on networks other than MAIN.
+ApiReadOnly                           = false
+DebugApiPort                          = 0
+
 ; Specifying when to change ACKs for switching leader servers
 ChangeAcksHeight                      = 0
 
@@ -244,7 +343,9 @@ WalletEncrypted                       = false
 ; Configuration options for the live feed API
 ; ------------------------------------------------------------------------------
 [LiveFeedAPI]
+; --------------- EnableLiveFeedAPI: send to the receiver of this section, the [LiveFeedReceiver] sections are sent to either way
 EnableLiveFeedAPI                     = false
+; --------------- EventReceiverProtocol: tcp or udp to send to the receiver, http to serve the events at /livefeed/events and /livefeed/ws of the API
 EventReceiverProtocol                 = tcp
 EventReceiverHost                     = 127.0.0.1
 EventReceiverPort                     = 8040
@@ -252,8 +353,26 @@ EventSenderPort                       = 8040
 EventFormat                           = protobuf
 EventReplayDuringStartup              = false
 EventSendStateChange                  = false
+; --------------- EventSendBalanceChanges: also send the change of the balance of each address of the factoid transactions in a block
+EventSendBalanceChanges               = false
 EventBroadcastContent                 = once
 PersistentReconnect                   = false
+; --------------- EventTypes: comma separated events to send, like directoryBlockCommit,entryReveal, all if empty
+; --------------- EventChainIDs: comma separated chains whose entries are sent, all if empty
+EventTypes                            = ""
+EventChainIDs                         = ""
+; --------------- EventSpoolPath: directory keeping the last EventSpoolSize events, that a tcp or http receiver resumes from
+; --------------- A spooled tcp receiver opens the connection with the sequence number of the last event it got
+EventSpoolPath                        = ""
+EventSpoolSize                        = 100000
+
+; Each additional receiver of the live feed has its own section and queue, with the keys of [LiveFeedAPI] but EnableLiveFeedAPI
+; [LiveFeedReceiver "indexer"]
+; EventReceiverHost                   = 127.0.0.1
+; EventReceiverPort                   = 8041
+; EventFormat                         = json
+; EventBroadcastContent               = always
+; EventChainIDs                       = 888888001750ede0eff4b05f0c3f557890b256450cabbb84cada937f9c258327
 `
 
 func (s *FactomdConfig) String() string {
@@ -269,6 +388,9 @@ func (s *FactomdConfig) String() string {
 	out.WriteString(fmt.Sprintf("\n    DBType                  %v", s.App.DBType))
 	out.WriteString(fmt.Sprintf("\n    LdbPath                 %v", s.App.LdbPath))
 	out.WriteString(fmt.Sprintf("\n    BoltDBPath              %v", s.App.BoltDBPath))
+	out.WriteString(fmt.Sprintf("\n    BadgerDBPath            %v", s.App.BadgerDBPath))
+	out.WriteString(fmt.Sprintf("\n    DBEncryption            %v", s.App.DBEncryption))
+	out.WriteString(fmt.Sprintf("\n    DBEncryptionKeyFile     %v", s.App.DBEncryptionKeyFile))
 	out.WriteString(fmt.Sprintf("\n    DataStorePath           %v", s.App.DataStorePath))
 	out.WriteString(fmt.Sprintf("\n    DirectoryBlockInSeconds %v", s.App.DirectoryBlockInSeconds))
 	out.WriteString(fmt.Sprintf("\n    ExportData              %v", s.App.ExportData))
@@ -303,6 +425,18 @@ func (s *FactomdConfig) String() string {
 	out.WriteString(fmt.Sprintf("\n    FactomdTlsPublicCert     %v", s.App.FactomdTlsPublicCert))
 	out.WriteString(fmt.Sprintf("\n    FactomdRpcUser          	%v", s.App.FactomdRpcUser))
 	out.WriteString(fmt.Sprintf("\n    FactomdRpcPass          	%v", s.App.FactomdRpcPass))
+	out.WriteString(fmt.Sprintf("\n    ApiBatchLimit            %v", s.App.ApiBatchLimit))
+	out.WriteString(fmt.Sprintf("\n    ApiBatchWorkers          %v", s.App.ApiBatchWorkers))
+	out.WriteString(fmt.Sprintf("\n    EnableAddressIndex       %v", s.App.EnableAddressIndex))
+	out.WriteString(fmt.Sprintf("\n    EnableExtIDIndex         %v", s.App.EnableExtIDIndex))
+	out.WriteString(fmt.Sprintf("\n    PruneEntriesAfter        %v", s.App.PruneEntriesAfter))
+	out.WriteString(fmt.Sprintf("\n    ApiReadRateLimit         %v", s.App.ApiReadRateLimit))
+	out.WriteString(fmt.Sprintf("\n    ApiReadRateBurst         %v", s.App.ApiReadRateBurst))
+	out.WriteString(fmt.Sprintf("\n    ApiWriteRateLimit        %v", s.App.ApiWriteRateLimit))
+	out.WriteString(fmt.Sprintf("\n    ApiWriteRateBurst        %v", s.App.ApiWriteRateBurst))
+	out.WriteString(fmt.Sprintf("\n    ApiRateLimitKey          %v", s.App.ApiRateLimitKey))
+	out.WriteString(fmt.Sprintf("\n    ApiReadOnly              %v", s.App.ApiReadOnly))
+	out.WriteString(fmt.Sprintf("\n    DebugApiPort             %v", s.App.DebugApiPort))
 	out.WriteString(fmt.Sprintf("\n    ChangeAcksHeight         %v", s.App.ChangeAcksHeight))
 	out.WriteString(fmt.Sprintf("\n    BitcoinAnchorRecordPublicKeys    %v", s.App.BitcoinAnchorRecordPublicKeys))
 	out.WriteString(fmt.Sprintf("\n    EthereumAnchorRecordPublicKeys    %v", s.App.EthereumAnchorRecordPublicKeys))
@@ -331,8 +465,31 @@ func (s *FactomdConfig) String() string {
 	out.WriteString(fmt.Sprintf("\n    EventFormat              %v", s.LiveFeedAPI.EventFormat))
 	out.WriteString(fmt.Sprintf("\n    EventBroadcastContent    %v", s.LiveFeedAPI.EventBroadcastContent))
 	out.WriteString(fmt.Sprintf("\n    EventSendStateChange     %v", s.LiveFeedAPI.EventSendStateChange))
+	out.WriteString(fmt.Sprintf("\n    EventSendBalanceChanges  %v", s.LiveFeedAPI.EventSendBalanceChanges))
 	out.WriteString(fmt.Sprintf("\n    EventReplayDuringStartup %v", s.LiveFeedAPI.EventReplayDuringStartup))
 	out.WriteString(fmt.Sprintf("\n    PersistentReconnect      %v", s.LiveFeedAPI.PersistentReconnect))
+	out.WriteString(fmt.Sprintf("\n    EventTypes               %v", s.LiveFeedAPI.EventTypes))
+	out.WriteString(fmt.Sprintf("\n    EventChainIDs            %v", s.LiveFeedAPI.EventChainIDs))
+	out.WriteString(fmt.Sprintf("\n    EventSpoolPath           %v", s.LiveFeedAPI.EventSpoolPath))
+	out.WriteString(fmt.Sprintf("\n    EventSpoolSize           %v", s.LiveFeedAPI.EventSpoolSize))
+
+	for name, receiver := range s.LiveFeedReceiver {
+		out.WriteString(fmt.Sprintf("\n  LiveFeedReceiver %q", name))
+		out.WriteString(fmt.Sprintf("\n    EventReceiverProtocol    %v", receiver.EventReceiverProtocol))
+		out.WriteString(fmt.Sprintf("\n    EventReceiverHost        %v", receiver.EventReceiverHost))
+		out.WriteString(fmt.Sprintf("\n    EventReceiverPort        %v", receiver.EventReceiverPort))
+		out.WriteString(fmt.Sprintf("\n    EventSenderPort          %v", receiver.EventSenderPort))
+		out.WriteString(fmt.Sprintf("\n    EventFormat              %v", receiver.EventFormat))
+		out.WriteString(fmt.Sprintf("\n    EventBroadcastContent    %v", receiver.EventBroadcastContent))
+		out.WriteString(fmt.Sprintf("\n    EventSendStateChange     %v", receiver.EventSendStateChange))
+		out.WriteString(fmt.Sprintf("\n    EventSendBalanceChanges  %v", receiver.EventSendBalanceChanges))
+		out.WriteString(fmt.Sprintf("\n    EventReplayDuringStartup %v", receiver.EventReplayDuringStartup))
+		out.WriteString(fmt.Sprintf("\n    PersistentReconnect      %v", receiver.PersistentReconnect))
+		out.WriteString(fmt.Sprintf("\n    EventTypes               %v", receiver.EventTypes))
+		out.WriteString(fmt.Sprintf("\n    EventChainIDs            %v", receiver.EventChainIDs))
+		out.WriteString(fmt.Sprintf("\n    EventSpoolPath           %v", receiver.EventSpoolPath))
+		out.WriteString(fmt.Sprintf("\n    EventSpoolSize           %v", receiver.EventSpoolSize))
+	}
 
 	return out.String()
 }
@@ -8,8 +8,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"math"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/FactomProject/factomd/common/globals"
@@ -18,6 +20,7 @@ import (
 
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/database/snapshot"
 )
 
 type success struct {
@@ -67,6 +70,10 @@ func HandleDebugRequest(state interfaces.IState, j *primitives.JSON2Request) (*p
 	params := j.Params
 	wsDebugLog.Printf("request %v", j.String())
 
+	if state.IsApiReadOnly() {
+		return nil, NewReadOnlyError(j.Method)
+	}
+
 	switch j.Method {
 	case "audit-servers":
 		resp, jsonError = HandleAuditServers(state, params)
@@ -137,6 +144,15 @@ func HandleDebugRequest(state interfaces.IState, j *primitives.JSON2Request) (*p
 	case "message-filter":
 		resp, jsonError = HandleMessageFilter(state, params)
 		break
+	case "snapshot-database":
+		resp, jsonError = HandleSnapshotDatabase(state, params)
+		break
+	case "compact-database":
+		resp, jsonError = HandleCompactDatabase(state, params)
+		break
+	case "replay-livefeed":
+		resp, jsonError = HandleReplayLiveFeed(state, params)
+		break
 	default:
 		jsonError = NewMethodNotFoundError()
 		break
@@ -165,10 +181,7 @@ func HandleAuditServers(state interfaces.IState, params interface{}) (interface{
 }
 
 func HandleAuthorities(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
-	type ret struct {
-		Authorities []interfaces.IAuthority `json:"authorities"`
-	}
-	r := new(ret)
+	r := new(AuthoritiesResponse)
 
 	r.Authorities = state.GetAuthorities()
 	return r, nil
@@ -368,6 +381,104 @@ func HandleSimControl(state interfaces.IState, params interface{}) (interface{},
 	return r, nil
 }
 
+// HandleSnapshotDatabase writes a snapshot of the database into a directory on the node, and
+// optionally packs it into a tar file there as well. Both have to be below the home directory of
+// the node, relative paths are taken from it
+func HandleSnapshotDatabase(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	req := new(SnapshotDatabaseRequest)
+	err := MapToObject(params, req)
+	if err != nil || req.Path == "" {
+		return nil, NewInvalidParamsError()
+	}
+	dir, err := state.SnapshotPath(req.Path)
+	if err != nil {
+		return nil, NewCustomInvalidParamsError(err.Error())
+	}
+	tar := ""
+	if req.Tar != "" {
+		tar, err = state.SnapshotPath(req.Tar)
+		if err != nil {
+			return nil, NewCustomInvalidParamsError(err.Error())
+		}
+	}
+
+	m, err := state.SnapshotDatabase(dir)
+	if err != nil {
+		return nil, NewCustomInternalError(err.Error())
+	}
+
+	if tar != "" {
+		f, err := os.OpenFile(tar, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0644)
+		if err != nil {
+			return nil, NewCustomInternalError(err.Error())
+		}
+		defer f.Close()
+		err = snapshot.WriteTar(dir, f)
+		if err != nil {
+			return nil, NewCustomInternalError(err.Error())
+		}
+	}
+	return m, nil
+}
+
+type SnapshotDatabaseRequest struct {
+	Path string `json:"path"`
+	Tar  string `json:"tar,omitempty"`
+}
+
+// HandleCompactDatabase compacts the database of the node. The derived buckets are not rebuilt,
+// lookups in them would miss until they are done, Utilities/DatabaseCompact rebuilds them with
+// the node stopped
+func HandleCompactDatabase(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	req := new(CompactDatabaseRequest)
+	if params != nil {
+		err := MapToObject(params, req)
+		if err != nil {
+			return nil, NewInvalidParamsError()
+		}
+	}
+	if len(req.Rebuild) > 0 {
+		return nil, NewCustomInvalidParamsError(fmt.Sprintf("%v can only be rebuilt with the node stopped, by Utilities/DatabaseCompact", strings.Join(req.Rebuild, ", ")))
+	}
+
+	r, err := state.CompactDatabase()
+	if err != nil {
+		return nil, NewCustomInternalError(err.Error())
+	}
+	return r, nil
+}
+
+type CompactDatabaseRequest struct {
+	Rebuild []string `json:"rebuild,omitempty"`
+}
+
+// HandleReplayLiveFeed starts sending the directory blocks of the height range in the request out
+// of the database to a live feed receiver, through the highest saved block if there is no end
+func HandleReplayLiveFeed(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	req := new(ReplayLiveFeedRequest)
+	err := MapToObject(params, req)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+	end := uint32(math.MaxUint32)
+	if req.End != nil {
+		end = *req.End
+	}
+
+	err = state.StartLiveFeedReplay(req.Receiver, req.Start, end, req.Rate)
+	if err != nil {
+		return nil, NewCustomInvalidParamsError(err.Error())
+	}
+	return &success{Status: "replay started"}, nil
+}
+
+type ReplayLiveFeedRequest struct {
+	Receiver string  `json:"receiver"`
+	Start    uint32  `json:"start"`
+	End      *uint32 `json:"end,omitempty"`
+	Rate     int     `json:"rate,omitempty"`
+}
+
 type SetDelayRequest struct {
 	Delay int64 `json:"delay"`
 }
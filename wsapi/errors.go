@@ -5,6 +5,8 @@
 package wsapi
 
 import (
+	"fmt"
+
 	"github.com/FactomProject/factomd/common/primitives"
 )
 
@@ -103,3 +105,27 @@ func NewReceiptError() *primitives.JSONError {
 func NewRepeatCommitError(data interface{}) *primitives.JSONError {
 	return primitives.NewJSONError(-32011, "Repeated Commit", data)
 }
+func NewAddressIndexDisabledError() *primitives.JSONError {
+	return primitives.NewJSONError(-32012, "Address index disabled", "Set EnableAddressIndex in factomd.conf to use this call")
+}
+func NewRateLimitExceededError() *primitives.JSONError {
+	return primitives.NewJSONError(-32013, "Rate limit exceeded", nil)
+}
+func NewReadOnlyError(method string) *primitives.JSONError {
+	return primitives.NewJSONError(-32014, "Read-only API", fmt.Sprintf("%s is disabled, this node only serves read calls", method))
+}
+func NewExtIDIndexDisabledError() *primitives.JSONError {
+	return primitives.NewJSONError(-32015, "ExtID index disabled", "Set EnableExtIDIndex in factomd.conf to use this call")
+}
+func NewEntryPrunedError() *primitives.JSONError {
+	return primitives.NewJSONError(-32016, "Entry pruned", "This node dropped the content of the entry, query a node that keeps all entries")
+}
+func NewNoBalanceHistoryError(data interface{}) *primitives.JSONError {
+	return primitives.NewJSONError(-32017, "No balance history", data)
+}
+func NewTooManySubscriptionsError(limit int) *primitives.JSONError {
+	return primitives.NewJSONError(-32018, "Too many subscriptions", fmt.Sprintf("A connection can have at most %d subscriptions, unsubscribe first", limit))
+}
+func NewBatchTooLargeError(limit int) *primitives.JSONError {
+	return primitives.NewJSONError(-32600, "Invalid Request", fmt.Sprintf("Batch exceeds the maximum of %d requests", limit))
+}
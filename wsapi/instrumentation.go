@@ -1,6 +1,10 @@
 package wsapi
 
 import (
+	"strconv"
+	"time"
+
+	"github.com/FactomProject/factomd/common/primitives"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -189,8 +193,59 @@ var (
 		Name: "factomd_wsapi_v2_api_call_tpsrate_ns",
 		Help: "Time it takes to compelete a tpsrate",
 	})
+
+	// Per method metrics of the v2 API, covering http, batch and websocket requests
+	HandleV2APIMethodDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "factomd_wsapi_v2_method_duration_seconds",
+		Help:    "Time it takes to complete a v2 call, by method",
+		Buckets: prometheus.ExponentialBuckets(0.0005, 2, 16),
+	}, []string{"method"})
+
+	HandleV2APIMethodCalls = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "factomd_wsapi_v2_method_calls_total",
+		Help: "Number of v2 calls, by method",
+	}, []string{"method"})
+
+	HandleV2APIMethodErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "factomd_wsapi_v2_method_errors_total",
+		Help: "Number of v2 calls that returned an error, by method and error code",
+	}, []string{"method", "code"})
+
+	HandleV2APIInFlight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "factomd_wsapi_v2_in_flight",
+		Help: "Number of v2 calls currently being handled",
+	})
+
+	// Rate limiting
+	RateLimitAllowed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "factomd_wsapi_ratelimit_allowed_total",
+		Help: "Number of calls let through by the rate limiter, by read or write budget",
+	}, []string{"budget"})
+
+	RateLimitRejected = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "factomd_wsapi_ratelimit_rejected_total",
+		Help: "Number of calls rejected by the rate limiter, by read or write budget",
+	}, []string{"budget"})
+
+	RateLimitClients = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "factomd_wsapi_ratelimit_clients",
+		Help: "Number of client budgets tracked by the rate limiter",
+	})
 )
 
+// ObserveV2Call records the duration and outcome of a v2 call. Unknown methods are counted
+// under a single label so clients can not grow the number of series.
+func ObserveV2Call(method string, duration time.Duration, jsonError *primitives.JSONError) {
+	if jsonError != nil && jsonError.Code == NewMethodNotFoundError().Code {
+		method = "unknown"
+	}
+	HandleV2APIMethodDuration.WithLabelValues(method).Observe(duration.Seconds())
+	HandleV2APIMethodCalls.WithLabelValues(method).Inc()
+	if jsonError != nil {
+		HandleV2APIMethodErrors.WithLabelValues(method, strconv.Itoa(jsonError.Code)).Inc()
+	}
+}
+
 var registered = false
 
 // RegisterPrometheus registers the variables to be exposed. This can only be run once, hence the
@@ -203,6 +258,13 @@ func RegisterPrometheus() {
 
 	prometheus.MustRegister(GensisFblockCall)
 	prometheus.MustRegister(HandleV2APICallGeneral)
+	prometheus.MustRegister(HandleV2APIMethodDuration)
+	prometheus.MustRegister(HandleV2APIMethodCalls)
+	prometheus.MustRegister(HandleV2APIMethodErrors)
+	prometheus.MustRegister(HandleV2APIInFlight)
+	prometheus.MustRegister(RateLimitAllowed)
+	prometheus.MustRegister(RateLimitRejected)
+	prometheus.MustRegister(RateLimitClients)
 	prometheus.MustRegister(HandleV2APICallChainHead)
 	prometheus.MustRegister(HandleV2APICallCommitChain)
 	prometheus.MustRegister(HandleV2APICallCommitEntry)
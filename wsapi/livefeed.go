@@ -0,0 +1,142 @@
+package wsapi
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/FactomProject/factomd/events/eventconfig"
+	"github.com/FactomProject/factomd/events/eventmessages/generated/eventmessages"
+	"github.com/FactomProject/factomd/events/eventservices"
+	"golang.org/x/net/websocket"
+)
+
+// AddLiveFeedEndpoints serves the events of the live feed receivers with the http protocol
+func (server *Server) AddLiveFeedEndpoints() {
+	server.addRoute("/livefeed/events", HandleLiveFeedEvents).Methods("GET")
+	server.addRoute("/livefeed/ws", HandleLiveFeedWebSocket)
+}
+
+// liveFeedSubscription subscribes to the receiver in the query of the request, the [LiveFeedAPI]
+// receiver if there is none. The query can narrow down the events with "types" and "chainids",
+// pick the "format" and resume "after" a sequence number. It writes the error response if the
+// request can not be served
+func liveFeedSubscription(writer http.ResponseWriter, request *http.Request) (*eventservices.EventSubscription, eventconfig.EventFormat, bool) {
+	state, err := GetState(request)
+	if err != nil {
+		wsLog.Errorf("failed to extract port from request: %s", err)
+		writer.WriteHeader(http.StatusBadRequest)
+		return nil, 0, false
+	}
+	if err := checkAuthHeader(state, request); err != nil {
+		handleUnauthorized(request, writer)
+		return nil, 0, false
+	}
+
+	query := request.URL.Query()
+	stream := eventservices.GetEventStream(query.Get("receiver"))
+	if stream == nil {
+		http.Error(writer, "no live feed receiver with the http protocol", http.StatusNotFound)
+		return nil, 0, false
+	}
+
+	filter, err := eventservices.ParseEventFilter(query.Get("types"), query.Get("chainids"))
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return nil, 0, false
+	}
+
+	after := query.Get("after")
+	if len(after) == 0 {
+		// sent by an EventSource that reconnects
+		after = request.Header.Get("Last-Event-ID")
+	}
+	var sequence uint64
+	if len(after) > 0 {
+		if sequence, err = strconv.ParseUint(after, 10, 64); err != nil {
+			http.Error(writer, "after must be a sequence number", http.StatusBadRequest)
+			return nil, 0, false
+		}
+	}
+
+	format := eventconfig.EventFormatFrom(query.Get("format"), stream.GetOutputFormat())
+	return stream.Subscribe(filter, sequence), format, true
+}
+
+// HandleLiveFeedEvents streams the live feed as server-sent events. The id of each event is its
+// sequence number, the data is the event in JSON or in base64 encoded protobuf
+func HandleLiveFeedEvents(writer http.ResponseWriter, request *http.Request) {
+	subscription, format, ok := liveFeedSubscription(writer, request)
+	if !ok {
+		return
+	}
+	flusher, ok := writer.(http.Flusher)
+	if !ok {
+		http.Error(writer, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	writer.Header().Set("Content-Type", "text/event-stream")
+	writer.Header().Set("Cache-Control", "no-cache")
+	writer.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	err := subscription.Run(request.Context().Done(), func(event *eventmessages.FactomEvent) error {
+		data, err := eventservices.MarshalEvent(event, format)
+		if err != nil {
+			return err
+		}
+		if format == eventconfig.Protobuf {
+			data = []byte(base64.StdEncoding.EncodeToString(data))
+		}
+		if _, err := fmt.Fprintf(writer, "id: %d\ndata: %s\n\n", event.Sequence, data); err != nil {
+			return err
+		}
+		flusher.Flush()
+		return nil
+	})
+	if err != nil {
+		wsLog.Debugf("live feed event stream closed: %v", err)
+	}
+}
+
+// HandleLiveFeedWebSocket streams the live feed over a websocket, the events in JSON as text
+// messages or in protobuf as binary messages. Messages from the client are ignored
+func HandleLiveFeedWebSocket(writer http.ResponseWriter, request *http.Request) {
+	subscription, format, ok := liveFeedSubscription(writer, request)
+	if !ok {
+		return
+	}
+	state, _ := GetState(request)
+
+	wsServer := websocket.Server{
+		Handshake: func(config *websocket.Config, r *http.Request) error {
+			return checkWebSocketOrigin(state, config, r)
+		},
+		Handler: func(conn *websocket.Conn) {
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				var msg []byte
+				for websocket.Message.Receive(conn, &msg) == nil {
+				}
+			}()
+
+			err := subscription.Run(done, func(event *eventmessages.FactomEvent) error {
+				data, err := eventservices.MarshalEvent(event, format)
+				if err != nil {
+					return err
+				}
+				if format == eventconfig.Protobuf {
+					return websocket.Message.Send(conn, data)
+				}
+				return websocket.Message.Send(conn, string(data))
+			})
+			if err != nil {
+				wsLog.Debugf("live feed websocket closed: %v", err)
+			}
+		},
+	}
+	wsServer.ServeHTTP(writer, request)
+}
@@ -0,0 +1,107 @@
+package wsapi_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/FactomProject/factomd/events/eventconfig"
+	"github.com/FactomProject/factomd/events/eventmessages/generated/eventmessages"
+	"github.com/FactomProject/factomd/events/eventservices"
+	"github.com/FactomProject/factomd/testHelper"
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/websocket"
+)
+
+func liveFeedTestEvent() *eventmessages.FactomEvent {
+	return &eventmessages.FactomEvent{
+		FactomNodeName: "test",
+		Event: &eventmessages.FactomEvent_NodeMessage{NodeMessage: &eventmessages.NodeMessage{
+			MessageCode: eventmessages.NodeMessageCode_STARTED,
+			MessageText: "started",
+		}},
+	}
+}
+
+func TestHandleLiveFeed(t *testing.T) {
+	dir, err := ioutil.TempDir("", "livefeed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// the events are spooled, so they are sent to the clients that connect later on
+	params := &eventservices.EventServiceParams{Name: "wsapi", Protocol: "http", OutputFormat: eventconfig.Json, SpoolPath: dir, SpoolSize: 100}
+	sender := eventservices.NewEventSenderTo(params)
+	defer sender.Shutdown()
+	for i := 0; i < 3; i++ {
+		sender.GetEventQueue() <- sender.Stamp(liveFeedTestEvent())
+	}
+
+	state := testHelper.CreateAndPopulateTestState()
+	delayedStart(t, state)
+	base := fmt.Sprintf("http://localhost:%d/livefeed", state.GetPort())
+
+	for query, status := range map[string]int{
+		"?receiver=none":               http.StatusNotFound,
+		"?receiver=wsapi&types=nope":   http.StatusBadRequest,
+		"?receiver=wsapi&chainids=01":  http.StatusBadRequest,
+		"?receiver=wsapi&after=latest": http.StatusBadRequest,
+	} {
+		resp, err := http.Get(base + "/events" + query)
+		if assert.NoError(t, err, query) {
+			assert.Equal(t, status, resp.StatusCode, query)
+			resp.Body.Close()
+		}
+	}
+
+	// server-sent events in json, resuming after the first
+	resp, err := http.Get(base + "/events?receiver=wsapi&types=nodeMessage&after=1")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	reader := bufio.NewReader(resp.Body)
+	for _, sequence := range []uint64{2, 3} {
+		id, _ := reader.ReadString('\n')
+		data, _ := reader.ReadString('\n')
+		blank, _ := reader.ReadString('\n')
+		assert.Equal(t, fmt.Sprintf("id: %d\n", sequence), id)
+		assert.Equal(t, "\n", blank)
+
+		var event struct {
+			Sequence uint64
+			Event    struct{ NodeMessage *eventmessages.NodeMessage }
+		}
+		if assert.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(data, "data: ")), &event), data) {
+			assert.Equal(t, sequence, event.Sequence)
+			assert.Equal(t, "started", event.Event.NodeMessage.GetMessageText())
+		}
+	}
+	resp.Body.Close()
+
+	// websocket in protobuf
+	url := fmt.Sprintf("ws://localhost:%d/livefeed/ws?receiver=wsapi&format=protobuf", state.GetPort())
+	conn, err := websocket.Dial(url, "", fmt.Sprintf("http://localhost:%d", state.GetPort()))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+	sender.GetEventQueue() <- sender.Stamp(liveFeedTestEvent())
+	for sequence := uint64(1); sequence <= 4; sequence++ {
+		var data []byte
+		if !assert.NoError(t, websocket.Message.Receive(conn, &data)) {
+			return
+		}
+		event := new(eventmessages.FactomEvent)
+		if assert.NoError(t, proto.Unmarshal(data, event)) {
+			assert.Equal(t, sequence, event.Sequence)
+		}
+	}
+}
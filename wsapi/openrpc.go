@@ -0,0 +1,236 @@
+package wsapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// OpenRPCVersion is the version of the OpenRPC specification the rpc.discover document follows
+const OpenRPCVersion = "1.2.6"
+
+type OpenRPCDocument struct {
+	OpenRPC    string            `json:"openrpc"`
+	Info       OpenRPCInfo       `json:"info"`
+	Methods    []OpenRPCMethod   `json:"methods"`
+	Components OpenRPCComponents `json:"components"`
+}
+
+type OpenRPCInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type OpenRPCMethod struct {
+	Name           string                     `json:"name"`
+	ParamStructure string                     `json:"paramStructure"`
+	Params         []OpenRPCContentDescriptor `json:"params"`
+	Result         OpenRPCContentDescriptor   `json:"result"`
+}
+
+type OpenRPCContentDescriptor struct {
+	Name     string      `json:"name"`
+	Required bool        `json:"required,omitempty"`
+	Schema   interface{} `json:"schema"`
+}
+
+type OpenRPCComponents struct {
+	Schemas map[string]interface{} `json:"schemas"`
+}
+
+// oneOf is used as the result of methods that answer with one of several types
+type oneOf []interface{}
+
+// HandleV2Discover returns an OpenRPC document describing the v2 methods, generated from the
+// method registry and the request and response types of the methods
+func HandleV2Discover(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	doc := new(OpenRPCDocument)
+	doc.OpenRPC = OpenRPCVersion
+	doc.Info.Title = "factomd v" + API_VERSION + " API"
+	doc.Info.Version = state.GetFactomdVersion()
+
+	g := newSchemaGenerator()
+	for name, method := range v2Methods {
+		if name == "rpc.discover" {
+			// the specification does not list the discover method itself
+			continue
+		}
+		if isReadOnlyDisabled(state, name) {
+			// not served by this node
+			continue
+		}
+		m := OpenRPCMethod{Name: name, ParamStructure: "by-name", Params: []OpenRPCContentDescriptor{}}
+		if method.Params != nil {
+			m.Params = g.params(reflect.TypeOf(method.Params))
+		}
+		m.Result = OpenRPCContentDescriptor{Name: "result", Schema: g.result(method.Result)}
+		doc.Methods = append(doc.Methods, m)
+	}
+	sort.Slice(doc.Methods, func(i, j int) bool {
+		return doc.Methods[i].Name < doc.Methods[j].Name
+	})
+	doc.Components.Schemas = g.schemas
+
+	return doc, nil
+}
+
+var (
+	hashType      = reflect.TypeOf((*interfaces.IHash)(nil)).Elem()
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	timeType      = reflect.TypeOf(time.Time{})
+)
+
+// schemaGenerator builds JSON schemas from go types the same way encoding/json serializes
+// them. Named structs are added to the components and referenced.
+type schemaGenerator struct {
+	schemas map[string]interface{}
+	names   map[reflect.Type]string
+}
+
+func newSchemaGenerator() *schemaGenerator {
+	g := new(schemaGenerator)
+	g.schemas = make(map[string]interface{})
+	g.names = make(map[reflect.Type]string)
+	return g
+}
+
+func (g *schemaGenerator) result(result interface{}) interface{} {
+	if alternatives, ok := result.(oneOf); ok {
+		var schemas []interface{}
+		for _, a := range alternatives {
+			schemas = append(schemas, g.schema(reflect.TypeOf(a)))
+		}
+		return map[string]interface{}{"oneOf": schemas}
+	}
+	if result == nil {
+		return map[string]interface{}{}
+	}
+	return g.schema(reflect.TypeOf(result))
+}
+
+// params describes each field of a request struct as a by-name parameter
+func (g *schemaGenerator) params(t reflect.Type) []OpenRPCContentDescriptor {
+	var params []OpenRPCContentDescriptor
+	for _, f := range g.fields(t) {
+		params = append(params, OpenRPCContentDescriptor{Name: f.name, Required: !f.omitEmpty, Schema: g.schema(f.typ)})
+	}
+	return params
+}
+
+type schemaField struct {
+	name      string
+	typ       reflect.Type
+	omitEmpty bool
+}
+
+// fields returns the fields of a struct as encoding/json sees them, with embedded structs flattened
+func (g *schemaGenerator) fields(t reflect.Type) []schemaField {
+	var fields []schemaField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts := tag, ""
+		if idx := strings.Index(tag, ","); idx >= 0 {
+			name, opts = tag[:idx], tag[idx:]
+		}
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				fields = append(fields, g.fields(ft)...)
+				continue
+			}
+		}
+		if f.PkgPath != "" {
+			// unexported
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields = append(fields, schemaField{name: name, typ: f.Type, omitEmpty: strings.Contains(opts, "omitempty")})
+	}
+	return fields
+}
+
+func (g *schemaGenerator) schema(t reflect.Type) interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == hashType || t == reflect.TypeOf(primitives.Hash{}):
+		return map[string]interface{}{"type": "string", "pattern": "^[0-9a-f]{64}$"}
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case t.Kind() == reflect.Interface,
+		t.Implements(marshalerType) || reflect.PtrTo(t).Implements(marshalerType):
+		// the serialization is up to the implementation
+		return map[string]interface{}{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// encoding/json writes byte slices as base64
+			return map[string]interface{}{"type": "string", "contentEncoding": "base64"}
+		}
+		return map[string]interface{}{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + g.component(t)}
+	}
+	return map[string]interface{}{}
+}
+
+// component adds a named struct to the components, and returns its name there
+func (g *schemaGenerator) component(t reflect.Type) string {
+	if name, ok := g.names[t]; ok {
+		return name
+	}
+	name := t.Name()
+	if _, taken := g.schemas[name]; taken {
+		// same name in different packages
+		name = t.String()
+	}
+	g.names[t] = name
+	// reserve the name before generating the schema, for recursive types
+	g.schemas[name] = nil
+	g.schemas[name] = g.object(t)
+	return name
+}
+
+func (g *schemaGenerator) object(t reflect.Type) interface{} {
+	properties := map[string]interface{}{}
+	required := []string{}
+	for _, f := range g.fields(t) {
+		properties[f.name] = g.schema(f.typ)
+		if !f.omitEmpty {
+			required = append(required, f.name)
+		}
+	}
+	return map[string]interface{}{"type": "object", "properties": properties, "required": required}
+}
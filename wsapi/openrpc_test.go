@@ -0,0 +1,57 @@
+package wsapi_test
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/testHelper"
+	. "github.com/FactomProject/factomd/wsapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleV2Discover(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestState()
+
+	r, jErr := HandleV2Discover(state, nil)
+	assert.Nil(t, jErr)
+	doc := r.(*OpenRPCDocument)
+	assert.Equal(t, OpenRPCVersion, doc.OpenRPC)
+
+	methods := map[string]OpenRPCMethod{}
+	for _, m := range doc.Methods {
+		methods[m.Name] = m
+	}
+	for _, name := range []string{"heights", "chain-head", "entry", "factoid-submit", "ack", "chain-entries"} {
+		assert.Contains(t, methods, name)
+	}
+	assert.NotContains(t, methods, "rpc.discover")
+
+	assert.Empty(t, methods["heights"].Params)
+	assert.Equal(t, []OpenRPCContentDescriptor{{Name: "chainid", Required: true, Schema: map[string]interface{}{"type": "string"}}}, methods["chain-head"].Params)
+
+	// every reference points to a component
+	b, err := json.Marshal(doc)
+	assert.Nil(t, err)
+	for _, ref := range regexp.MustCompile(`"#/components/schemas/([^"]+)"`).FindAllStringSubmatch(string(b), -1) {
+		assert.Contains(t, doc.Components.Schemas, ref[1])
+	}
+
+	// the response of a call has the properties the document requires
+	heights := doc.Components.Schemas["HeightsResponse"].(map[string]interface{})
+	resp, jErr := HandleV2JSONRequest(state, primitives.NewJSON2Request("heights", 1, nil))
+	assert.Nil(t, jErr)
+	b, err = json.Marshal(resp.Result)
+	assert.Nil(t, err)
+	result := map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal(b, &result))
+	for _, name := range heights["required"].([]string) {
+		assert.Contains(t, result, name)
+	}
+
+	// the method is served through the registry like any other
+	resp, jErr = HandleV2JSONRequest(state, primitives.NewJSON2Request("rpc.discover", 1, nil))
+	assert.Nil(t, jErr)
+	assert.NotNil(t, resp.Result)
+}
@@ -0,0 +1,200 @@
+package wsapi
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+)
+
+// Ways to identify a client for rate limiting
+const (
+	RateLimitKeyIP     = "ip"
+	RateLimitKeyUser   = "user"
+	RateLimitKeyIPUser = "ip+user"
+)
+
+// rateLimitSweepInterval is how often idle buckets are removed
+var rateLimitSweepInterval = time.Minute
+
+// tokenBucket holds up to burst tokens, refilled at rate tokens per second
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func (b *tokenBucket) take(now time.Time, rate float64, burst float64) bool {
+	b.tokens += now.Sub(b.last).Seconds() * rate
+	if b.tokens > burst {
+		b.tokens = burst
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// RateLimiter keeps a read and a write token bucket per client. A rate of 0 disables the
+// limit of that budget, a nil RateLimiter does not limit anything.
+type RateLimiter struct {
+	mutex sync.Mutex
+
+	key        string
+	readRate   float64
+	readBurst  float64
+	writeRate  float64
+	writeBurst float64
+
+	reads     map[string]*tokenBucket
+	writes    map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+// NewRateLimiter creates a limiter from the settings of the state, or returns nil if both
+// budgets are unlimited
+func NewRateLimiter(state interfaces.IState) *RateLimiter {
+	readRate, readBurst, writeRate, writeBurst := state.GetApiRateLimits()
+	if readRate <= 0 && writeRate <= 0 {
+		return nil
+	}
+
+	l := new(RateLimiter)
+	l.key = state.GetApiRateLimitKey()
+	l.readRate, l.readBurst = float64(readRate), float64(readBurst)
+	l.writeRate, l.writeBurst = float64(writeRate), float64(writeBurst)
+	// without a burst a client may use one second worth of its budget at once
+	if l.readBurst < 1 {
+		l.readBurst = math.Max(l.readRate, 1)
+	}
+	if l.writeBurst < 1 {
+		l.writeBurst = math.Max(l.writeRate, 1)
+	}
+	l.reads = make(map[string]*tokenBucket)
+	l.writes = make(map[string]*tokenBucket)
+	l.lastSweep = time.Now()
+	return l
+}
+
+// Client returns the name the limiter tracks the sender of the request under
+func (l *RateLimiter) Client(request *http.Request) string {
+	if l == nil {
+		return ""
+	}
+	ip, _, err := net.SplitHostPort(request.RemoteAddr)
+	if err != nil {
+		ip = request.RemoteAddr
+	}
+	user, _, _ := request.BasicAuth()
+
+	switch strings.ToLower(l.key) {
+	case RateLimitKeyUser:
+		return user
+	case RateLimitKeyIPUser:
+		return ip + "/" + user
+	default:
+		return ip
+	}
+}
+
+// Allow takes a token from the budget of the method for the client, and reports if there
+// was one
+func (l *RateLimiter) Allow(client string, method string) bool {
+	if l == nil {
+		return true
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := time.Now()
+	if now.Sub(l.lastSweep) > rateLimitSweepInterval {
+		l.sweep(now)
+	}
+
+	class, buckets, rate, burst := "read", l.reads, l.readRate, l.readBurst
+	if WriteMethods[method] {
+		class, buckets, rate, burst = "write", l.writes, l.writeRate, l.writeBurst
+	}
+	if rate <= 0 {
+		RateLimitAllowed.WithLabelValues(class).Inc()
+		return true
+	}
+
+	b, ok := buckets[client]
+	if !ok {
+		b = &tokenBucket{tokens: burst, last: now}
+		buckets[client] = b
+		RateLimitClients.Set(float64(len(l.reads) + len(l.writes)))
+	}
+	if !b.take(now, rate, burst) {
+		RateLimitRejected.WithLabelValues(class).Inc()
+		return false
+	}
+	RateLimitAllowed.WithLabelValues(class).Inc()
+	return true
+}
+
+// sweep removes the buckets that have refilled completely, they are the same as new ones
+func (l *RateLimiter) sweep(now time.Time) {
+	l.lastSweep = now
+	for client, b := range l.reads {
+		if b.tokens+now.Sub(b.last).Seconds()*l.readRate >= l.readBurst {
+			delete(l.reads, client)
+		}
+	}
+	for client, b := range l.writes {
+		if b.tokens+now.Sub(b.last).Seconds()*l.writeRate >= l.writeBurst {
+			delete(l.writes, client)
+		}
+	}
+	RateLimitClients.Set(float64(len(l.reads) + len(l.writes)))
+}
+
+// GetRateLimiter returns the limiter of the server that received the request, nil if there
+// is no limit
+func GetRateLimiter(r *http.Request) *RateLimiter {
+	ServersMutex.Lock()
+	defer ServersMutex.Unlock()
+	if server, ok := Servers[r.Header.Get("factomd-port")]; ok {
+		return server.Limiter
+	}
+	return nil
+}
+
+// handleV2LimitedRequest handles a v2 request unless the client ran out of its budget
+func handleV2LimitedRequest(state interfaces.IState, limiter *RateLimiter, client string, j *primitives.JSON2Request) (*primitives.JSON2Response, *primitives.JSONError) {
+	if !limiter.Allow(client, j.Method) {
+		return nil, NewRateLimitExceededError()
+	}
+	return HandleV2JSONRequest(state, j)
+}
+
+// RateLimitMiddleware rejects the request when the client ran out of its budget. It is used on
+// the routes that are not v2 calls, the v1 write routes take from the write budget through the
+// v2 method of the same name, every other route from the read budget
+func RateLimitMiddleware(server *Server) Middleware {
+	return func(f http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if !server.Limiter.Allow(server.Limiter.Client(r), routeMethod(r.URL.Path)) {
+				http.Error(w, "429 Too Many Requests. Rate limit exceeded.", http.StatusTooManyRequests)
+				return
+			}
+			f(w, r)
+		}
+	}
+}
+
+// routeMethod returns the name of the call of a route, "/v1/commit-entry/" is "commit-entry"
+func routeMethod(path string) string {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "/"), "v1/")
+	if i := strings.Index(path, "/"); i >= 0 {
+		path = path[:i]
+	}
+	return path
+}
@@ -0,0 +1,83 @@
+package wsapi_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/FactomProject/factomd/testHelper"
+	. "github.com/FactomProject/factomd/wsapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestState()
+	assert.Nil(t, NewRateLimiter(state), "no limiter without limits")
+
+	state.ApiReadRateLimit = 1
+	state.ApiReadRateBurst = 2
+	state.ApiWriteRateLimit = 1
+	limiter := NewRateLimiter(state)
+	assert.NotNil(t, limiter)
+
+	// the burst is used up, then the client has to wait for the bucket to refill
+	assert.True(t, limiter.Allow("a", "entry"))
+	assert.True(t, limiter.Allow("a", "heights"))
+	assert.False(t, limiter.Allow("a", "entry"))
+
+	// writes have their own budget, with a burst of the rate if none is set
+	assert.True(t, limiter.Allow("a", "commit-entry"))
+	assert.False(t, limiter.Allow("a", "factoid-submit"))
+
+	// other clients are not affected
+	assert.True(t, limiter.Allow("b", "entry"))
+	assert.True(t, limiter.Allow("b", "send-raw-message"))
+
+	var unlimited *RateLimiter
+	assert.True(t, unlimited.Allow("a", "entry"))
+}
+
+func TestRateLimiterClient(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestState()
+	state.ApiReadRateLimit = 1
+
+	request, _ := http.NewRequest("POST", "/v2", nil)
+	request.RemoteAddr = "10.0.0.1:5000"
+	request.SetBasicAuth("user", "pass")
+
+	cases := map[string]string{
+		RateLimitKeyIP:     "10.0.0.1",
+		RateLimitKeyUser:   "user",
+		RateLimitKeyIPUser: "10.0.0.1/user",
+	}
+	for key, expected := range cases {
+		state.ApiRateLimitKey = key
+		assert.Equal(t, expected, NewRateLimiter(state).Client(request), key)
+	}
+}
+
+func TestRateLimitV1(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestState()
+	state.ApiReadRateLimit = 1
+	state.ApiReadRateBurst = 2
+	state.ApiWriteRateLimit = 1
+	state.SetPort(18094)
+	delayedStart(t, state)
+
+	status := func(method string, url string) int {
+		request, _ := http.NewRequest(method, url, nil)
+		response, err := http.DefaultClient.Do(request)
+		if !assert.NoError(t, err) {
+			return 0
+		}
+		response.Body.Close()
+		return response.StatusCode
+	}
+
+	assert.Equal(t, http.StatusOK, status("GET", "http://localhost:18094/v1/heights/"))
+	assert.Equal(t, http.StatusOK, status("GET", "http://localhost:18094/v1/properties/"))
+	assert.Equal(t, http.StatusTooManyRequests, status("GET", "http://localhost:18094/v1/heights/"))
+
+	// writes have their own budget
+	assert.NotEqual(t, http.StatusTooManyRequests, status("POST", "http://localhost:18094/v1/commit-entry/"))
+	assert.Equal(t, http.StatusTooManyRequests, status("POST", "http://localhost:18094/v1/commit-entry/"))
+}
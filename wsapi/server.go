@@ -22,6 +22,11 @@ type Server struct {
 	certFile   string
 	keyFile    string
 	Port       string
+	Limiter    *RateLimiter
+
+	// the debug API has its own listener if a debug port is set, nil otherwise
+	debugServer *http.Server
+	debugRouter *mux.Router
 }
 
 type Middleware func(http.HandlerFunc) http.HandlerFunc
@@ -32,7 +37,7 @@ func InitServer(state interfaces.IState) *Server {
 
 	router := mux.NewRouter()
 	port := strconv.Itoa(state.GetPort())
-	server := Server{State: state, router: router, tlsEnabled: tlsIsEnabled, certFile: certFile, keyFile: keyFile, Port: port}
+	server := Server{State: state, router: router, tlsEnabled: tlsIsEnabled, certFile: certFile, keyFile: keyFile, Port: port, Limiter: NewRateLimiter(state)}
 
 	if tlsIsEnabled {
 		wsLog.Info("Starting encrypted API server")
@@ -58,23 +63,36 @@ func InitServer(state interfaces.IState) *Server {
 
 	wsLog.Infof("Init API server at: %s\n", address)
 
+	if debugPort := state.GetDebugApiPort(); debugPort != 0 && debugPort != state.GetPort() {
+		debugAddress := fmt.Sprintf(":%d", debugPort)
+		server.debugRouter = mux.NewRouter()
+		server.debugServer = &http.Server{Addr: debugAddress, Handler: server.debugRouter, TLSConfig: server.httpServer.TLSConfig}
+		wsLog.Infof("Init debug API server at: %s\n", debugAddress)
+	}
+
 	return &server
 }
 
 func (server *Server) Start() {
 	wsLog.Info("Starting API server")
-	go func() {
-		// returns ErrServerClosed on graceful close
-		if server.tlsEnabled {
-			if err := server.httpServer.ListenAndServeTLS(server.certFile, server.keyFile); err != http.ErrServerClosed {
-				wsLog.Errorf("ListenAndServeTLS %v", err)
-			}
-		} else {
-			if err := server.httpServer.ListenAndServe(); err != http.ErrServerClosed {
-				wsLog.Errorf("ListenAndServe %v", err)
-			}
+	go server.listenAndServe(server.httpServer)
+	if server.debugServer != nil {
+		wsLog.Info("Starting debug API server")
+		go server.listenAndServe(server.debugServer)
+	}
+}
+
+func (server *Server) listenAndServe(httpServer *http.Server) {
+	// returns ErrServerClosed on graceful close
+	if server.tlsEnabled {
+		if err := httpServer.ListenAndServeTLS(server.certFile, server.keyFile); err != http.ErrServerClosed {
+			wsLog.Errorf("ListenAndServeTLS %v", err)
 		}
-	}()
+	} else {
+		if err := httpServer.ListenAndServe(); err != http.ErrServerClosed {
+			wsLog.Errorf("ListenAndServe %v", err)
+		}
+	}
 }
 
 func (server *Server) Stop() {
@@ -83,6 +101,11 @@ func (server *Server) Stop() {
 	if err := server.httpServer.Shutdown(context.Background()); err != nil {
 		panic(err) // failure/timeout shutting down the server gracefully
 	}
+	if server.debugServer != nil {
+		if err := server.debugServer.Shutdown(context.Background()); err != nil {
+			panic(err)
+		}
+	}
 }
 
 // Logging logs all requests with its path and the time it took to process
@@ -125,13 +148,19 @@ func PanicRecovery() Middleware {
 
 // add route and Chain applies middlewares to a http.HandlerFunc
 func (server *Server) addRoute(path string, f func(http.ResponseWriter, *http.Request), middlewares ...Middleware) *mux.Route {
+	return server.addRouteTo(server.router, path, f, middlewares...)
+}
+
+// addRouteTo adds the route to the given router, the requests are still served with the
+// state of this server
+func (server *Server) addRouteTo(router *mux.Router, path string, f func(http.ResponseWriter, *http.Request), middlewares ...Middleware) *mux.Route {
 	middlewares = append(middlewares, APILogger())
 	middlewares = append(middlewares, IDInjector(server))
 	middlewares = append(middlewares, PanicRecovery()) // keep this last
 	for _, m := range middlewares {
 		f = m(f)
 	}
-	return server.router.HandleFunc(path, f)
+	return router.HandleFunc(path, f)
 }
 
 func (server *Server) AddRootEndpoints() {
@@ -142,15 +171,22 @@ func (server *Server) AddRootEndpoints() {
 		})
 
 		server.router.Use(c.Handler)
+		if server.debugRouter != nil {
+			server.debugRouter.Use(c.Handler)
+		}
 	}
 
 	// for the v1 endpoints the default behavior of a not allowed method behaviour is different.
 	// for v2 and debug endpoints this isn't applicable as all methods accept both gets, and posts
 	server.router.MethodNotAllowedHandler = methodNotAllowedHandler()
 
-	// start the debugging api if we are not on the main network
+	// start the debugging api if we are not on the main network, on its own port if one is set
 	if state.GetNetworkName() != "MAIN" {
-		server.addRoute("/debug", HandleDebug).Methods("GET", "POST")
+		router := server.router
+		if server.debugRouter != nil {
+			router = server.debugRouter
+		}
+		server.addRouteTo(router, "/debug", HandleDebug, RateLimitMiddleware(server)).Methods("GET", "POST")
 	}
 }
 
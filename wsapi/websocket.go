@@ -0,0 +1,444 @@
+// Copyright 2017 Factom Foundation
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package wsapi
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/FactomProject/factomd/common/constants"
+	"github.com/FactomProject/factomd/common/interfaces"
+	"github.com/FactomProject/factomd/common/primitives"
+	"golang.org/x/net/websocket"
+)
+
+// Subscription topics a websocket client can ask for
+const (
+	TopicDirectoryBlock = "directory-block"
+	TopicEntries        = "entries"
+	TopicAck            = "ack"
+)
+
+// SubscriptionPollInterval is how often a websocket connection checks the state for new data
+var SubscriptionPollInterval = time.Second
+
+// MaxSubscriptionsPerConnection caps the subscriptions of a websocket connection, as every one of
+// them is checked on each poll
+var MaxSubscriptionsPerConnection = 100
+
+func (server *Server) AddWebSocketEndpoints() {
+	server.addRoute("/ws", HandleWebSocket)
+}
+
+// HandleWebSocket upgrades the connection to a websocket. The connection accepts the same
+// JSON2Requests as the /v2 endpoint, plus "subscribe" and "unsubscribe", and pushes
+// notifications for active subscriptions as the state advances. An ack subscription ends
+// with the notification of its DBlockConfirmed status.
+func HandleWebSocket(writer http.ResponseWriter, request *http.Request) {
+	state, err := GetState(request)
+	if err != nil {
+		wsLog.Errorf("failed to extract port from request: %s", err)
+		writer.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if err := checkAuthHeader(state, request); err != nil {
+		handleUnauthorized(request, writer)
+		return
+	}
+
+	wsServer := websocket.Server{
+		Handshake: func(config *websocket.Config, r *http.Request) error {
+			return checkWebSocketOrigin(state, config, r)
+		},
+		Handler: func(conn *websocket.Conn) {
+			limiter := GetRateLimiter(request)
+			newSubscriber(state, conn, limiter, limiter.Client(request)).run()
+		},
+	}
+	wsServer.ServeHTTP(writer, request)
+}
+
+// checkWebSocketOrigin allows any origin unless cors domains are configured, in which case
+// the origin of the handshake must be one of them
+func checkWebSocketOrigin(state interfaces.IState, config *websocket.Config, r *http.Request) error {
+	origin, err := websocket.Origin(config, r)
+	if err != nil {
+		return err
+	}
+	config.Origin = origin
+
+	domains := state.GetCorsDomains()
+	if len(domains) == 0 {
+		// non-browser clients do not send an origin
+		return nil
+	}
+	if origin == nil {
+		return fmt.Errorf("missing origin")
+	}
+	for _, d := range domains {
+		if d == "*" || d == origin.String() {
+			return nil
+		}
+	}
+	return fmt.Errorf("origin %s not allowed", origin)
+}
+
+type SubscribeRequest struct {
+	Topic           string `json:"topic"`
+	ChainID         string `json:"chainid,omitempty"`
+	Hash            string `json:"hash,omitempty"`
+	FullTransaction string `json:"fulltransaction,omitempty"`
+}
+
+type SubscriptionRequest struct {
+	Subscription int64 `json:"subscription"`
+}
+
+type SubscribeResponse struct {
+	Subscription int64 `json:"subscription"`
+}
+
+type UnsubscribeResponse struct {
+	Message string `json:"message"`
+}
+
+type SubscriptionNotification struct {
+	Subscription int64       `json:"subscription"`
+	Topic        string      `json:"topic"`
+	Result       interface{} `json:"result"`
+}
+
+type DirectoryBlockNotification struct {
+	KeyMR  string                  `json:"keymr"`
+	Height int64                   `json:"height"`
+	DBlock *DirectoryBlockResponse `json:"dblock"`
+}
+
+type EntryNotification struct {
+	EntryHash       string   `json:"entryhash"`
+	EntryBlockKeyMR string   `json:"entryblockkeymr"`
+	Height          int64    `json:"height"`
+	ChainID         string   `json:"chainid"`
+	Content         string   `json:"content"`
+	ExtIDs          []string `json:"extids"`
+}
+
+type subscription struct {
+	id      int64
+	topic   string
+	chainID interfaces.IHash
+	ack     *EntryAckWithChainRequest
+	last    string // last ack status pushed, to only notify on changes
+}
+
+// subscriber holds the subscriptions of a single websocket connection
+type subscriber struct {
+	state   interfaces.IState
+	conn    *websocket.Conn
+	limiter *RateLimiter
+	client  string
+
+	sendMutex sync.Mutex
+	subMutex  sync.Mutex
+	subs      map[int64]*subscription
+	nextID    int64
+
+	dblockHeight uint32
+	entryHeight  uint32
+}
+
+func newSubscriber(state interfaces.IState, conn *websocket.Conn, limiter *RateLimiter, client string) *subscriber {
+	s := new(subscriber)
+	s.state = state
+	s.conn = conn
+	s.limiter = limiter
+	s.client = client
+	s.subs = make(map[int64]*subscription)
+	s.dblockHeight = state.GetHighestSavedBlk()
+	s.entryHeight = s.completeEntryHeight()
+	return s
+}
+
+func (s *subscriber) run() {
+	done := make(chan struct{})
+	defer close(done)
+	go s.poll(done)
+
+	for {
+		var msg string
+		if err := websocket.Message.Receive(s.conn, &msg); err != nil {
+			wsLog.Debugf("websocket connection closed: %v", err)
+			return
+		}
+
+		j, err := primitives.ParseJSON2Request(msg)
+		if err != nil {
+			s.sendError(nil, NewInvalidRequestError())
+			continue
+		}
+		// subscribe and unsubscribe are not write methods, so like the v2 reads they take a token
+		// from the read budget of the client
+		if !s.limiter.Allow(s.client, j.Method) {
+			s.sendError(j, NewRateLimitExceededError())
+			continue
+		}
+
+		var resp interface{}
+		var jsonError *primitives.JSONError
+		switch j.Method {
+		case "subscribe":
+			resp, jsonError = s.subscribe(j.Params)
+		case "unsubscribe":
+			resp, jsonError = s.unsubscribe(j.Params)
+		default:
+			var jsonResp *primitives.JSON2Response
+			jsonResp, jsonError = HandleV2JSONRequest(s.state, j)
+			if jsonResp != nil {
+				resp = jsonResp.Result
+			}
+		}
+		if jsonError != nil {
+			s.sendError(j, jsonError)
+			continue
+		}
+
+		jsonResp := primitives.NewJSON2Response()
+		jsonResp.ID = j.ID
+		jsonResp.Result = resp
+		s.send(jsonResp.String())
+	}
+}
+
+func (s *subscriber) subscribe(params interface{}) (interface{}, *primitives.JSONError) {
+	req := new(SubscribeRequest)
+	err := MapToObject(params, req)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+
+	sub := new(subscription)
+	sub.topic = req.Topic
+	switch req.Topic {
+	case TopicDirectoryBlock:
+	case TopicEntries:
+		sub.chainID, err = primitives.HexToHash(req.ChainID)
+		if err != nil {
+			return nil, NewCustomInvalidParamsError("ChainID must be 64 hex encoded characters")
+		}
+	case TopicAck:
+		sub.ack = &EntryAckWithChainRequest{Hash: req.Hash, ChainID: req.ChainID, FullTransaction: req.FullTransaction}
+		// validate the request up front so a bad subscription is rejected right away
+		if _, jsonError := HandleV2ACKWithChain(s.state, sub.ack); jsonError != nil {
+			return nil, jsonError
+		}
+	default:
+		return nil, NewCustomInvalidParamsError("Unknown topic")
+	}
+
+	s.subMutex.Lock()
+	defer s.subMutex.Unlock()
+	if len(s.subs) >= MaxSubscriptionsPerConnection {
+		return nil, NewTooManySubscriptionsError(MaxSubscriptionsPerConnection)
+	}
+	s.nextID++
+	sub.id = s.nextID
+	s.subs[sub.id] = sub
+
+	return &SubscribeResponse{Subscription: sub.id}, nil
+}
+
+func (s *subscriber) unsubscribe(params interface{}) (interface{}, *primitives.JSONError) {
+	req := new(SubscriptionRequest)
+	err := MapToObject(params, req)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+
+	s.subMutex.Lock()
+	defer s.subMutex.Unlock()
+	if _, ok := s.subs[req.Subscription]; !ok {
+		return nil, NewCustomInvalidParamsError("Unknown subscription")
+	}
+	delete(s.subs, req.Subscription)
+
+	return &UnsubscribeResponse{Message: "Successfully unsubscribed"}, nil
+}
+
+func (s *subscriber) poll(done chan struct{}) {
+	ticker := time.NewTicker(SubscriptionPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			s.notify()
+		}
+	}
+}
+
+// completeEntryHeight is the highest height for which all entries are in the database
+func (s *subscriber) completeEntryHeight() uint32 {
+	h := s.state.GetEntryBlockDBHeightComplete()
+	if saved := s.state.GetHighestSavedBlk(); saved < h {
+		h = saved
+	}
+	return h
+}
+
+func (s *subscriber) snapshot() []*subscription {
+	s.subMutex.Lock()
+	defer s.subMutex.Unlock()
+	subs := make([]*subscription, 0, len(s.subs))
+	for _, sub := range s.subs {
+		subs = append(subs, sub)
+	}
+	return subs
+}
+
+func (s *subscriber) notify() {
+	subs := s.snapshot()
+	dbase := s.state.GetDB()
+
+	saved := s.state.GetHighestSavedBlk()
+	for ; s.dblockHeight < saved; s.dblockHeight++ {
+		block, err := dbase.FetchDBlockByHeight(s.dblockHeight + 1)
+		if err != nil || block == nil {
+			break
+		}
+		for _, sub := range subs {
+			if sub.topic == TopicDirectoryBlock {
+				s.sendNotification(sub, DBlockToNotification(block))
+			}
+		}
+	}
+
+	complete := s.completeEntryHeight()
+	for ; s.entryHeight < complete; s.entryHeight++ {
+		block, err := dbase.FetchDBlockByHeight(s.entryHeight + 1)
+		if err != nil || block == nil {
+			break
+		}
+		for _, sub := range subs {
+			if sub.topic == TopicEntries {
+				s.notifyEntries(sub, block)
+			}
+		}
+	}
+
+	for _, sub := range subs {
+		if sub.topic != TopicAck {
+			continue
+		}
+		status, jsonError := HandleV2ACKWithChain(s.state, sub.ack)
+		if jsonError != nil {
+			continue
+		}
+		b, err := json.Marshal(status)
+		if err != nil || string(b) == sub.last {
+			continue
+		}
+		sub.last = string(b)
+		s.sendNotification(sub, status)
+
+		// the status does not change once it is in a directory block
+		if ackConfirmed(sub.ack, status) {
+			s.subMutex.Lock()
+			delete(s.subs, sub.id)
+			s.subMutex.Unlock()
+		}
+	}
+}
+
+// ackConfirmed returns true if the status of the request is DBlockConfirmed, that of the
+// commit for a request on the entry credit chain, that of the entry otherwise
+func ackConfirmed(req *EntryAckWithChainRequest, status interface{}) bool {
+	switch status := status.(type) {
+	case *FactoidTxStatus:
+		return status.Status == AckStatusDBlockConfirmed
+	case *EntryStatus:
+		if req.ChainID == "c" || req.ChainID == hex.EncodeToString(constants.EC_CHAINID) {
+			return status.CommitData.Status == AckStatusDBlockConfirmed
+		}
+		return status.EntryData.Status == AckStatusDBlockConfirmed
+	}
+	return false
+}
+
+func (s *subscriber) notifyEntries(sub *subscription, block interfaces.IDirectoryBlock) {
+	dbase := s.state.GetDB()
+	for _, dbEntry := range block.GetDBEntries() {
+		if !dbEntry.GetChainID().IsSameAs(sub.chainID) {
+			continue
+		}
+		eblock, err := dbase.FetchEBlock(dbEntry.GetKeyMR())
+		if err != nil || eblock == nil {
+			wsLog.Errorf("failed to fetch entry block %s for subscription", dbEntry.GetKeyMR())
+			continue
+		}
+		for _, h := range eblock.GetEntryHashes() {
+			if h.IsMinuteMarker() {
+				continue
+			}
+			entry, err := dbase.FetchEntry(h)
+			if err != nil || entry == nil {
+				wsLog.Errorf("failed to fetch entry %s for subscription", h)
+				continue
+			}
+			e := new(EntryNotification)
+			e.EntryHash = h.String()
+			e.EntryBlockKeyMR = dbEntry.GetKeyMR().String()
+			e.Height = int64(block.GetDatabaseHeight())
+			e.ChainID = entry.GetChainIDHash().String()
+			e.Content = hex.EncodeToString(entry.GetContent())
+			for _, v := range entry.ExternalIDs() {
+				e.ExtIDs = append(e.ExtIDs, hex.EncodeToString(v))
+			}
+			s.sendNotification(sub, e)
+		}
+	}
+}
+
+// DBlockToNotification converts a directory block into the result of a directory-block notification
+func DBlockToNotification(block interfaces.IDirectoryBlock) *DirectoryBlockNotification {
+	n := new(DirectoryBlockNotification)
+	n.KeyMR = block.GetKeyMR().String()
+	n.Height = int64(block.GetDatabaseHeight())
+	n.DBlock = DBlockToResp(block)
+	return n
+}
+
+func (s *subscriber) sendNotification(sub *subscription, result interface{}) {
+	n := new(SubscriptionNotification)
+	n.Subscription = sub.id
+	n.Topic = sub.topic
+	n.Result = result
+
+	// notifications are JSON-RPC requests without an id
+	j := primitives.NewJSON2Request("subscription", nil, n)
+	s.send(j.String())
+}
+
+func (s *subscriber) sendError(j *primitives.JSON2Request, jErr *primitives.JSONError) {
+	resp := primitives.NewJSON2Response()
+	if j != nil {
+		resp.ID = j.ID
+	}
+	resp.Error = jErr
+	s.send(resp.String())
+}
+
+func (s *subscriber) send(msg string) {
+	s.sendMutex.Lock()
+	defer s.sendMutex.Unlock()
+	if err := websocket.Message.Send(s.conn, msg); err != nil {
+		wsLog.Debugf("failed to write websocket message: %v", err)
+	}
+}
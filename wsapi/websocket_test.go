@@ -0,0 +1,157 @@
+package wsapi_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/FactomProject/factomd/common/primitives"
+	"github.com/FactomProject/factomd/testHelper"
+	. "github.com/FactomProject/factomd/wsapi"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/websocket"
+)
+
+func TestHandleWebSocket(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestState()
+	delayedStart(t, state)
+
+	url := fmt.Sprintf("ws://localhost:%d/ws", state.GetPort())
+	conn, err := websocket.Dial(url, "", fmt.Sprintf("http://localhost:%d", state.GetPort()))
+	if err != nil {
+		t.Fatalf("failed to dial websocket: %v", err)
+	}
+	defer conn.Close()
+
+	cases := map[string]struct {
+		Method  string
+		Params  interface{}
+		Success bool
+	}{
+		"properties":             {"properties", nil, true},
+		"subscribe-dblocks":      {"subscribe", SubscribeRequest{Topic: TopicDirectoryBlock}, true},
+		"subscribe-entries":      {"subscribe", SubscribeRequest{Topic: TopicEntries, ChainID: "000000000000000000000000000000000000000000000000000000000000000a"}, true},
+		"subscribe-bad-chain":    {"subscribe", SubscribeRequest{Topic: TopicEntries, ChainID: "xyz"}, false},
+		"subscribe-bad-topic":    {"subscribe", SubscribeRequest{Topic: "nope"}, false},
+		"unsubscribe-bad-id":     {"unsubscribe", SubscriptionRequest{Subscription: 100}, false},
+		"unknown-method":         {"nope", nil, false},
+		"subscribe-missing-hash": {"subscribe", SubscribeRequest{Topic: TopicAck, ChainID: "f"}, false},
+	}
+
+	for name, testCase := range cases {
+		t.Logf("test case '%s'", name)
+		req := primitives.NewJSON2Request(testCase.Method, 1, testCase.Params)
+		assert.Nil(t, websocket.Message.Send(conn, req.String()), name)
+
+		var msg string
+		assert.Nil(t, websocket.Message.Receive(conn, &msg), name)
+
+		resp := primitives.NewJSON2Response()
+		assert.Nil(t, json.Unmarshal([]byte(msg), resp), name)
+		assert.Equal(t, testCase.Success, resp.Error == nil, "%s: %s", name, msg)
+		assert.Equal(t, testCase.Success, resp.Result != nil, "%s: %s", name, msg)
+	}
+}
+
+func TestWebSocketAckNotification(t *testing.T) {
+	defer func(interval time.Duration) { SubscriptionPollInterval = interval }(SubscriptionPollInterval)
+	SubscriptionPollInterval = 10 * time.Millisecond
+
+	state := testHelper.CreateAndPopulateTestState()
+	delayedStart(t, state)
+
+	url := fmt.Sprintf("ws://localhost:%d/ws", state.GetPort())
+	conn, err := websocket.Dial(url, "", fmt.Sprintf("http://localhost:%d", state.GetPort()))
+	if err != nil {
+		t.Fatalf("failed to dial websocket: %v", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(10 * time.Second))
+
+	// a transaction that is already in a directory block
+	fblock, err := state.DB.FetchFBlockByHeight(0)
+	if err != nil || fblock == nil {
+		t.Fatal(fblock, err)
+	}
+	txID := fblock.GetTransactions()[0].GetSigHash().String()
+
+	req := primitives.NewJSON2Request("subscribe", 1, SubscribeRequest{Topic: TopicAck, ChainID: "f", Hash: txID})
+	assert.Nil(t, websocket.Message.Send(conn, req.String()))
+
+	// the reply and the notification can arrive in either order
+	var id float64
+	var notification *SubscriptionNotification
+	for i := 0; i < 2; i++ {
+		var msg string
+		if !assert.Nil(t, websocket.Message.Receive(conn, &msg)) {
+			return
+		}
+		fields := map[string]json.RawMessage{}
+		assert.Nil(t, json.Unmarshal([]byte(msg), &fields), msg)
+		if _, ok := fields["method"]; ok {
+			n := new(SubscriptionNotification)
+			assert.Nil(t, json.Unmarshal(fields["params"], n), msg)
+			notification = n
+			continue
+		}
+		resp := new(SubscribeResponse)
+		assert.Nil(t, json.Unmarshal(fields["result"], resp), msg)
+		id = float64(resp.Subscription)
+	}
+	if !assert.NotNil(t, notification) {
+		return
+	}
+	assert.Equal(t, TopicAck, notification.Topic)
+	assert.Equal(t, id, float64(notification.Subscription))
+	status := notification.Result.(map[string]interface{})
+	assert.Equal(t, txID, status["txid"])
+	assert.Equal(t, AckStatusDBlockConfirmed, status["status"])
+
+	// the subscription ends once the transaction is confirmed
+	time.Sleep(5 * SubscriptionPollInterval)
+	req = primitives.NewJSON2Request("unsubscribe", 2, SubscriptionRequest{Subscription: int64(id)})
+	assert.Nil(t, websocket.Message.Send(conn, req.String()))
+	var msg string
+	assert.Nil(t, websocket.Message.Receive(conn, &msg))
+	resp := primitives.NewJSON2Response()
+	assert.Nil(t, json.Unmarshal([]byte(msg), resp), msg)
+	assert.NotNil(t, resp.Error, msg)
+}
+
+func TestWebSocketSubscriptionLimits(t *testing.T) {
+	defer func(max int) { MaxSubscriptionsPerConnection = max }(MaxSubscriptionsPerConnection)
+	MaxSubscriptionsPerConnection = 2
+
+	state := testHelper.CreateAndPopulateTestState()
+	state.ApiReadRateLimit = 1
+	state.ApiReadRateBurst = 4
+	state.SetPort(18096)
+	delayedStart(t, state)
+
+	url := fmt.Sprintf("ws://localhost:%d/ws", state.GetPort())
+	conn, err := websocket.Dial(url, "", fmt.Sprintf("http://localhost:%d", state.GetPort()))
+	if err != nil {
+		t.Fatalf("failed to dial websocket: %v", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(10 * time.Second))
+
+	call := func(method string, params interface{}) *primitives.JSON2Response {
+		req := primitives.NewJSON2Request(method, 1, params)
+		assert.Nil(t, websocket.Message.Send(conn, req.String()))
+		var msg string
+		assert.Nil(t, websocket.Message.Receive(conn, &msg))
+		resp := primitives.NewJSON2Response()
+		assert.Nil(t, json.Unmarshal([]byte(msg), resp), msg)
+		return resp
+	}
+
+	assert.Nil(t, call("subscribe", SubscribeRequest{Topic: TopicDirectoryBlock}).Error)
+	assert.Nil(t, call("subscribe", SubscribeRequest{Topic: TopicDirectoryBlock}).Error)
+	assert.Equal(t, NewTooManySubscriptionsError(2), call("subscribe", SubscribeRequest{Topic: TopicDirectoryBlock}).Error)
+
+	// the subscribe calls used up the read budget of the client
+	assert.Nil(t, call("unsubscribe", SubscriptionRequest{Subscription: 1}).Error)
+	assert.Equal(t, NewRateLimitExceededError(), call("subscribe", SubscribeRequest{Topic: TopicDirectoryBlock}).Error)
+}
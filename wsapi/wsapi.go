@@ -43,6 +43,8 @@ func Start(state interfaces.IState) {
 		server.AddRootEndpoints()
 		server.AddV1Endpoints()
 		server.AddV2Endpoints()
+		server.AddWebSocketEndpoints()
+		server.AddLiveFeedEndpoints()
 
 		Servers[port] = server
 
@@ -140,6 +140,46 @@ type EntryResponse struct {
 	ExtIDs  []string `json:"extids"`
 }
 
+type ChainEntry struct {
+	EntryHash       string   `json:"entryhash"`
+	EntryBlockKeyMR string   `json:"entryblockkeymr"`
+	DBHeight        int64    `json:"dbheight"`
+	Content         string   `json:"content"`
+	ExtIDs          []string `json:"extids"`
+	Pruned          bool     `json:"pruned,omitempty"`
+}
+
+type ChainEntriesResponse struct {
+	Entries    []ChainEntry `json:"entries"`
+	NextCursor *ChainCursor `json:"nextcursor,omitempty"`
+}
+
+type AddressTransaction struct {
+	TxID   string `json:"txid"`
+	Height int64  `json:"height"`
+}
+
+type AddressTransactionsResponse struct {
+	Transactions []AddressTransaction `json:"transactions"`
+	Total        int                  `json:"total"`
+	IndexHeight  int64                `json:"indexheight"`
+}
+
+type EntriesByExtIDResponse struct {
+	EntryHashes []string `json:"entryhashes"`
+	NextCursor  string   `json:"nextcursor,omitempty"`
+	IndexHeight int64    `json:"indexheight"`
+}
+
+type BalanceAtHeightResponse struct {
+	Balance int64 `json:"balance"`
+	Height  int64 `json:"height"`
+}
+
+type AuthoritiesResponse struct {
+	Authorities []interfaces.IAuthority `json:"authorities"`
+}
+
 type ChainHeadResponse struct {
 	ChainHead          string `json:"chainhead"`
 	ChainInProcessList bool   `json:"chaininprocesslist"`
@@ -294,6 +334,44 @@ type ChainIDRequest struct {
 	ChainID string `json:"chainid"`
 }
 
+// ChainCursor points to an entry in a chain by the KeyMR of its entry block and its index in
+// the entry block's list of entry hashes
+type ChainCursor struct {
+	KeyMR string `json:"keymr"`
+	Index int    `json:"index"`
+}
+
+type AddressTransactionsRequest struct {
+	Address string `json:"address"`
+	Offset  int    `json:"offset,omitempty"`
+	Limit   int    `json:"limit,omitempty"`
+}
+
+type EntriesByExtIDRequest struct {
+	ChainID string `json:"chainid"`
+	ExtID   string `json:"extid"`
+	Cursor  string `json:"cursor,omitempty"`
+	Limit   int    `json:"limit,omitempty"`
+}
+
+type BalanceAtHeightRequest struct {
+	Address string `json:"address"`
+	Height  int64  `json:"height"`
+}
+
+type MultipleAddressesRequest struct {
+	Addresses []string `json:"addresses"`
+}
+
+type ChainEntriesRequest struct {
+	ChainID     string       `json:"chainid"`
+	Cursor      *ChainCursor `json:"cursor,omitempty"`
+	Limit       int          `json:"limit,omitempty"`
+	Reverse     bool         `json:"reverse,omitempty"`
+	StartHeight *int64       `json:"startheight,omitempty"`
+	EndHeight   *int64       `json:"endheight,omitempty"`
+}
+
 type EntryRequest struct {
 	Entry string `json:"entry"`
 }
@@ -13,31 +13,31 @@ import (
 )
 
 func (server *Server) AddV1Endpoints() {
-	server.addRoute("/v1/factoid-submit/", HandleFactoidSubmit, CheckHttpPasswordOkV1Middleware()).Methods("POST")
-	server.addRoute("/v1/commit-chain/", HandleCommitChain, CheckHttpPasswordOkV1Middleware()).Methods("POST")
-	server.addRoute("/v1/reveal-chain/", HandleRevealChain, CheckHttpPasswordOkV1Middleware()).Methods("POST")
-	server.addRoute("/v1/commit-entry/", HandleCommitEntry, CheckHttpPasswordOkV1Middleware()).Methods("POST")
-	server.addRoute("/v1/reveal-entry/", HandleRevealEntry, CheckHttpPasswordOkV1Middleware()).Methods("POST")
-
-	server.addRoute("/v1/directory-block-head/", HandleDirectoryBlockHead, CheckHttpPasswordOkV1Middleware()).Methods("GET")
-	server.addRoute("/v1/get-raw-data/{hash}", HandleGetRaw, CheckHttpPasswordOkV1Middleware()).Methods("GET")
-	server.addRoute("/v1/get-receipt/{hash}", HandleGetReceipt, CheckHttpPasswordOkV1Middleware()).Methods("GET")
-	server.addRoute("/v1/directory-block-by-keymr/{keymr}", HandleDirectoryBlock, CheckHttpPasswordOkV1Middleware()).Methods("GET")
-	server.addRoute("/v1/directory-block-height/", HandleDirectoryBlockHeight, CheckHttpPasswordOkV1Middleware()).Methods("GET")
-	server.addRoute("/v1/entry-block-by-keymr/{keymr}", HandleEntryBlock, CheckHttpPasswordOkV1Middleware()).Methods("GET")
-	server.addRoute("/v1/entry-by-hash/{hash}", HandleEntry, CheckHttpPasswordOkV1Middleware()).Methods("GET")
-	server.addRoute("/v1/chain-head/{chainid}", HandleChainHead, CheckHttpPasswordOkV1Middleware()).Methods("GET")
-	server.addRoute("/v1/entry-credit-balance/{address}", HandleEntryCreditBalance, CheckHttpPasswordOkV1Middleware()).Methods("GET")
-	server.addRoute("/v1/factoid-balance/{address}", HandleFactoidBalance, CheckHttpPasswordOkV1Middleware()).Methods("GET")
-	server.addRoute("/v1/factoid-get-fee/", HandleGetFee, CheckHttpPasswordOkV1Middleware()).Methods("GET")
-	server.addRoute("/v1/properties/", HandleProperties, CheckHttpPasswordOkV1Middleware()).Methods("GET")
-	server.addRoute("/v1/heights/", HandleHeights, CheckHttpPasswordOkV1Middleware()).Methods("GET")
-
-	server.addRoute("/v1/dblock-by-height/{height:[0-9]+}", HandleDBlockByHeight, CheckHttpPasswordOkV1Middleware()).Methods("GET")
-	server.addRoute("/v1/ecblock-by-height/{height:[0-9]+}", HandleECBlockByHeight, CheckHttpPasswordOkV1Middleware()).Methods("GET")
-	server.addRoute("/v1/fblock-by-height/{height:[0-9]+}", HandleFBlockByHeight, CheckHttpPasswordOkV1Middleware()).Methods("GET")
-	server.addRoute("/v1/ablock-by-height/{height:[0-9]+}", HandleABlockByHeight, CheckHttpPasswordOkV1Middleware()).Methods("GET")
-	server.addRoute("/v1/dblock-by-height/{height:[0-9]+}", HandleDBlockByHeight, CheckHttpPasswordOkV1Middleware()).Methods("GET")
+	server.addRoute("/v1/factoid-submit/", HandleFactoidSubmit, RateLimitMiddleware(server), CheckHttpPasswordOkV1Middleware(), RejectReadOnlyV1Middleware()).Methods("POST")
+	server.addRoute("/v1/commit-chain/", HandleCommitChain, RateLimitMiddleware(server), CheckHttpPasswordOkV1Middleware(), RejectReadOnlyV1Middleware()).Methods("POST")
+	server.addRoute("/v1/reveal-chain/", HandleRevealChain, RateLimitMiddleware(server), CheckHttpPasswordOkV1Middleware(), RejectReadOnlyV1Middleware()).Methods("POST")
+	server.addRoute("/v1/commit-entry/", HandleCommitEntry, RateLimitMiddleware(server), CheckHttpPasswordOkV1Middleware(), RejectReadOnlyV1Middleware()).Methods("POST")
+	server.addRoute("/v1/reveal-entry/", HandleRevealEntry, RateLimitMiddleware(server), CheckHttpPasswordOkV1Middleware(), RejectReadOnlyV1Middleware()).Methods("POST")
+
+	server.addRoute("/v1/directory-block-head/", HandleDirectoryBlockHead, RateLimitMiddleware(server), CheckHttpPasswordOkV1Middleware()).Methods("GET")
+	server.addRoute("/v1/get-raw-data/{hash}", HandleGetRaw, RateLimitMiddleware(server), CheckHttpPasswordOkV1Middleware()).Methods("GET")
+	server.addRoute("/v1/get-receipt/{hash}", HandleGetReceipt, RateLimitMiddleware(server), CheckHttpPasswordOkV1Middleware()).Methods("GET")
+	server.addRoute("/v1/directory-block-by-keymr/{keymr}", HandleDirectoryBlock, RateLimitMiddleware(server), CheckHttpPasswordOkV1Middleware()).Methods("GET")
+	server.addRoute("/v1/directory-block-height/", HandleDirectoryBlockHeight, RateLimitMiddleware(server), CheckHttpPasswordOkV1Middleware()).Methods("GET")
+	server.addRoute("/v1/entry-block-by-keymr/{keymr}", HandleEntryBlock, RateLimitMiddleware(server), CheckHttpPasswordOkV1Middleware()).Methods("GET")
+	server.addRoute("/v1/entry-by-hash/{hash}", HandleEntry, RateLimitMiddleware(server), CheckHttpPasswordOkV1Middleware()).Methods("GET")
+	server.addRoute("/v1/chain-head/{chainid}", HandleChainHead, RateLimitMiddleware(server), CheckHttpPasswordOkV1Middleware()).Methods("GET")
+	server.addRoute("/v1/entry-credit-balance/{address}", HandleEntryCreditBalance, RateLimitMiddleware(server), CheckHttpPasswordOkV1Middleware()).Methods("GET")
+	server.addRoute("/v1/factoid-balance/{address}", HandleFactoidBalance, RateLimitMiddleware(server), CheckHttpPasswordOkV1Middleware()).Methods("GET")
+	server.addRoute("/v1/factoid-get-fee/", HandleGetFee, RateLimitMiddleware(server), CheckHttpPasswordOkV1Middleware()).Methods("GET")
+	server.addRoute("/v1/properties/", HandleProperties, RateLimitMiddleware(server), CheckHttpPasswordOkV1Middleware()).Methods("GET")
+	server.addRoute("/v1/heights/", HandleHeights, RateLimitMiddleware(server), CheckHttpPasswordOkV1Middleware()).Methods("GET")
+
+	server.addRoute("/v1/dblock-by-height/{height:[0-9]+}", HandleDBlockByHeight, RateLimitMiddleware(server), CheckHttpPasswordOkV1Middleware()).Methods("GET")
+	server.addRoute("/v1/ecblock-by-height/{height:[0-9]+}", HandleECBlockByHeight, RateLimitMiddleware(server), CheckHttpPasswordOkV1Middleware()).Methods("GET")
+	server.addRoute("/v1/fblock-by-height/{height:[0-9]+}", HandleFBlockByHeight, RateLimitMiddleware(server), CheckHttpPasswordOkV1Middleware()).Methods("GET")
+	server.addRoute("/v1/ablock-by-height/{height:[0-9]+}", HandleABlockByHeight, RateLimitMiddleware(server), CheckHttpPasswordOkV1Middleware()).Methods("GET")
+	server.addRoute("/v1/dblock-by-height/{height:[0-9]+}", HandleDBlockByHeight, RateLimitMiddleware(server), CheckHttpPasswordOkV1Middleware()).Methods("GET")
 }
 
 // Check authentication header
@@ -52,6 +52,20 @@ func CheckHttpPasswordOkV1Middleware() Middleware {
 	}
 }
 
+// Reject write calls on a read-only API
+func RejectReadOnlyV1Middleware() Middleware {
+	return func(f http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if state, err := GetState(r); err == nil && state.IsApiReadOnly() {
+				http.Error(w, "403 Forbidden. Read-only API.", http.StatusForbidden)
+				return
+			}
+			// Call the next middleware/handler in chain
+			f(w, r)
+		}
+	}
+}
+
 func checkHttpPasswordOkV1(writer http.ResponseWriter, request *http.Request) bool {
 	if state, err := GetState(request); err == nil {
 		if err := checkAuthHeader(state, request); err != nil {
@@ -5,14 +5,18 @@
 package wsapi
 
 import (
+	"bytes"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"math"
 	"net/http"
 	"os"
 	"reflect"
+	"runtime/debug"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/FactomProject/factomd/anchor"
@@ -30,6 +34,15 @@ import (
 
 const API_VERSION string = "2.0"
 
+// MaxAddressTransactionsLimit is the maximum number of transactions returned by a single address-transactions call
+const MaxAddressTransactionsLimit = 1000
+
+// MaxEntriesByExtIDLimit is the maximum number of entry hashes returned by a single entries-by-extid call
+const MaxEntriesByExtIDLimit = 1000
+
+// MaxChainEntriesLimit is the maximum number of entries returned by a single chain-entries call
+const MaxChainEntriesLimit = 1000
+
 func (server *Server) AddV2Endpoints() {
 	server.addRoute("/v2", HandleV2)
 }
@@ -50,19 +63,27 @@ func HandleV2(writer http.ResponseWriter, request *http.Request) {
 		return
 	}
 
+	limiter := GetRateLimiter(request)
+	client := limiter.Client(request)
+
 	body, err := ioutil.ReadAll(request.Body)
 	if err != nil {
 		HandleV2Error(writer, nil, NewInvalidRequestError())
 		return
 	}
 
+	if isBatchRequest(body) {
+		handleV2Batch(writer, state, limiter, client, body)
+		return
+	}
+
 	j, err := primitives.ParseJSON2Request(string(body))
 	if err != nil {
 		HandleV2Error(writer, nil, NewInvalidRequestError())
 		return
 	}
 
-	jsonResp, jsonError := HandleV2JSONRequest(state, j)
+	jsonResp, jsonError := handleV2LimitedRequest(state, limiter, client, j)
 	if jsonError != nil {
 		HandleV2Error(writer, j, jsonError)
 		return
@@ -76,6 +97,94 @@ func HandleV2(writer http.ResponseWriter, request *http.Request) {
 	}
 }
 
+// isBatchRequest checks if the body is a JSON array, which JSON-RPC 2.0 defines as a batch call
+func isBatchRequest(body []byte) bool {
+	trimmed := bytes.TrimLeft(body, " \t\r\n")
+	return len(trimmed) > 0 && trimmed[0] == '['
+}
+
+// handleV2Batch executes every request of a batch call and writes back an array with a
+// response for each of them, in the same order. Errors are reported per request, and every
+// request of the batch counts against the rate limit of the client.
+func handleV2Batch(writer http.ResponseWriter, state interfaces.IState, limiter *RateLimiter, client string, body []byte) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil {
+		HandleV2Error(writer, nil, NewParseError())
+		return
+	}
+	if len(raw) == 0 {
+		HandleV2Error(writer, nil, NewInvalidRequestError())
+		return
+	}
+
+	limit, workers := state.GetApiBatchSettings()
+	if limit > 0 && len(raw) > limit {
+		HandleV2Error(writer, nil, NewBatchTooLargeError(limit))
+		return
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(raw) {
+		workers = len(raw)
+	}
+
+	responses := make([]*primitives.JSON2Response, len(raw))
+	indexes := make(chan int, len(raw))
+	for i := range raw {
+		indexes <- i
+	}
+	close(indexes)
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				responses[i] = handleV2BatchItem(state, limiter, client, raw[i])
+			}
+		}()
+	}
+	wg.Wait()
+
+	p, err := json.Marshal(responses)
+	if err != nil {
+		wsLog.Errorf("failed to marshal batch response: %v", err)
+		HandleV2Error(writer, nil, NewInternalError())
+		return
+	}
+	_, err = writer.Write(p)
+	if err != nil {
+		wsLog.Errorf("failed to write response: %v", err)
+	}
+}
+
+func handleV2BatchItem(state interfaces.IState, limiter *RateLimiter, client string, raw json.RawMessage) (resp *primitives.JSON2Response) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			wsLog.Errorf("Recovered from a panic in batch request: %v: %s", rec, string(debug.Stack()))
+			resp = primitives.NewJSON2Response()
+			resp.Error = NewInternalError()
+		}
+	}()
+
+	j, err := primitives.ParseJSON2Request(string(raw))
+	if err != nil {
+		resp = primitives.NewJSON2Response()
+		resp.Error = NewInvalidRequestError()
+		return resp
+	}
+
+	resp, jsonError := handleV2LimitedRequest(state, limiter, client, j)
+	if jsonError != nil {
+		resp = primitives.NewJSON2Response()
+		resp.ID = j.ID
+		resp.Error = jsonError
+	}
+	return resp
+}
+
 func HandleV2Request(_ http.ResponseWriter, request *http.Request, j *primitives.JSON2Request) (*primitives.JSON2Response, *primitives.JSONError) {
 	state, err := GetState(request)
 	if err != nil {
@@ -85,93 +194,109 @@ func HandleV2Request(_ http.ResponseWriter, request *http.Request, j *primitives
 	return HandleV2JSONRequest(state, j)
 }
 
+// HandleV2JSONRequest dispatches a single v2 request, and records the per method metrics
 func HandleV2JSONRequest(state interfaces.IState, j *primitives.JSON2Request) (*primitives.JSON2Response, *primitives.JSONError) {
+	HandleV2APIInFlight.Inc()
+	defer HandleV2APIInFlight.Dec()
+
+	n := time.Now()
+	resp, jsonError := handleV2JSONRequest(state, j)
+	ObserveV2Call(j.Method, time.Since(n), jsonError)
+	return resp, jsonError
+}
+
+// WriteMethods are the v2 methods that submit data to the network. They are limited by the
+// write budget, every other method by the read budget.
+var WriteMethods = map[string]bool{
+	"commit-chain":     true,
+	"commit-entry":     true,
+	"reveal-chain":     true,
+	"reveal-entry":     true,
+	"factoid-submit":   true,
+	"send-raw-message": true,
+}
+
+// AdminMethods are the v2 methods that act on the node instead of reading from it. Like the
+// write methods they are disabled on a read-only API.
+var AdminMethods = map[string]bool{
+	"replay-from-height": true,
+}
+
+// isReadOnlyDisabled checks if a read-only API rejects the method
+func isReadOnlyDisabled(state interfaces.IState, method string) bool {
+	return state.IsApiReadOnly() && (WriteMethods[method] || AdminMethods[method])
+}
+
+// v2Method is an entry of the v2 method registry. Params and Result are zero values of the
+// request and response types, used to describe the method in the rpc.discover document.
+type v2Method struct {
+	Handler func(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError)
+	Params  interface{} // nil if the method takes no parameters
+	Result  interface{}
+}
+
+// v2Methods maps the v2 method names to their handlers
+var v2Methods = map[string]v2Method{
+	"replay-from-height":    {HandleV2ReplayDBFromHeight, ReplayRequest{}, SendReplayMessageResponse{}},
+	"anchors":               {HandleV2Anchors, HeightOrHashRequest{}, AnchorsResponse{}},
+	"address-transactions":  {HandleV2AddressTransactions, AddressTransactionsRequest{}, AddressTransactionsResponse{}},
+	"balance-at-height":     {HandleV2BalanceAtHeight, BalanceAtHeightRequest{}, BalanceAtHeightResponse{}},
+	"chain-head":            {HandleV2ChainHead, ChainIDRequest{}, ChainHeadResponse{}},
+	"chain-entries":         {HandleV2ChainEntries, ChainEntriesRequest{}, ChainEntriesResponse{}},
+	"commit-chain":          {HandleV2CommitChain, MessageRequest{}, CommitChainResponse{}},
+	"commit-entry":          {HandleV2CommitEntry, MessageRequest{}, CommitEntryResponse{}},
+	"current-minute":        {HandleV2CurrentMinute, nil, CurrentMinuteResponse{}},
+	"entries-by-extid":      {HandleV2EntriesByExtID, EntriesByExtIDRequest{}, EntriesByExtIDResponse{}},
+	"directory-block":       {HandleV2DirectoryBlock, KeyMRRequest{}, DirectoryBlockResponse{}},
+	"directory-block-head":  {HandleV2DirectoryBlockHead, nil, DirectoryBlockHeadResponse{}},
+	"entry-block":           {HandleV2EntryBlock, KeyMRRequest{}, EntryBlockResponse{}},
+	"admin-block":           {HandleV2AdminBlock, KeyMRRequest{}, BlockHeightResponse{}},
+	"factoid-block":         {HandleV2FactoidBlock, KeyMRRequest{}, BlockHeightResponse{}},
+	"entrycredit-block":     {HandleV2EntryCreditBlock, KeyMRRequest{}, EntryCreditBlockResponse{}},
+	"entry":                 {HandleV2Entry, HashRequest{}, EntryResponse{}},
+	"entry-credit-balance":  {HandleV2EntryCreditBalance, AddressRequest{}, EntryCreditBalanceResponse{}},
+	"entry-credit-rate":     {HandleV2EntryCreditRate, nil, EntryCreditRateResponse{}},
+	"factoid-balance":       {HandleV2FactoidBalance, AddressRequest{}, FactoidBalanceResponse{}},
+	"factoid-submit":        {HandleV2FactoidSubmit, TransactionRequest{}, FactoidSubmitResponse{}},
+	"heights":               {HandleV2Heights, nil, HeightsResponse{}},
+	"properties":            {HandleV2Properties, nil, PropertiesResponse{}},
+	"raw-data":              {HandleV2RawData, HashRequest{}, RawDataResponse{}},
+	"receipt":               {HandleV2Receipt, ReceiptRequest{}, ReceiptResponse{}},
+	"reveal-chain":          {HandleV2RevealChain, EntryRequest{}, RevealEntryResponse{}},
+	"reveal-entry":          {HandleV2RevealEntry, EntryRequest{}, RevealEntryResponse{}},
+	"factoid-ack":           {HandleV2FactoidACK, AckRequest{}, FactoidTxStatus{}},
+	"entry-ack":             {HandleV2EntryACK, AckRequest{}, EntryStatus{}},
+	"pending-entries":       {HandleV2GetPendingEntries, ChainIDRequest{}, []interfaces.IPendingEntry{}},
+	"pending-transactions":  {HandleV2GetPendingTransactions, AddressRequest{}, []interfaces.IPendingTransaction{}},
+	"send-raw-message":      {HandleV2SendRawMessage, SendRawMessageRequest{}, SendRawMessageResponse{}},
+	"transaction":           {HandleV2GetTranasction, HashRequest{}, TransactionResponse{}},
+	"dblock-by-height":      {HandleV2DBlockByHeight, HeightRequest{}, BlockHeightResponse{}},
+	"ecblock-by-height":     {HandleV2ECBlockByHeight, HeightRequest{}, EntryCreditBlockResponse{}},
+	"fblock-by-height":      {HandleV2FBlockByHeight, HeightRequest{}, BlockHeightResponse{}},
+	"ablock-by-height":      {HandleV2ABlockByHeight, HeightRequest{}, BlockHeightResponse{}},
+	"authorities":           {HandleAuthorities, nil, AuthoritiesResponse{}},
+	"tps-rate":              {HandleV2TransactionRate, nil, TransactionRateResponse{}},
+	"ack":                   {HandleV2ACKWithChain, EntryAckWithChainRequest{}, oneOf{EntryStatus{}, FactoidTxStatus{}}},
+	"multiple-fct-balances": {HandleV2MultipleFCTBalances, MultipleAddressesRequest{}, MultipleFTBalances{}},
+	"multiple-ec-balances":  {HandleV2MultipleECBalances, MultipleAddressesRequest{}, MultipleFTBalances{}},
+	"diagnostics":           {HandleV2Diagnostics, nil, DiagnosticsResponse{}},
+	//"factoid-accounts": {HandleV2Accounts, nil, nil},
+}
+
+func init() {
+	// registered here as the discover handler reads the registry itself
+	v2Methods["rpc.discover"] = v2Method{HandleV2Discover, nil, OpenRPCDocument{}}
+}
+
+func handleV2JSONRequest(state interfaces.IState, j *primitives.JSON2Request) (*primitives.JSON2Response, *primitives.JSONError) {
 	var resp interface{}
 	var jsonError *primitives.JSONError
-	params := j.Params
 	wsLog.Infof("request %v", j.String())
-	switch j.Method {
-	case "replay-from-height":
-		resp, jsonError = HandleV2ReplayDBFromHeight(state, params)
-	case "anchors":
-		resp, jsonError = HandleV2Anchors(state, params)
-	case "chain-head":
-		resp, jsonError = HandleV2ChainHead(state, params)
-	case "commit-chain":
-		resp, jsonError = HandleV2CommitChain(state, params)
-	case "commit-entry":
-		resp, jsonError = HandleV2CommitEntry(state, params)
-	case "current-minute":
-		resp, jsonError = HandleV2CurrentMinute(state, params)
-	case "directory-block":
-		resp, jsonError = HandleV2DirectoryBlock(state, params)
-	case "directory-block-head":
-		resp, jsonError = HandleV2DirectoryBlockHead(state, params)
-	case "entry-block":
-		resp, jsonError = HandleV2EntryBlock(state, params)
-	case "admin-block":
-		resp, jsonError = HandleV2AdminBlock(state, params)
-	case "factoid-block":
-		resp, jsonError = HandleV2FactoidBlock(state, params)
-	case "entrycredit-block":
-		resp, jsonError = HandleV2EntryCreditBlock(state, params)
-	case "entry":
-		resp, jsonError = HandleV2Entry(state, params)
-	case "entry-credit-balance":
-		resp, jsonError = HandleV2EntryCreditBalance(state, params)
-	case "entry-credit-rate":
-		resp, jsonError = HandleV2EntryCreditRate(state, params)
-	case "factoid-balance":
-		resp, jsonError = HandleV2FactoidBalance(state, params)
-	case "factoid-submit":
-		resp, jsonError = HandleV2FactoidSubmit(state, params)
-	case "heights":
-		resp, jsonError = HandleV2Heights(state, params)
-	case "properties":
-		resp, jsonError = HandleV2Properties(state, params)
-	case "raw-data":
-		resp, jsonError = HandleV2RawData(state, params)
-	case "receipt":
-		resp, jsonError = HandleV2Receipt(state, params)
-	case "reveal-chain":
-		resp, jsonError = HandleV2RevealChain(state, params)
-	case "reveal-entry":
-		resp, jsonError = HandleV2RevealEntry(state, params)
-	case "factoid-ack":
-		resp, jsonError = HandleV2FactoidACK(state, params)
-	case "entry-ack":
-		resp, jsonError = HandleV2EntryACK(state, params)
-	case "pending-entries":
-		resp, jsonError = HandleV2GetPendingEntries(state, params)
-	case "pending-transactions":
-		resp, jsonError = HandleV2GetPendingTransactions(state, params)
-	case "send-raw-message":
-		resp, jsonError = HandleV2SendRawMessage(state, params)
-	case "transaction":
-		resp, jsonError = HandleV2GetTranasction(state, params)
-	case "dblock-by-height":
-		resp, jsonError = HandleV2DBlockByHeight(state, params)
-	case "ecblock-by-height":
-		resp, jsonError = HandleV2ECBlockByHeight(state, params)
-	case "fblock-by-height":
-		resp, jsonError = HandleV2FBlockByHeight(state, params)
-	case "ablock-by-height":
-		resp, jsonError = HandleV2ABlockByHeight(state, params)
-	case "authorities":
-		resp, jsonError = HandleAuthorities(state, params)
-	case "tps-rate":
-		resp, jsonError = HandleV2TransactionRate(state, params)
-	case "ack":
-		resp, jsonError = HandleV2ACKWithChain(state, params)
-	case "multiple-fct-balances":
-		resp, jsonError = HandleV2MultipleFCTBalances(state, params)
-	case "multiple-ec-balances":
-		resp, jsonError = HandleV2MultipleECBalances(state, params)
-	case "diagnostics":
-		resp, jsonError = HandleV2Diagnostics(state, params)
-		//case "factoid-accounts":
-		// resp, jsonError = HandleV2Accounts(state, params)
-	default:
+	if isReadOnlyDisabled(state, j.Method) {
+		jsonError = NewReadOnlyError(j.Method)
+	} else if method, ok := v2Methods[j.Method]; ok {
+		resp, jsonError = method.Handler(state, j.Params)
+	} else {
 		jsonError = NewMethodNotFoundError()
 	}
 	if jsonError != nil {
@@ -756,6 +881,8 @@ func HandleV2RawData(state interfaces.IState, params interface{}) (interface{},
 			b, _ = block.MarshalBinary()
 		} else if block, _ = dbase.FetchEntry(h); block != nil {
 			b, _ = block.MarshalBinary()
+		} else if pruned, _ := dbase.IsEntryPruned(h); pruned {
+			return nil, NewEntryPrunedError()
 		} else {
 			return nil, NewObjectNotFoundError()
 		}
@@ -948,6 +1075,10 @@ func HandleV2DirectoryBlock(state interfaces.IState, params interface{}) (interf
 		return nil, NewBlockNotFoundError()
 	}
 
+	return DBlockToResp(block), nil
+}
+
+func DBlockToResp(block interfaces.IDirectoryBlock) *DirectoryBlockResponse {
 	d := new(DirectoryBlockResponse)
 	d.Header.PrevBlockKeyMR = block.GetHeader().GetPrevKeyMR().String()
 
@@ -959,8 +1090,7 @@ func HandleV2DirectoryBlock(state interfaces.IState, params interface{}) (interf
 		l.KeyMR = v.GetKeyMR().String()
 		d.EntryBlockList = append(d.EntryBlockList, *l)
 	}
-
-	return d, nil
+	return d
 }
 
 func HandleV2EntryBlock(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
@@ -1062,6 +1192,9 @@ func HandleV2Entry(state interfaces.IState, params interface{}) (interface{}, *p
 			return nil, NewInvalidHashError()
 		}
 		if entry == nil {
+			if pruned, _ := dbase.IsEntryPruned(h); pruned {
+				return nil, NewEntryPrunedError()
+			}
 			return nil, NewEntryNotFoundError()
 		}
 
@@ -1131,6 +1264,341 @@ func HandleV2ChainHead(state interfaces.IState, params interface{}) (interface{}
 	return c, nil
 }
 
+// HandleV2AddressTransactions returns a page of the transactions touching a factoid or entry
+// credit address, oldest first. Factoid transactions are identified by their transaction id,
+// entry credit commits by their entry hash. Only available if the address index is enabled.
+func HandleV2AddressTransactions(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	if !state.IsAddressIndexEnabled() {
+		return nil, NewAddressIndexDisabledError()
+	}
+
+	req := new(AddressTransactionsRequest)
+	err := MapToObject(params, req)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+	if req.Offset < 0 {
+		return nil, NewCustomInvalidParamsError("Offset must not be negative")
+	}
+
+	limit := req.Limit
+	if limit <= 0 || limit > MaxAddressTransactionsLimit {
+		limit = MaxAddressTransactionsLimit
+	}
+
+	dbase := state.GetDB()
+	var txs []interfaces.AddressTransaction
+	var total int
+	switch {
+	case primitives.ValidateFUserStr(req.Address):
+		txs, total, err = dbase.FetchFactoidAddressTransactionsPage(primitives.NewHash(primitives.ConvertUserStrToAddress(req.Address)), req.Offset, limit)
+	case primitives.ValidateECUserStr(req.Address):
+		txs, total, err = dbase.FetchECAddressTransactionsPage(primitives.NewHash(primitives.ConvertUserStrToAddress(req.Address)), req.Offset, limit)
+	default:
+		return nil, NewInvalidAddressError()
+	}
+	if err != nil {
+		return nil, NewInternalDatabaseError()
+	}
+
+	indexHeight, err := dbase.FetchAddressIndexHeight()
+	if err != nil {
+		return nil, NewInternalDatabaseError()
+	}
+
+	resp := new(AddressTransactionsResponse)
+	resp.Total = total
+	// the stored height is the next one to index
+	resp.IndexHeight = int64(indexHeight) - 1
+	resp.Transactions = []AddressTransaction{}
+	for _, tx := range txs {
+		resp.Transactions = append(resp.Transactions, AddressTransaction{TxID: tx.TxID.String(), Height: int64(tx.Height)})
+	}
+	return resp, nil
+}
+
+// HandleV2EntriesByExtID returns a page of the hashes of the entries of a chain that have the
+// given external ID, hex encoded, among their ExtIDs. The entries are ordered by hash, a page
+// starts at the cursor returned with the previous one. Only available if the ExtID index is
+// enabled.
+func HandleV2EntriesByExtID(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	if !state.IsExtIDIndexEnabled() {
+		return nil, NewExtIDIndexDisabledError()
+	}
+
+	req := new(EntriesByExtIDRequest)
+	err := MapToObject(params, req)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+	chainID, err := primitives.HexToHash(req.ChainID)
+	if err != nil {
+		return nil, NewInvalidHashError()
+	}
+	extID, err := hex.DecodeString(req.ExtID)
+	if err != nil {
+		return nil, NewCustomInvalidParamsError("ExtID must be hex encoded")
+	}
+	var cursor interfaces.IHash
+	if req.Cursor != "" {
+		cursor, err = primitives.HexToHash(req.Cursor)
+		if err != nil {
+			return nil, NewCustomInvalidParamsError("Invalid cursor")
+		}
+	}
+
+	limit := req.Limit
+	if limit <= 0 || limit > MaxEntriesByExtIDLimit {
+		limit = MaxEntriesByExtIDLimit
+	}
+
+	dbase := state.GetDB()
+	hashes, next, err := dbase.FetchEntryHashesByExtID(chainID, extID, cursor, limit)
+	if err != nil {
+		return nil, NewInternalDatabaseError()
+	}
+	indexHeight, err := dbase.FetchExtIDIndexHeight()
+	if err != nil {
+		return nil, NewInternalDatabaseError()
+	}
+
+	resp := new(EntriesByExtIDResponse)
+	// the stored height is the next one to backfill, newer entries are indexed as they are saved
+	resp.IndexHeight = int64(indexHeight) - 1
+	resp.EntryHashes = []string{}
+	for _, hash := range hashes {
+		resp.EntryHashes = append(resp.EntryHashes, hash.String())
+	}
+	if next != nil {
+		resp.NextCursor = next.String()
+	}
+	return resp, nil
+}
+
+// HandleV2BalanceAtHeight returns the balance of a factoid or entry credit address as of the
+// directory block at the given height. With the address index enabled the blocks touching the
+// address are replayed, otherwise the blocks after the fastboot save state, which has to be at
+// or below the height.
+func HandleV2BalanceAtHeight(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	req := new(BalanceAtHeightRequest)
+	err := MapToObject(params, req)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+	if req.Height < 0 || req.Height > int64(state.GetHighestSavedBlk()) {
+		return nil, NewInvalidHeightError()
+	}
+
+	var adr [32]byte
+	resp := new(BalanceAtHeightResponse)
+	resp.Height = req.Height
+	switch {
+	case primitives.ValidateFUserStr(req.Address):
+		copy(adr[:], primitives.ConvertUserStrToAddress(req.Address))
+		resp.Balance, err = state.GetFactoidBalanceAtHeight(adr, uint32(req.Height))
+	case primitives.ValidateECUserStr(req.Address):
+		copy(adr[:], primitives.ConvertUserStrToAddress(req.Address))
+		resp.Balance, err = state.GetECBalanceAtHeight(adr, uint32(req.Height))
+	default:
+		return nil, NewInvalidAddressError()
+	}
+	if _, ok := err.(*interfaces.BalanceHistoryError); ok {
+		return nil, NewNoBalanceHistoryError(err.Error())
+	}
+	if err != nil {
+		return nil, NewInternalDatabaseError()
+	}
+	return resp, nil
+}
+
+// HandleV2ChainEntries returns a page of the entries of a chain, walking the entry blocks in
+// forward or reverse order. The returned cursor points to the next entry to read, and is omitted
+// once the end of the chain (or of the requested height range) has been reached.
+func HandleV2ChainEntries(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
+	req := new(ChainEntriesRequest)
+	err := MapToObject(params, req)
+	if err != nil {
+		return nil, NewInvalidParamsError()
+	}
+	chainID, err := primitives.HexToHash(req.ChainID)
+	if err != nil {
+		return nil, NewInvalidHashError()
+	}
+
+	limit := req.Limit
+	if limit <= 0 || limit > MaxChainEntriesLimit {
+		limit = MaxChainEntriesLimit
+	}
+
+	dbase := state.GetDB()
+	head, err := dbase.FetchEBlockHead(chainID)
+	if err != nil {
+		return nil, NewInternalDatabaseError()
+	}
+	if head == nil {
+		return nil, NewMissingChainHeadError()
+	}
+
+	inRange := func(b interfaces.IEntryBlock) bool {
+		height := int64(b.GetDatabaseHeight())
+		return (req.StartHeight == nil || height >= *req.StartHeight) && (req.EndHeight == nil || height <= *req.EndHeight)
+	}
+	// fetch reads the entry block at or past the height in the walking direction, nil if there
+	// is none or if it is outside the requested height range
+	fetch := func(height int64) (interfaces.IEntryBlock, *primitives.JSONError) {
+		if height < 0 || height > math.MaxUint32 {
+			return nil, nil
+		}
+		keyMR, err := dbase.FetchEBlockKeyMRFromHeight(chainID, uint32(height), req.Reverse)
+		if err != nil {
+			return nil, NewInternalDatabaseError()
+		}
+		if keyMR == nil {
+			return nil, nil
+		}
+		b, err := dbase.FetchEBlock(keyMR)
+		if err != nil {
+			return nil, NewInternalDatabaseError()
+		}
+		if b == nil || !inRange(b) {
+			return nil, nil
+		}
+		return b, nil
+	}
+	// next reads the entry block after the given one in the walking direction. In reverse that
+	// is the previous block of the chain
+	next := func(b interfaces.IEntryBlock) (interfaces.IEntryBlock, *primitives.JSONError) {
+		if !req.Reverse {
+			return fetch(int64(b.GetDatabaseHeight()) + 1)
+		}
+		prev := b.GetHeader().GetPrevKeyMR()
+		if prev == nil || prev.IsZero() {
+			return nil, nil
+		}
+		p, err := dbase.FetchEBlock(prev)
+		if err != nil {
+			return nil, NewInternalDatabaseError()
+		}
+		if p == nil || !inRange(p) {
+			return nil, nil
+		}
+		return p, nil
+	}
+
+	// find the starting position
+	var block interfaces.IEntryBlock
+	var jErr *primitives.JSONError
+	ei := 0
+	if req.Cursor != nil {
+		cursor, err := primitives.HexToHash(req.Cursor.KeyMR)
+		if err != nil {
+			return nil, NewCustomInvalidParamsError("Invalid cursor")
+		}
+		block, err = dbase.FetchEBlock(cursor)
+		if err != nil {
+			return nil, NewInternalDatabaseError()
+		}
+		if block == nil || !block.GetChainID().IsSameAs(chainID) || !inRange(block) ||
+			req.Cursor.Index < 0 || req.Cursor.Index >= len(block.GetEntryHashes()) {
+			return nil, NewCustomInvalidParamsError("Invalid cursor")
+		}
+		ei = req.Cursor.Index
+	} else {
+		first := int64(0)
+		if req.StartHeight != nil {
+			first = *req.StartHeight
+		}
+		if req.Reverse {
+			first = math.MaxUint32
+			if req.EndHeight != nil {
+				first = *req.EndHeight
+			}
+		}
+		block, jErr = fetch(first)
+		if jErr != nil {
+			return nil, jErr
+		}
+		if block != nil && req.Reverse {
+			ei = len(block.GetEntryHashes()) - 1
+		}
+	}
+
+	resp := new(ChainEntriesResponse)
+	resp.Entries = []ChainEntry{}
+	if block == nil {
+		return resp, nil
+	}
+	keyMR, err := block.KeyMR()
+	if err != nil {
+		return nil, NewInternalError()
+	}
+
+	step := 1
+	if req.Reverse {
+		step = -1
+	}
+	for block != nil {
+		hashes := block.GetEntryHashes()
+		if ei < 0 || ei >= len(hashes) {
+			// move on to the next block in the walking direction
+			block, jErr = next(block)
+			if jErr != nil {
+				return nil, jErr
+			}
+			if block != nil {
+				keyMR, err = block.KeyMR()
+				if err != nil {
+					return nil, NewInternalError()
+				}
+				ei = 0
+				if req.Reverse {
+					ei = len(block.GetEntryHashes()) - 1
+				}
+			}
+			continue
+		}
+
+		// minute markers are skipped first, so the cursor is only set if an entry is left
+		h := hashes[ei]
+		if h.IsMinuteMarker() {
+			ei += step
+			continue
+		}
+		if len(resp.Entries) == limit {
+			resp.NextCursor = &ChainCursor{KeyMR: keyMR.String(), Index: ei}
+			break
+		}
+		ei += step
+
+		entry, err := dbase.FetchEntry(h)
+		if err != nil {
+			return nil, NewInternalDatabaseError()
+		}
+
+		e := ChainEntry{}
+		e.EntryHash = h.String()
+		e.EntryBlockKeyMR = keyMR.String()
+		e.DBHeight = int64(block.GetDatabaseHeight())
+		if entry == nil {
+			// the chain stays readable on a pruned node, without the content of the pruned entries
+			pruned, _ := dbase.IsEntryPruned(h)
+			if !pruned {
+				return nil, NewEntryNotFoundError()
+			}
+			e.Pruned = true
+			resp.Entries = append(resp.Entries, e)
+			continue
+		}
+		e.Content = hex.EncodeToString(entry.GetContent())
+		for _, v := range entry.ExternalIDs() {
+			e.ExtIDs = append(e.ExtIDs, hex.EncodeToString(v))
+		}
+		resp.Entries = append(resp.Entries, e)
+	}
+
+	return resp, nil
+}
+
 func HandleV2CurrentMinute(state interfaces.IState, params interface{}) (interface{}, *primitives.JSONError) {
 	n := time.Now()
 	defer HandleV2APICallHeights.Observe(float64(time.Since(n).Nanoseconds()))
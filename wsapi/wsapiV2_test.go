@@ -2,20 +2,28 @@ package wsapi_test
 
 import (
 	"bytes"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net/http"
+	"os"
 	"reflect"
 	"strings"
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 
 	"time"
 
+	"github.com/FactomProject/factomd/common/entryBlock"
+	"github.com/FactomProject/factomd/common/entryCreditBlock"
+	"github.com/FactomProject/factomd/common/factoid"
 	"github.com/FactomProject/factomd/common/interfaces"
 	"github.com/FactomProject/factomd/common/primitives"
 	"github.com/FactomProject/factomd/receipts"
+	state2 "github.com/FactomProject/factomd/state"
 	"github.com/FactomProject/factomd/testHelper"
 	. "github.com/FactomProject/factomd/wsapi"
 )
@@ -243,6 +251,504 @@ func TestHandleV2Requests(t *testing.T) {
 	}
 }
 
+func TestHandleV2Batch(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestState()
+	delayedStart(t, state)
+
+	batch := []interface{}{
+		primitives.NewJSON2Request("properties", 1, nil),
+		primitives.NewJSON2Request("no-such-method", 2, nil),
+		"not a request",
+		primitives.NewJSON2Request("heights", 4, nil),
+	}
+	j, err := json.Marshal(batch)
+	assert.Nil(t, err)
+
+	resp, err := http.Post(fmt.Sprintf("http://localhost:%d/v2", state.GetPort()), "application/json", bytes.NewBuffer(j))
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var responses []*primitives.JSON2Response
+	d := json.NewDecoder(resp.Body)
+	d.UseNumber()
+	assert.Nil(t, d.Decode(&responses))
+	assert.Equal(t, len(batch), len(responses))
+
+	assert.Equal(t, number("1"), responses[0].ID)
+	assert.Nil(t, responses[0].Error)
+	assert.Equal(t, number("2"), responses[1].ID)
+	assert.Equal(t, NewMethodNotFoundError(), responses[1].Error)
+	assert.Nil(t, responses[2].ID)
+	assert.Equal(t, NewInvalidRequestError(), responses[2].Error)
+	assert.Equal(t, number("4"), responses[3].ID)
+	assert.Nil(t, responses[3].Error)
+
+	// an empty batch is a single invalid request
+	resp, err = http.Post(fmt.Sprintf("http://localhost:%d/v2", state.GetPort()), "application/json", strings.NewReader("[]"))
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	// batches over the limit are rejected as a whole
+	limit, _ := state.GetApiBatchSettings()
+	tooLarge := make([]*primitives.JSON2Request, limit+1)
+	for i := range tooLarge {
+		tooLarge[i] = primitives.NewJSON2Request("properties", i, nil)
+	}
+	j, err = json.Marshal(tooLarge)
+	assert.Nil(t, err)
+	resp, err = http.Post(fmt.Sprintf("http://localhost:%d/v2", state.GetPort()), "application/json", bytes.NewBuffer(j))
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	r := primitives.NewJSON2Response()
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(r))
+	assert.Equal(t, NewBatchTooLargeError(limit), r.Error)
+}
+
+func TestHandleV2ChainEntries(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestState()
+	chainID := "6e7e64ac45ff57edbf8537a0c99fba2e9ee351ef3d3f4abd93af9f01107e592c"
+
+	walkRange := func(reverse bool, limit int, start *int64, end *int64) []ChainEntry {
+		var entries []ChainEntry
+		req := &ChainEntriesRequest{ChainID: chainID, Reverse: reverse, Limit: limit, StartHeight: start, EndHeight: end}
+		for {
+			r, jErr := HandleV2ChainEntries(state, req)
+			assert.Nil(t, jErr)
+			resp := r.(*ChainEntriesResponse)
+			assert.True(t, len(resp.Entries) <= limit)
+			// a cursor is only returned if there are entries past it, minute markers aside
+			assert.NotEmpty(t, resp.Entries)
+			entries = append(entries, resp.Entries...)
+			if resp.NextCursor == nil {
+				return entries
+			}
+			req.Cursor = resp.NextCursor
+		}
+	}
+	walk := func(reverse bool, limit int) []string {
+		var hashes []string
+		for _, e := range walkRange(reverse, limit, nil, nil) {
+			hashes = append(hashes, e.EntryHash)
+		}
+		return hashes
+	}
+
+	all := walk(false, MaxChainEntriesLimit)
+	assert.NotEmpty(t, all)
+	assert.Equal(t, all, walk(false, 1))
+
+	reversed := walk(true, 1)
+	assert.Equal(t, len(all), len(reversed))
+	for i := range all {
+		assert.Equal(t, all[i], reversed[len(reversed)-1-i])
+	}
+
+	// a height range returns the entries of the blocks in it, in both directions
+	entries := walkRange(false, MaxChainEntriesLimit, nil, nil)
+	start, end := int64(3), int64(5)
+	var inRange []ChainEntry
+	for _, e := range entries {
+		if e.DBHeight >= start && e.DBHeight <= end {
+			inRange = append(inRange, e)
+		}
+	}
+	assert.NotEmpty(t, inRange)
+	assert.Equal(t, inRange, walkRange(false, 1, &start, &end))
+	reversedRange := walkRange(true, 2, &start, &end)
+	assert.Equal(t, len(inRange), len(reversedRange))
+	for i := range inRange {
+		assert.Equal(t, inRange[i], reversedRange[len(reversedRange)-1-i])
+	}
+
+	// a height range past the chain returns no entries
+	height := int64(1000000)
+	r, jErr := HandleV2ChainEntries(state, &ChainEntriesRequest{ChainID: chainID, StartHeight: &height})
+	assert.Nil(t, jErr)
+	assert.Empty(t, r.(*ChainEntriesResponse).Entries)
+
+	_, jErr = HandleV2ChainEntries(state, &ChainEntriesRequest{ChainID: chainID, Cursor: &ChainCursor{KeyMR: chainID}})
+	assert.NotNil(t, jErr)
+	// a cursor has to point into the chain and the height range
+	anchorHead, err := state.DB.FetchEBlockHead(testHelper.GetAnchorChainID())
+	assert.Nil(t, err)
+	anchorKeyMR, _ := anchorHead.KeyMR()
+	_, jErr = HandleV2ChainEntries(state, &ChainEntriesRequest{ChainID: chainID, Cursor: &ChainCursor{KeyMR: anchorKeyMR.String()}})
+	assert.NotNil(t, jErr)
+	_, jErr = HandleV2ChainEntries(state, &ChainEntriesRequest{ChainID: chainID, Cursor: &ChainCursor{KeyMR: inRange[0].EntryBlockKeyMR}, EndHeight: &height, StartHeight: &height})
+	assert.NotNil(t, jErr)
+
+	_, jErr = HandleV2ChainEntries(state, &ChainEntriesRequest{ChainID: "0000000000000000000000000000000000000000000000000000000000000123"})
+	assert.Equal(t, NewMissingChainHeadError(), jErr)
+}
+
+func TestHandleV2AddressTransactions(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestState()
+
+	_, jErr := HandleV2AddressTransactions(state, &AddressTransactionsRequest{Address: "FA2jK2HcLnRdS94dEcU27rF3meoJfpUcZPSinpb7AwQvPRY6RL1Q"})
+	assert.Equal(t, NewAddressIndexDisabledError(), jErr)
+
+	state.AddressIndex = true
+	var address string
+	for h := uint32(0); h <= state.GetHighestSavedBlk(); h++ {
+		fblock, err := state.DB.FetchFBlockByHeight(h)
+		assert.Nil(t, err)
+		ecblock, err := state.DB.FetchECBlockByHeight(h)
+		assert.Nil(t, err)
+		assert.Nil(t, state.DB.SaveAddressTransactions(h, fblock, ecblock))
+		for _, tx := range fblock.GetTransactions() {
+			for _, out := range tx.GetOutputs() {
+				address = primitives.ConvertFctAddressToUserStr(out.GetAddress())
+			}
+		}
+	}
+	assert.NotEmpty(t, address)
+
+	r, jErr := HandleV2AddressTransactions(state, &AddressTransactionsRequest{Address: address})
+	assert.Nil(t, jErr)
+	all := r.(*AddressTransactionsResponse)
+	assert.NotEmpty(t, all.Transactions)
+	assert.Equal(t, len(all.Transactions), all.Total)
+	assert.Equal(t, int64(state.GetHighestSavedBlk()), all.IndexHeight)
+
+	// paging one at a time returns the same transactions
+	for i, tx := range all.Transactions {
+		r, jErr := HandleV2AddressTransactions(state, &AddressTransactionsRequest{Address: address, Offset: i, Limit: 1})
+		assert.Nil(t, jErr)
+		page := r.(*AddressTransactionsResponse)
+		assert.Equal(t, []AddressTransaction{tx}, page.Transactions)
+		assert.Equal(t, all.Total, page.Total)
+	}
+
+	_, jErr = HandleV2AddressTransactions(state, &AddressTransactionsRequest{Address: "nope"})
+	assert.Equal(t, NewInvalidAddressError(), jErr)
+}
+
+func TestHandleV2BalanceAtHeight(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestState()
+	d := state.DBStates.Get(int(state.GetLLeaderHeight()))
+	d.Saved = true
+	d.Locked = true
+	top := int64(state.GetHighestSavedBlk())
+	if !assert.True(t, top > 0) {
+		return
+	}
+
+	fblock, err := state.DB.FetchFBlockByHeight(uint32(top))
+	assert.Nil(t, err)
+	out := fblock.GetTransactions()[0].GetOutputs()[0].GetAddress()
+	address := primitives.ConvertFctAddressToUserStr(out)
+
+	// without the address index or a fastboot file there is nothing to replay from
+	_, jErr := HandleV2BalanceAtHeight(state, &BalanceAtHeightRequest{Address: address, Height: top})
+	missing := &interfaces.BalanceHistoryError{Height: uint32(top)}
+	assert.Equal(t, NewNoBalanceHistoryError(missing.Error()), jErr)
+
+	// the fastboot save state covers its own height, not the ones below
+	d.SaveStruct = state2.SaveFactomdState(state, d)
+	if !assert.NotNil(t, d.SaveStruct) {
+		return
+	}
+	list := &state2.DBStateList{State: state, DBStates: []*state2.DBState{d}}
+	b, err := list.MarshalBinary()
+	assert.Nil(t, err)
+	dir, err := ioutil.TempDir("", "fastboot")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	state.StateSaverStruct.FastBoot = true
+	state.StateSaverStruct.FastBootLocation = dir
+	filename := state2.NetworkIDToFilename(state.Network, dir)
+	assert.Nil(t, ioutil.WriteFile(filename, append(primitives.Sha(b).Bytes(), b...), 0644))
+
+	balance := d.SaveStruct.FactoidBalancesP[out.Fixed()]
+	r, jErr := HandleV2BalanceAtHeight(state, &BalanceAtHeightRequest{Address: address, Height: top})
+	assert.Nil(t, jErr)
+	assert.Equal(t, &BalanceAtHeightResponse{Balance: balance, Height: top}, r)
+	_, jErr = HandleV2BalanceAtHeight(state, &BalanceAtHeightRequest{Address: address, Height: top - 1})
+	missing = &interfaces.BalanceHistoryError{Height: uint32(top - 1), SaveState: true, SaveStateHeight: uint32(top)}
+	assert.Equal(t, NewNoBalanceHistoryError(missing.Error()), jErr)
+	assert.Contains(t, jErr.Error(), fmt.Sprintf("covers the heights from %d", top))
+
+	// while the address index is backfilling it only covers the heights it has indexed
+	state.AddressIndex = true
+	_, jErr = HandleV2BalanceAtHeight(state, &BalanceAtHeightRequest{Address: address, Height: top - 1})
+	missing.AddressIndex = true
+	assert.Equal(t, NewNoBalanceHistoryError(missing.Error()), jErr)
+
+	// the address index covers every height, the balances are summed up from the blocks here
+	state.AddressIndex = true
+	var ecAddress [32]byte
+	for h := uint32(0); h <= uint32(top); h++ {
+		fblock, err := state.DB.FetchFBlockByHeight(h)
+		assert.Nil(t, err)
+		ecblock, err := state.DB.FetchECBlockByHeight(h)
+		assert.Nil(t, err)
+		assert.Nil(t, state.DB.SaveAddressTransactions(h, fblock, ecblock))
+		for _, entry := range ecblock.GetEntries() {
+			if commit, ok := entry.(*entryCreditBlock.CommitEntry); ok {
+				ecAddress = commit.ECPubKey.Fixed()
+			}
+		}
+	}
+
+	var fctBalance, ecBalance int64
+	for h := int64(0); h <= top; h++ {
+		fblock, _ := state.DB.FetchFBlockByHeight(uint32(h))
+		ecblock, _ := state.DB.FetchECBlockByHeight(uint32(h))
+		for _, tx := range fblock.GetTransactions() {
+			for _, in := range tx.GetInputs() {
+				if in.GetAddress().IsSameAs(out) {
+					fctBalance -= int64(in.GetAmount())
+				}
+			}
+			for _, o := range tx.GetOutputs() {
+				if o.GetAddress().IsSameAs(out) {
+					fctBalance += int64(o.GetAmount())
+				}
+			}
+			for _, o := range tx.GetECOutputs() {
+				if o.GetAddress().Fixed() == ecAddress {
+					ecBalance += int64(o.GetAmount() / fblock.GetExchRate())
+				}
+			}
+		}
+		for _, entry := range ecblock.GetEntries() {
+			switch commit := entry.(type) {
+			case *entryCreditBlock.CommitEntry:
+				if commit.ECPubKey.Fixed() == ecAddress {
+					ecBalance -= int64(commit.Credits)
+				}
+			case *entryCreditBlock.CommitChain:
+				if commit.ECPubKey.Fixed() == ecAddress {
+					ecBalance -= int64(commit.Credits)
+				}
+			}
+		}
+
+		r, jErr = HandleV2BalanceAtHeight(state, &BalanceAtHeightRequest{Address: address, Height: h})
+		assert.Nil(t, jErr)
+		assert.Equal(t, &BalanceAtHeightResponse{Balance: fctBalance, Height: h}, r)
+
+		ecUserAddress := primitives.ConvertECAddressToUserStr(factoid.NewAddress(ecAddress[:]))
+		r, jErr = HandleV2BalanceAtHeight(state, &BalanceAtHeightRequest{Address: ecUserAddress, Height: h})
+		assert.Nil(t, jErr)
+		assert.Equal(t, &BalanceAtHeightResponse{Balance: ecBalance, Height: h}, r)
+	}
+	assert.NotEqual(t, int64(0), ecBalance)
+
+	_, jErr = HandleV2BalanceAtHeight(state, &BalanceAtHeightRequest{Address: address, Height: top + 1})
+	assert.Equal(t, NewInvalidHeightError(), jErr)
+
+	_, jErr = HandleV2BalanceAtHeight(state, &BalanceAtHeightRequest{Address: "nope", Height: top})
+	assert.Equal(t, NewInvalidAddressError(), jErr)
+}
+
+func TestHandleV2EntriesByExtID(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestState()
+	chainID := testHelper.GetChainID()
+
+	_, jErr := HandleV2EntriesByExtID(state, &EntriesByExtIDRequest{ChainID: chainID.String()})
+	assert.Equal(t, NewExtIDIndexDisabledError(), jErr)
+
+	// backfill the index of the whole test database
+	head, err := state.DB.FetchDBlockHead()
+	assert.Nil(t, err)
+	height := head.GetDatabaseHeight()
+	state.ExtIDIndex = true
+	state.EntryBlockDBHeightComplete = height
+	state2.ExtIDIndexInterval = 10 * time.Millisecond
+	go state.IndexEntryExtIDs()
+	for i := 0; i < 500; i++ {
+		next, err := state.DB.FetchExtIDIndexHeight()
+		assert.Nil(t, err)
+		if next > height {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	entries, err := state.DB.FetchAllEntriesByChainID(chainID)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, entries)
+	for _, entry := range entries {
+		for _, extID := range entry.ExternalIDs() {
+			r, jErr := HandleV2EntriesByExtID(state, &EntriesByExtIDRequest{ChainID: chainID.String(), ExtID: hex.EncodeToString(extID)})
+			assert.Nil(t, jErr)
+			resp := r.(*EntriesByExtIDResponse)
+			assert.Contains(t, resp.EntryHashes, entry.GetHash().String())
+			assert.Empty(t, resp.NextCursor)
+			assert.Equal(t, int64(height), resp.IndexHeight)
+		}
+	}
+
+	// paging through entries sharing an ExtID
+	shared := []byte("shared")
+	for i := 0; i < 3; i++ {
+		entry := entryBlock.NewEntry()
+		entry.ChainID = chainID
+		entry.ExtIDs = []primitives.ByteSlice{{Bytes: shared}, {Bytes: []byte{byte(i)}}}
+		assert.Nil(t, state.DB.SaveEntryExtIDs(entry))
+	}
+	r, jErr := HandleV2EntriesByExtID(state, &EntriesByExtIDRequest{ChainID: chainID.String(), ExtID: hex.EncodeToString(shared)})
+	assert.Nil(t, jErr)
+	all := r.(*EntriesByExtIDResponse)
+	assert.Len(t, all.EntryHashes, 3)
+	cursor := ""
+	for i, hash := range all.EntryHashes {
+		r, jErr := HandleV2EntriesByExtID(state, &EntriesByExtIDRequest{ChainID: chainID.String(), ExtID: hex.EncodeToString(shared), Cursor: cursor, Limit: 1})
+		assert.Nil(t, jErr)
+		page := r.(*EntriesByExtIDResponse)
+		assert.Equal(t, []string{hash}, page.EntryHashes)
+		if i < len(all.EntryHashes)-1 {
+			assert.Equal(t, all.EntryHashes[i+1], page.NextCursor)
+		} else {
+			assert.Empty(t, page.NextCursor)
+		}
+		cursor = page.NextCursor
+	}
+
+	_, jErr = HandleV2EntriesByExtID(state, &EntriesByExtIDRequest{ChainID: "nope"})
+	assert.Equal(t, NewInvalidHashError(), jErr)
+	_, jErr = HandleV2EntriesByExtID(state, &EntriesByExtIDRequest{ChainID: chainID.String(), ExtID: "xyz"})
+	assert.NotNil(t, jErr)
+	_, jErr = HandleV2EntriesByExtID(state, &EntriesByExtIDRequest{ChainID: chainID.String(), ExtID: hex.EncodeToString(shared), Cursor: "xyz"})
+	assert.NotNil(t, jErr)
+}
+
+func TestHandleV2EntryPruned(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestState()
+	chainID := testHelper.GetChainID()
+
+	// the entries of the test chain in the directory block at the height
+	entryHashes := func(height uint32) []interfaces.IHash {
+		dblock, err := state.DB.FetchDBlockByHeight(height)
+		assert.Nil(t, err)
+		hashes := []interfaces.IHash{}
+		for _, dbEntry := range dblock.GetEBlockDBEntries() {
+			if !dbEntry.GetChainID().IsSameAs(chainID) {
+				continue
+			}
+			eblock, err := state.DB.FetchEBlock(dbEntry.GetKeyMR())
+			assert.Nil(t, err)
+			for _, hash := range eblock.GetEntryHashes() {
+				if !hash.IsMinuteMarker() {
+					hashes = append(hashes, hash)
+				}
+			}
+		}
+		return hashes
+	}
+
+	head, err := state.DB.FetchDBlockHead()
+	assert.Nil(t, err)
+	height := head.GetDatabaseHeight()
+	old := entryHashes(2)
+	recent := entryHashes(height)
+	assert.NotEmpty(t, old)
+	assert.NotEmpty(t, recent)
+
+	// keep the entries of the last block only
+	state.PruneEntriesAfter = 1
+	state.EntryBlockDBHeightComplete = height
+	state2.EntryPruneInterval = 10 * time.Millisecond
+	go state.PruneEntries()
+	for i := 0; i < 500; i++ {
+		next, err := state.DB.FetchEntryPruneHeight()
+		assert.Nil(t, err)
+		if next >= height {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	for _, hash := range old {
+		_, jErr := HandleV2Entry(state, &HashRequest{Hash: hash.String()})
+		assert.Equal(t, NewEntryPrunedError(), jErr)
+		_, jErr = HandleV2RawData(state, &HashRequest{Hash: hash.String()})
+		assert.Equal(t, NewEntryPrunedError(), jErr)
+
+		// pruned entries are not served to other nodes
+		data, _, err := state.LoadDataByHash(hash)
+		assert.Nil(t, data)
+		assert.NotNil(t, err)
+	}
+	for _, hash := range recent {
+		r, jErr := HandleV2Entry(state, &HashRequest{Hash: hash.String()})
+		assert.Nil(t, jErr)
+		assert.Equal(t, chainID.String(), r.(*EntryResponse).ChainID)
+		data, _, err := state.LoadDataByHash(hash)
+		assert.NotNil(t, data)
+		assert.Nil(t, err)
+	}
+
+	// the chain can still be read, with the pruned entries flagged
+	r, jErr := HandleV2ChainEntries(state, &ChainEntriesRequest{ChainID: chainID.String()})
+	assert.Nil(t, jErr)
+	entries := map[string]ChainEntry{}
+	for _, entry := range r.(*ChainEntriesResponse).Entries {
+		entries[entry.EntryHash] = entry
+	}
+	for _, hash := range old {
+		assert.Equal(t, true, entries[hash.String()].Pruned)
+		assert.Empty(t, entries[hash.String()].Content)
+	}
+	for _, hash := range recent {
+		assert.Equal(t, false, entries[hash.String()].Pruned)
+		assert.NotEmpty(t, entries[hash.String()].Content)
+	}
+
+	// the blocks themselves are kept
+	dblock, err := state.DB.FetchDBlockByHeight(2)
+	assert.Nil(t, err)
+	assert.NotNil(t, dblock)
+
+	_, jErr = HandleV2Entry(state, &HashRequest{Hash: primitives.NewHash([]byte("unknown")).String()})
+	assert.Equal(t, NewEntryNotFoundError(), jErr)
+}
+
+func TestHandleV2ReadOnly(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestState()
+	state.ApiReadOnly = true
+
+	for _, method := range []string{"commit-chain", "commit-entry", "reveal-chain", "reveal-entry", "factoid-submit", "send-raw-message", "replay-from-height"} {
+		_, jErr := HandleV2JSONRequest(state, primitives.NewJSON2Request(method, 1, nil))
+		assert.Equal(t, NewReadOnlyError(method), jErr, method)
+	}
+	_, jErr := HandleDebugRequest(state, primitives.NewJSON2Request("holding-queue", 1, nil))
+	assert.Equal(t, NewReadOnlyError("holding-queue"), jErr)
+
+	resp, jErr := HandleV2JSONRequest(state, primitives.NewJSON2Request("heights", 1, nil))
+	assert.Nil(t, jErr)
+	assert.NotNil(t, resp.Result)
+
+	// the disabled methods are not advertised either
+	r, jErr := HandleV2Discover(state, nil)
+	assert.Nil(t, jErr)
+	for _, m := range r.(*OpenRPCDocument).Methods {
+		assert.NotEqual(t, "factoid-submit", m.Name)
+	}
+}
+
+func TestHandleV2JSONRequestMetrics(t *testing.T) {
+	state := testHelper.CreateAndPopulateTestState()
+
+	calls := testutil.ToFloat64(HandleV2APIMethodCalls.WithLabelValues("heights"))
+	_, jErr := HandleV2JSONRequest(state, primitives.NewJSON2Request("heights", 1, nil))
+	assert.Nil(t, jErr)
+	assert.Equal(t, calls+1, testutil.ToFloat64(HandleV2APIMethodCalls.WithLabelValues("heights")))
+
+	errs := testutil.ToFloat64(HandleV2APIMethodErrors.WithLabelValues("entry", "-32602"))
+	_, jErr = HandleV2JSONRequest(state, primitives.NewJSON2Request("entry", 1, HashRequest{Hash: "nope"}))
+	assert.NotNil(t, jErr)
+	assert.Equal(t, errs+1, testutil.ToFloat64(HandleV2APIMethodErrors.WithLabelValues("entry", "-32602")))
+
+	// unknown methods share one label
+	unknown := testutil.ToFloat64(HandleV2APIMethodCalls.WithLabelValues("unknown"))
+	HandleV2JSONRequest(state, primitives.NewJSON2Request("no-such-method", 1, nil))
+	assert.Equal(t, unknown+1, testutil.ToFloat64(HandleV2APIMethodCalls.WithLabelValues("unknown")))
+
+	assert.Equal(t, float64(0), testutil.ToFloat64(HandleV2APIInFlight))
+}
+
 func TestRegisterPrometheus(t *testing.T) {
 	RegisterPrometheus()
 	RegisterPrometheus()
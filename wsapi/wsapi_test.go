@@ -131,6 +131,47 @@ func TestAuthenticatedUnauthorizedRequest(t *testing.T) {
 	}
 }
 
+func TestReadOnlyWithDebugPort(t *testing.T) {
+	globals.Params.NetworkName = "LOCAL"
+
+	state := testHelper.CreateAndPopulateTestState()
+	state.ApiReadOnly = true
+	state.DebugApiPort = 18093
+	state.SetPort(18092)
+	delayedStart(t, state)
+	waitUntilStarted(t, "http://localhost:18093/", time.Second*5)
+
+	cases := map[string]struct {
+		Method   string
+		Url      string
+		Expected int
+		Body     io.Reader
+	}{
+		"v1Write":       {"POST", "http://localhost:18092/v1/commit-entry/", http.StatusForbidden, body("")},
+		"v1Read":        {"GET", "http://localhost:18092/v1/properties/", http.StatusOK, nil},
+		"v2Write":       {"POST", "http://localhost:18092/v2", http.StatusBadRequest, body(primitives.NewJSON2Request("commit-entry", 0, ""))},
+		"v2Read":        {"POST", "http://localhost:18092/v2", http.StatusOK, body(primitives.NewJSON2Request("properties", 0, ""))},
+		"debugOnApi":    {"POST", "http://localhost:18092/debug", http.StatusNotFound, body(primitives.NewJSON2Request("holding-queue", 0, ""))},
+		"debugReadOnly": {"POST", "http://localhost:18093/debug", http.StatusBadRequest, body(primitives.NewJSON2Request("holding-queue", 0, ""))},
+	}
+
+	client := &http.Client{}
+	for name, testCase := range cases {
+		t.Logf("test case '%s'", name)
+		request, err := http.NewRequest(testCase.Method, testCase.Url, testCase.Body)
+		response, err := client.Do(request)
+
+		if err != nil {
+			t.Errorf("test '%s' failed: %v \nresponse: %v", name, err, response)
+		} else if response == nil {
+			t.Errorf("test '%s' failed: response == nil", name)
+		} else if testCase.Expected != response.StatusCode {
+			body, _ := ioutil.ReadAll(response.Body)
+			t.Errorf("test '%s' failed: wrong status code expected '%d' != actual '%d', body: %s", name, testCase.Expected, response.StatusCode, string(body))
+		}
+	}
+}
+
 func body(content interface{}) io.Reader {
 	payload, _ := json.Marshal(content)
 	body := bytes.NewBuffer(payload)